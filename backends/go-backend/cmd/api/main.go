@@ -6,9 +6,11 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -18,6 +20,13 @@ import (
 	"github.com/carterperez-dev/templates/go-backend/internal/auth"
 	"github.com/carterperez-dev/templates/go-backend/internal/config"
 	"github.com/carterperez-dev/templates/go-backend/internal/core"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/audit"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/authlock"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/clientip"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/logging"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/mailer"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/policy"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/quota"
 	"github.com/carterperez-dev/templates/go-backend/internal/health"
 	"github.com/carterperez-dev/templates/go-backend/internal/middleware"
 	"github.com/carterperez-dev/templates/go-backend/internal/server"
@@ -30,14 +39,130 @@ const (
 
 func main() {
 	configPath := flag.String("config", "config.yaml", "path to config file")
+	rotateKeys := flag.Bool("rotate-keys", false, "rotate JWT signing keys and exit")
+	forcePurgeUser := flag.String("force-purge-user", "", "hard-delete a soft-deleted user by id and exit, ignoring its grace period")
+	extendGracePeriod := flag.String("extend-grace-period", "", "extend a soft-deleted user's grace period, as <user-id>=<duration> (e.g. 550e8400-...=720h), and exit")
 	flag.Parse()
 
+	if *rotateKeys {
+		if err := runRotateKeys(*configPath); err != nil {
+			slog.Error("key rotation failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *forcePurgeUser != "" {
+		if err := runForcePurgeUser(*configPath, *forcePurgeUser); err != nil {
+			slog.Error("force purge failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *extendGracePeriod != "" {
+		if err := runExtendGracePeriod(*configPath, *extendGracePeriod); err != nil {
+			slog.Error("extend grace period failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := run(*configPath); err != nil {
 		slog.Error("application error", "error", err)
 		os.Exit(1)
 	}
 }
 
+// runRotateKeys drives the `-rotate-keys` CLI flag: it loads config,
+// constructs a JWTManager against the existing keyset, triggers one
+// rotation, and exits, so operators can force a rotation outside the
+// RotationInterval without restarting the server.
+func runRotateKeys(configPath string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+
+	jwtManager, err := auth.NewJWTManager(cfg.JWT)
+	if err != nil {
+		return err
+	}
+
+	if err := jwtManager.RotateKeys(context.Background()); err != nil {
+		return err
+	}
+
+	slog.Info("jwt signing keys rotated", "new_primary", jwtManager.GetKeyID())
+	return nil
+}
+
+// runForcePurgeUser drives the `-force-purge-user` CLI flag: it
+// hard-deletes userID immediately, the same way PurgeWorker would once its
+// grace period lapsed, for an operator closing out a GDPR erasure request
+// without waiting on PurgeInterval.
+func runForcePurgeUser(configPath, userID string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	db, err := core.NewDatabase(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close() //nolint:errcheck // best-effort cleanup on CLI exit
+
+	userRepo := user.NewRepository(db.DB)
+
+	if err := userRepo.HardDelete(ctx, userID); err != nil {
+		return err
+	}
+
+	slog.Info("user force-purged", "user_id", userID)
+	return nil
+}
+
+// runExtendGracePeriod drives the `-extend-grace-period` CLI flag: it
+// re-runs SoftDelete with a fresh grace period so a soft-deleted user gets
+// more time before PurgeWorker would otherwise hard-delete it, for an
+// operator fielding a late undo-delete request. spec is "<user-id>=<duration>".
+func runExtendGracePeriod(configPath, spec string) error {
+	userID, durationStr, ok := strings.Cut(spec, "=")
+	if !ok {
+		return fmt.Errorf("invalid -extend-grace-period value %q: expected <user-id>=<duration>", spec)
+	}
+
+	gracePeriod, err := time.ParseDuration(durationStr)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", durationStr, err)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	db, err := core.NewDatabase(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close() //nolint:errcheck // best-effort cleanup on CLI exit
+
+	userRepo := user.NewRepository(db.DB)
+
+	if err := userRepo.ExtendGracePeriod(ctx, userID, gracePeriod); err != nil {
+		return err
+	}
+
+	slog.Info("grace period extended", "user_id", userID, "grace_period", gracePeriod)
+	return nil
+}
+
 //nolint:funlen // bootstrap code is inherently verbose
 func run(configPath string) error {
 	ctx, stop := signal.NotifyContext(
@@ -61,6 +186,12 @@ func run(configPath string) error {
 		"environment", cfg.App.Environment,
 	)
 
+	calibrated := core.CalibrateArgon2id(250 * time.Millisecond)
+	logger.Info("argon2id calibrated",
+		"memory_kib", calibrated.Memory(),
+		"time", calibrated.Time(),
+	)
+
 	var telemetry *core.Telemetry
 	if cfg.Otel.Enabled {
 		tel, telErr := core.NewTelemetry(ctx, cfg.Otel, cfg.App)
@@ -99,22 +230,200 @@ func run(configPath string) error {
 		"algorithm", "ES256",
 		"key_id", jwtManager.GetKeyID(),
 	)
+	go jwtManager.StartRotationLoop(ctx)
 
 	userRepo := user.NewRepository(db.DB)
 	userSvc := user.NewService(userRepo)
 	userHandler := user.NewHandler(userSvc)
 
+	quotaLimiter := quota.NewLimiter(redis.Client, quotaPolicySet(cfg.RateLimit.Tiers))
+	userSvc.SetTierChangeNotifier(quotaLimiter)
+	userSvc.SetSoftDeleteConfig(cfg.SoftDelete)
+
+	purgeWorker := user.NewPurgeWorker(userRepo, redis.Client, cfg.SoftDelete)
+	go purgeWorker.Run(ctx)
+
+	ipResolver, err := clientip.NewResolver(clientip.Config{
+		TrustedProxies: cfg.ClientIP.TrustedProxies,
+		Headers:        cfg.ClientIP.Headers,
+	})
+	if err != nil {
+		logger.Error("invalid client_ip config", "error", err)
+		os.Exit(1)
+	}
+
 	authRepo := auth.NewRepository(db.DB)
-	authSvc := auth.NewService(authRepo, jwtManager, userSvc, redis.Client)
-	authHandler := auth.NewHandler(authSvc)
+	authSvc := auth.NewService(authRepo, jwtManager, userSvc, redis.Client, cfg.App.Name)
+	authHandler := auth.NewHandler(authSvc, ipResolver)
+
+	janitor := auth.NewJanitor(authRepo, redis.Client, cfg.Janitor)
+	go janitor.Run(ctx)
+
+	if telemetry != nil {
+		authSvc.WithTelemetry(telemetry)
+		if err := telemetry.RegisterDBPoolGauges(db); err != nil {
+			logger.Warn("failed to register db pool gauges", "error", err)
+		}
+	}
+
+	passkeyRepo := auth.NewPasskeyRepository(db.DB)
+	passkeySvc := auth.NewPasskeyService(
+		passkeyRepo,
+		redis.Client,
+		cfg.App.Name,
+		cfg.App.Name,
+		cfg.CORS.AllowedOrigins[0],
+	)
+	userSvc.SetPasskeyProvider(passkeySvc)
+	authHandler = authHandler.WithPasskeys(passkeySvc)
+
+	identityLinkRepo := auth.NewIdentityLinkRepository(db.DB)
+	authSvc.WithIdentityLinks(identityLinkRepo)
+	userSvc.SetIdentityLinksProvider(authSvc)
+
+	passwordResetRepo := auth.NewPasswordResetRepository(db.DB)
+	authSvc.WithPasswordReset(passwordResetRepo, newMailer(cfg.Mailer), auth.PasswordResetConfig{
+		TokenTTL: cfg.PasswordReset.TokenTTL,
+		IPLimit: auth.RateWindow{
+			RequestsPerMinute: cfg.PasswordReset.IPLimit.RequestsPerMinute,
+			Burst:             cfg.PasswordReset.IPLimit.Burst,
+		},
+		EmailLimit: auth.RateWindow{
+			RequestsPerMinute: cfg.PasswordReset.EmailLimit.RequestsPerMinute,
+			Burst:             cfg.PasswordReset.EmailLimit.Burst,
+		},
+	})
+
+	emailVerificationRepo := auth.NewEmailVerificationRepository(db.DB)
+	authSvc.WithEmailVerification(emailVerificationRepo, auth.EmailVerificationConfig{
+		TokenTTL: cfg.EmailVerification.TokenTTL,
+		RateLimit: auth.RateWindow{
+			RequestsPerMinute: cfg.EmailVerification.RateLimit.RequestsPerMinute,
+			Burst:             cfg.EmailVerification.RateLimit.Burst,
+		},
+	})
 
-	healthHandler := health.NewHandler(db, redis)
+	auditLogger := audit.NewLogger(db.DB)
+	userSvc.SetSessionRevoker(authSvc)
+	userSvc.SetImpersonationIssuer(authSvc)
+	userSvc.SetAuditLogger(auditLogger)
+
+	oauthStates := auth.NewOAuthStateStore(redis.Client)
+
+	var identityProviders []auth.IdentityProvider
+
+	if cfg.OAuth.Google.ClientID != "" {
+		googleProvider, googleErr := auth.NewGoogleProvider(
+			ctx,
+			cfg.OAuth.Google.ClientID,
+			cfg.OAuth.Google.ClientSecret,
+			cfg.OAuth.BaseRedirectURL+"/v1/auth/oidc/google/callback",
+			oauthStates,
+		)
+		if googleErr != nil {
+			logger.Warn("failed to initialize google oauth provider", "error", googleErr)
+		} else {
+			identityProviders = append(identityProviders, googleProvider)
+		}
+	}
+
+	if cfg.OAuth.GitHub.ClientID != "" {
+		identityProviders = append(identityProviders, auth.NewGitHubProvider(
+			auth.OAuthProviderConfig{
+				ClientID:     cfg.OAuth.GitHub.ClientID,
+				ClientSecret: cfg.OAuth.GitHub.ClientSecret,
+				RedirectURL:  cfg.OAuth.BaseRedirectURL + "/v1/auth/oidc/github/callback",
+				Scopes:       []string{"read:user", "user:email"},
+			},
+			oauthStates,
+		))
+	}
+
+	for _, connector := range cfg.OAuth.Connectors {
+		provider, connErr := auth.NewDiscoveredOIDCProvider(
+			ctx,
+			connector.ID,
+			connector.ClientID,
+			connector.ClientSecret,
+			cfg.OAuth.BaseRedirectURL+"/v1/auth/oidc/"+connector.ID+"/callback",
+			connector.DiscoveryURL,
+			connector.Scopes,
+			oauthStates,
+		)
+		if connErr != nil {
+			logger.Warn("failed to initialize oidc connector",
+				"connector", connector.ID,
+				"error", connErr,
+			)
+			continue
+		}
+		identityProviders = append(identityProviders, provider)
+	}
+
+	if len(identityProviders) > 0 {
+		authHandler = authHandler.WithIdentityProviders(auth.NewProviderRegistry(identityProviders...))
+		logger.Info("social login providers enabled", "count", len(identityProviders))
+	}
+
+	deviceRepo := auth.NewDeviceAuthorizationRepository(db.DB)
+	deviceSvc := auth.NewDeviceService(deviceRepo, redis.Client, auth.DeviceConfig{
+		CodeTTL:         cfg.DeviceAuth.CodeTTL,
+		VerificationURI: cfg.DeviceAuth.VerificationURI,
+	})
+	authHandler = authHandler.WithDeviceAuthorization(deviceSvc)
+
+	apiTokenRepo := auth.NewAPITokenRepository(db.DB)
+	apiTokenSvc := auth.NewAPITokenService(apiTokenRepo, auth.APITokenConfig{
+		LastUsedFlushInterval: cfg.APIToken.LastUsedFlushInterval,
+	})
+	authHandler = authHandler.WithAPITokens(apiTokenSvc)
+	go apiTokenSvc.StartLastUsedFlusher(ctx)
+
+	oauthClientRepo := auth.NewClientRepository(db.DB)
+	oauthProviderCfg := auth.OAuthProviderConfig{
+		BaseURL:       cfg.OAuthProvider.BaseURL,
+		AuthCodeTTL:   cfg.OAuthProvider.AuthCodeTTL,
+		IDTokenExpire: cfg.OAuthProvider.IDTokenExpire,
+	}
+	authSvc.WithOAuthProvider(oauthClientRepo, oauthProviderCfg)
+	authHandler = authHandler.WithOAuthProvider(oauthClientRepo, oauthProviderCfg)
+
+	healthRegistry := health.NewRegistry()
+	healthRegistry.Register("database", db, health.WithTimeout(2*time.Second), health.WithCritical(true), health.WithCacheTTL(5*time.Second))
+	healthRegistry.Register("redis", redis, health.WithTimeout(2*time.Second), health.WithCritical(true), health.WithCacheTTL(5*time.Second))
+	healthRegistry.Start(ctx)
+
+	healthHandler := health.NewHandler(healthRegistry)
+
+	metrics := core.NewMetrics()
+
+	policyRegistry, err := policy.NewRegistry(cfg.Policies)
+	if err != nil {
+		return err
+	}
+	go reloadPoliciesOnSIGHUP(ctx, configPath, policyRegistry, logger)
+
+	authLockStore := authlock.NewStore(redis.Client, authlock.Config{
+		Attempts:    cfg.AuthRateLimit.Attempts,
+		Window:      cfg.AuthRateLimit.Window,
+		BaseLockout: cfg.AuthRateLimit.BaseLockout,
+		MaxLockout:  cfg.AuthRateLimit.MaxLockout,
+		TrackBy:     authlock.TrackBy(cfg.AuthRateLimit.TrackBy),
+	})
+
+	rateLimitStats := middleware.NewRateLimitStats(redis.Client)
 
 	adminHandler := admin.NewHandler(admin.HandlerConfig{
 		DBStats:    db.Stats,
 		RedisStats: redis.PoolStats,
 		DBPing:     db.Ping,
 		RedisPing:  redis.Ping,
+		Metrics:    metrics,
+		MetricsCfg: cfg.Metrics,
+		Quota:      quotaLimiter,
+		Policies:   policyRegistry,
+		Lockouts:   authLockStore,
+		RLStats:    rateLimitStats,
 	})
 
 	srv := server.New(server.Config{
@@ -133,26 +442,45 @@ func run(configPath string) error {
 				cfg.RateLimit.Requests,
 				cfg.RateLimit.Burst,
 			),
+			KeyFunc:  middleware.KeyByIPWith(ipResolver),
 			FailOpen: true,
+			Stats:    rateLimitStats,
+			Recorder: metrics,
 		}).Handler,
 	)
 	router.Use(middleware.SecurityHeaders(cfg.App.Environment == "production"))
 	router.Use(middleware.CORS(cfg.CORS))
+	router.Use(middleware.Metrics(metrics))
+	if telemetry != nil {
+		router.Use(middleware.Tracing(telemetry.Tracer))
+		router.Use(middleware.OtelMetrics(telemetry.Meter))
+	}
 
 	healthHandler.RegisterRoutes(router)
+	adminHandler.RegisterMetricsRoute(router)
 
 	router.Get("/.well-known/jwks.json", jwtManager.GetJWKSHandler())
+	router.Get("/.well-known/openid-configuration", authHandler.OIDCDiscovery)
 
-	authenticator := middleware.Authenticator(jwtManager)
+	authenticator := middleware.Authenticator(jwtManager, redis.Client, cfg.JWT.IdleTimeout, authSvc, apiTokenSvc)
 	adminOnly := middleware.RequireAdmin
+	loginThrottle := middleware.LoginThrottle(authLockStore, ipResolver)
+	reauth := middleware.RequireReauth(cfg.JWT.ReauthWindow, true)
+	reauthStrict := middleware.RequireReauth(cfg.JWT.ReauthWindow, false)
+
+	healthHandler.RegisterDetailRoute(router, authenticator, adminOnly)
 
 	router.Route("/v1", func(r chi.Router) {
-		authHandler.RegisterRoutes(r, authenticator)
+		r.Use(middleware.RateLimit(quotaLimiter))
+
+		authHandler.RegisterRoutes(r, authenticator, loginThrottle, reauth, reauthStrict)
+		authHandler.RegisterOAuthRoutes(r, authenticator)
+		authHandler.RegisterOAuthAdminRoutes(r, authenticator, adminOnly)
 
 		r.Post("/users", authHandler.Register)
 
 		userHandler.RegisterRoutes(r, authenticator)
-		userHandler.RegisterAdminRoutes(r, authenticator, adminOnly)
+		userHandler.RegisterAdminRoutes(r, authenticator, adminOnly, reauthStrict)
 		adminHandler.RegisterRoutes(r, authenticator, adminOnly)
 	})
 
@@ -196,26 +524,73 @@ func run(configPath string) error {
 	return nil
 }
 
-func setupLogger(cfg config.LogConfig) *slog.Logger {
-	var handler slog.Handler
-
-	level := slog.LevelInfo
-	switch cfg.Level {
-	case "debug":
-		level = slog.LevelDebug
-	case "warn":
-		level = slog.LevelWarn
-	case "error":
-		level = slog.LevelError
+// newMailer selects core/mailer's backend from cfg.Driver, defaulting to
+// the log driver so a deployment that hasn't configured SMTP yet still
+// starts up rather than failing to send password-reset email.
+func newMailer(cfg config.MailerConfig) mailer.Mailer {
+	switch cfg.Driver {
+	case "smtp":
+		return mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:        cfg.SMTP.Host,
+			Port:        cfg.SMTP.Port,
+			Username:    cfg.SMTP.Username,
+			Password:    cfg.SMTP.Password,
+			FromAddress: cfg.FromAddress,
+		})
+	case "noop":
+		return mailer.NewNoopMailer()
+	default:
+		return mailer.NewLogMailer()
 	}
+}
 
-	opts := &slog.HandlerOptions{Level: level}
+// quotaPolicySet converts the config-driven tier policies into the
+// core/quota.PolicySet the Limiter enforces against.
+func quotaPolicySet(tiers map[string]config.TierPolicyConfig) quota.PolicySet {
+	policies := make(quota.PolicySet, len(tiers))
+	for name, t := range tiers {
+		policies[name] = quota.Policy{
+			RequestsPerMinute: t.RequestsPerMinute,
+			Burst:             t.Burst,
+			Unlimited:         t.Unlimited,
+		}
+	}
+	return policies
+}
 
-	if cfg.Format == "json" {
-		handler = slog.NewJSONHandler(os.Stdout, opts)
-	} else {
-		handler = slog.NewTextHandler(os.Stdout, opts)
+// reloadPoliciesOnSIGHUP re-reads the `policies` section of configPath and
+// swaps it into registry each time the process receives SIGHUP, so an
+// operator can edit an authorization expression without restarting the
+// server. It runs until ctx is canceled at shutdown.
+func reloadPoliciesOnSIGHUP(ctx context.Context, configPath string, registry *policy.Registry, logger *slog.Logger) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sighup:
+			policies, err := config.LoadPolicies(configPath)
+			if err != nil {
+				logger.Error("policy reload failed", "error", err)
+				continue
+			}
+
+			if err := registry.Reload(policies); err != nil {
+				logger.Error("policy reload failed", "error", err)
+				continue
+			}
+
+			logger.Info("policies reloaded", "count", len(policies))
+		}
 	}
+}
 
-	return slog.New(handler)
+func setupLogger(cfg config.LogConfig) *slog.Logger {
+	return logging.New(logging.Config{
+		Level:  cfg.Level,
+		Format: cfg.Format,
+	})
 }
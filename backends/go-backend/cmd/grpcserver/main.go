@@ -0,0 +1,143 @@
+// AngelaMos | 2026
+// main.go
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/admin"
+	"github.com/carterperez-dev/templates/go-backend/internal/auth"
+	"github.com/carterperez-dev/templates/go-backend/internal/config"
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+	"github.com/carterperez-dev/templates/go-backend/internal/transport/grpc"
+	"github.com/carterperez-dev/templates/go-backend/internal/user"
+)
+
+func main() {
+	configPath := flag.String("config", "config.yaml", "path to config file")
+	flag.Parse()
+
+	if err := run(*configPath); err != nil {
+		slog.Error("application error", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run wires the same services cmd/api uses onto the gRPC transport instead
+// of chi, so the two binaries expose identical business logic over two
+// protocols.
+func run(configPath string) error {
+	ctx, stop := signal.NotifyContext(
+		context.Background(),
+		syscall.SIGINT,
+		syscall.SIGTERM,
+	)
+	defer stop()
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+
+	logger := setupLogger(cfg.Log)
+	slog.SetDefault(logger)
+
+	db, err := core.NewDatabase(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+
+	redis, err := core.NewRedis(ctx, cfg.Redis)
+	if err != nil {
+		return err
+	}
+
+	jwtManager, err := auth.NewJWTManager(cfg.JWT)
+	if err != nil {
+		return err
+	}
+
+	userRepo := user.NewRepository(db.DB)
+	userSvc := user.NewService(userRepo)
+
+	authRepo := auth.NewRepository(db.DB)
+	authSvc := auth.NewService(authRepo, jwtManager, userSvc, redis.Client, cfg.App.Name)
+
+	adminHandler := admin.NewHandler(admin.HandlerConfig{
+		DBStats:    db.Stats,
+		RedisStats: redis.PoolStats,
+		DBPing:     db.Ping,
+		RedisPing:  redis.Ping,
+	})
+
+	srv := grpc.NewServer(grpc.Config{
+		AuthSvc:      authSvc,
+		UserSvc:      userSvc,
+		AdminHandler: adminHandler,
+		Verifier:     jwtManager,
+	})
+
+	addr := fmt.Sprintf("%s:%d", cfg.GRPC.Host, cfg.GRPC.Port)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		logger.Info("grpc server listening", "address", addr)
+		errChan <- srv.Serve(lis)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		logger.Info("shutdown signal received")
+	}
+
+	srv.GracefulStop()
+
+	if err := redis.Close(); err != nil {
+		logger.Error("redis close error", "error", err)
+	}
+
+	if err := db.Close(); err != nil {
+		logger.Error("database close error", "error", err)
+	}
+
+	logger.Info("grpc server stopped")
+	return nil
+}
+
+func setupLogger(cfg config.LogConfig) *slog.Logger {
+	var handler slog.Handler
+
+	level := slog.LevelInfo
+	switch cfg.Level {
+	case "debug":
+		level = slog.LevelDebug
+	case "warn":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	if cfg.Format == "json" {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+
+	return slog.New(handler)
+}
@@ -6,13 +6,35 @@ package admin
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"net"
 	"net/http"
 	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/redis/go-redis/v9"
 
+	"github.com/carterperez-dev/templates/go-backend/internal/config"
 	"github.com/carterperez-dev/templates/go-backend/internal/core"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/authlock"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/policy"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/quota"
+	"github.com/carterperez-dev/templates/go-backend/internal/middleware"
+)
+
+// topLimitedUsersCount bounds how many entries GetSystemStats's quota
+// leaderboard reports.
+const topLimitedUsersCount = 10
+
+// rateLimitStatsDefaultWindowMinutes/TopOffendersCount bound
+// GetRateLimitStats's default query when the caller doesn't specify
+// window_minutes/top_n query params.
+const (
+	rateLimitStatsDefaultWindowMinutes = 5
+	rateLimitStatsDefaultTopOffenders  = 10
 )
 
 type AuthService interface {
@@ -25,6 +47,12 @@ type Handler struct {
 	redisPing  func(ctx context.Context) error
 	dbPing     func(ctx context.Context) error
 	authSvc    AuthService
+	metrics    *core.Metrics
+	metricsCfg config.MetricsConfig
+	quota      *quota.Limiter
+	policies   *policy.Registry
+	lockouts   *authlock.Store
+	rlStats    *middleware.RateLimitStats
 }
 
 type HandlerConfig struct {
@@ -33,6 +61,12 @@ type HandlerConfig struct {
 	RedisPing  func(ctx context.Context) error
 	DBPing     func(ctx context.Context) error
 	AuthSvc    AuthService
+	Metrics    *core.Metrics
+	MetricsCfg config.MetricsConfig
+	Quota      *quota.Limiter
+	Policies   *policy.Registry
+	Lockouts   *authlock.Store
+	RLStats    *middleware.RateLimitStats
 }
 
 func NewHandler(cfg HandlerConfig) *Handler {
@@ -42,6 +76,12 @@ func NewHandler(cfg HandlerConfig) *Handler {
 		redisPing:  cfg.RedisPing,
 		dbPing:     cfg.DBPing,
 		authSvc:    cfg.AuthSvc,
+		metrics:    cfg.Metrics,
+		metricsCfg: cfg.MetricsCfg,
+		quota:      cfg.Quota,
+		policies:   cfg.Policies,
+		lockouts:   cfg.Lockouts,
+		rlStats:    cfg.RLStats,
 	}
 }
 
@@ -57,12 +97,215 @@ func (h *Handler) RegisterRoutes(
 		r.Get("/stats/db", h.GetDatabaseStats)
 		r.Get("/stats/redis", h.GetRedisStats)
 		r.Get("/stats/runtime", h.GetRuntimeStats)
+		r.Get("/stats/security", h.GetSecurityStats)
+		r.Get("/stats/ratelimit", h.GetRateLimitStats)
+		r.Post("/security/calibrate", h.CalibrateKDF)
+		r.Post("/policies/test", h.TestPolicy)
+		r.Delete("/lockouts/{key}", h.ClearLockout)
+	})
+}
+
+// RegisterMetricsRoute mounts the Prometheus scrape endpoint outside the
+// JWT-protected /admin routes — scrapers don't carry a user session, so
+// access is instead gated by a config-driven bearer token and/or IP
+// allowlist. It is a no-op when metrics are disabled or unconfigured.
+func (h *Handler) RegisterMetricsRoute(r chi.Router) {
+	if h.metrics == nil || !h.metricsCfg.Enabled {
+		return
+	}
+
+	r.Get("/metrics", h.GetMetrics)
+}
+
+// GetMetrics serves the Prometheus exposition format after refreshing the
+// DB/Redis/runtime gauges from their live sources.
+func (h *Handler) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeMetricsRequest(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if h.dbStats != nil {
+		h.metrics.RecordDBStats(h.dbStats())
+	}
+	if h.redisStats != nil {
+		h.metrics.RecordRedisStats(h.redisStats())
+	}
+	h.metrics.RecordRuntimeStats()
+
+	h.metrics.Handler().ServeHTTP(w, r)
+}
+
+func (h *Handler) authorizeMetricsRequest(r *http.Request) bool {
+	if h.metricsCfg.BearerToken != "" {
+		authHeader := r.Header.Get("Authorization")
+		if strings.TrimPrefix(authHeader, "Bearer ") == h.metricsCfg.BearerToken {
+			return true
+		}
+	}
+
+	if len(h.metricsCfg.AllowedIPs) > 0 {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		for _, allowed := range h.metricsCfg.AllowedIPs {
+			if host == allowed {
+				return true
+			}
+		}
+	}
+
+	return h.metricsCfg.BearerToken == "" && len(h.metricsCfg.AllowedIPs) == 0
+}
+
+// GetSecurityStats reports the password hashing parameters currently in
+// effect, so operators can confirm a calibration took hold.
+func (h *Handler) GetSecurityStats(w http.ResponseWriter, r *http.Request) {
+	core.OK(w, SecurityStats{
+		DefaultAlgorithm: core.DefaultAlgorithm,
+	})
+}
+
+// CalibrateKDF re-tunes the argon2id parameters to hit a target hashing
+// latency on this host and installs them as the live argon2id hasher.
+func (h *Handler) CalibrateKDF(w http.ResponseWriter, r *http.Request) {
+	target := 250 * time.Millisecond
+	if raw := r.URL.Query().Get("target_ms"); raw != "" {
+		if ms, err := time.ParseDuration(raw + "ms"); err == nil {
+			target = ms
+		}
+	}
+
+	core.CalibrateArgon2id(target)
+
+	core.OK(w, SecurityStats{
+		DefaultAlgorithm: core.DefaultAlgorithm,
+	})
+}
+
+// TestPolicy evaluates a named policy against a caller-supplied
+// environment without requiring a matching request, so operators can
+// dry-run an expression edit before rolling it into a route.
+func (h *Handler) TestPolicy(w http.ResponseWriter, r *http.Request) {
+	if h.policies == nil {
+		core.NotFound(w, "policy")
+		return
+	}
+
+	var req TestPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	source, ok := h.policies.Source(req.Policy)
+	if !ok {
+		core.NotFound(w, "policy")
+		return
+	}
+
+	allowed, err := h.policies.Evaluate(req.Policy, policy.Env{
+		User:     req.User,
+		Request:  req.Request,
+		Resource: req.Resource,
+	})
+	if err != nil {
+		core.BadRequest(w, err.Error())
+		return
+	}
+
+	core.OK(w, TestPolicyResponse{
+		Allowed: allowed,
+		Source:  source,
 	})
 }
 
+// ClearLockout lifts a caller's progressive auth lockout before its TTL
+// expires naturally, e.g. support unlocking a user who failed login too
+// many times. key is an authlock.Store.Key value for the locked caller,
+// such as "email:a@b.com" or "email+ip:a@b.com:1.2.3.4", matching
+// cfg.AuthRateLimit.TrackBy.
+func (h *Handler) ClearLockout(w http.ResponseWriter, r *http.Request) {
+	if h.lockouts == nil {
+		core.NotFound(w, "lockout")
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+	if key == "" {
+		core.BadRequest(w, "lockout key required")
+		return
+	}
+
+	if err := h.lockouts.Clear(r.Context(), key); err != nil {
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+// GetRateLimitStats reports recent middleware.RateLimiter activity: requests
+// per minute and denial ratio per endpoint/tier, plus the top offending
+// limiter keys the Count-Min sketch estimates were denied most often.
+// window_minutes and top_n query params override the defaults.
+func (h *Handler) GetRateLimitStats(w http.ResponseWriter, r *http.Request) {
+	if h.rlStats == nil {
+		core.NotFound(w, "rate limit stats")
+		return
+	}
+
+	windowMinutes := rateLimitStatsDefaultWindowMinutes
+	if raw := r.URL.Query().Get("window_minutes"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil {
+			windowMinutes = n
+		}
+	}
+
+	topN := rateLimitStatsDefaultTopOffenders
+	if raw := r.URL.Query().Get("top_n"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil {
+			topN = n
+		}
+	}
+
+	summary, err := h.rlStats.Summary(r.Context(), windowMinutes, topN)
+	if err != nil {
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, summary)
+}
+
+// TestPolicyRequest carries the env a /admin/policies/test caller wants
+// a named policy evaluated against.
+type TestPolicyRequest struct {
+	Policy   string            `json:"policy" validate:"required"`
+	User     policy.UserEnv    `json:"user"`
+	Request  policy.RequestEnv `json:"request"`
+	Resource map[string]any    `json:"resource"`
+}
+
+type TestPolicyResponse struct {
+	Allowed bool   `json:"allowed"`
+	Source  string `json:"source"`
+}
+
+type SecurityStats struct {
+	DefaultAlgorithm string `json:"default_algorithm"`
+}
+
 func (h *Handler) GetSystemStats(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	core.OK(w, h.SystemStats(r.Context()))
+}
 
+// SystemStats assembles the same DB/Redis/runtime snapshot GetSystemStats
+// serves over REST. It lives here, rather than inline in the HTTP handler,
+// so the gRPC AdminService can report identical stats without re-pinging
+// anything itself.
+func (h *Handler) SystemStats(ctx context.Context) SystemStatsResponse {
 	dbHealthy := true
 	if h.dbPing != nil {
 		if err := h.dbPing(ctx); err != nil {
@@ -80,7 +323,7 @@ func (h *Handler) GetSystemStats(w http.ResponseWriter, r *http.Request) {
 	var memStats runtime.MemStats
 	runtime.ReadMemStats(&memStats)
 
-	response := SystemStatsResponse{
+	return SystemStatsResponse{
 		Database: DatabaseStatus{
 			Healthy: dbHealthy,
 			Stats:   h.getDBStats(),
@@ -97,9 +340,24 @@ func (h *Handler) GetSystemStats(w http.ResponseWriter, r *http.Request) {
 			MemSys:       memStats.Sys,
 			NumGC:        memStats.NumGC,
 		},
+		Quota: h.getQuotaStats(ctx),
 	}
+}
 
-	core.OK(w, response)
+// getQuotaStats reports middleware.RateLimit's per-tier hit rates and
+// top-limited users. It returns nil when quota tracking isn't configured,
+// so the "quota" field is simply omitted rather than serialized empty.
+func (h *Handler) getQuotaStats(ctx context.Context) *QuotaStats {
+	if h.quota == nil {
+		return nil
+	}
+
+	tiers, topUsers, err := h.quota.Stats(ctx, topLimitedUsersCount)
+	if err != nil {
+		return nil
+	}
+
+	return &QuotaStats{Tiers: tiers, TopLimitedUsers: topUsers}
 }
 
 func (h *Handler) GetDatabaseStats(w http.ResponseWriter, r *http.Request) {
@@ -165,6 +423,15 @@ type SystemStatsResponse struct {
 	Database DatabaseStatus `json:"database"`
 	Redis    RedisStatus    `json:"redis"`
 	Runtime  RuntimeStats   `json:"runtime"`
+	Quota    *QuotaStats    `json:"quota,omitempty"`
+}
+
+// QuotaStats surfaces middleware.RateLimit's per-tier hit rates and
+// top-limited users so operators can see quota pressure without querying
+// Redis directly.
+type QuotaStats struct {
+	Tiers           []quota.TierStats      `json:"tiers"`
+	TopLimitedUsers []quota.TopLimitedUser `json:"top_limited_users"`
 }
 
 type DatabaseStatus struct {
@@ -0,0 +1,365 @@
+// AngelaMos | 2026
+// api_token.go
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/logging"
+	"github.com/carterperez-dev/templates/go-backend/internal/middleware"
+)
+
+var (
+	ErrAPITokenNotFound = errors.New("api token not found")
+	ErrAPITokenRevoked  = errors.New("api token revoked")
+	ErrAPITokenExpired  = errors.New("api token expired")
+)
+
+const (
+	apiTokenIDBytes       = 9
+	apiTokenSecretBytes   = 32
+	apiTokenPrefix        = "pat_"
+	apiTokenHashAlgorithm = "blake2b"
+)
+
+// APITokenRepository persists issued personal access tokens, keyed by
+// their plaintext ID (the part of pat_<id>.<secret> before the dot),
+// since the secret's own salted hash can't serve as a unique index.
+type APITokenRepository interface {
+	Create(ctx context.Context, token *APIToken) error
+	FindByID(ctx context.Context, id string) (*APIToken, error)
+	ListForUser(ctx context.Context, userID string) ([]APIToken, error)
+	Revoke(ctx context.Context, userID, id string) error
+	TouchLastUsed(ctx context.Context, ids []string) error
+}
+
+type apiTokenRepository struct {
+	db core.DBTX
+}
+
+func NewAPITokenRepository(db core.DBTX) APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *APIToken) error {
+	query := `
+		INSERT INTO api_tokens (
+			id, user_id, name, secret_hash, scopes, expires_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)
+		RETURNING created_at`
+
+	err := r.db.GetContext(ctx, &token.CreatedAt, query,
+		token.ID, token.UserID, token.Name, token.SecretHash, token.Scopes, token.ExpiresAt,
+	)
+	if err != nil {
+		logging.FromContext(ctx).Error("create api token failed", "error", err)
+		return fmt.Errorf("create api token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *apiTokenRepository) FindByID(ctx context.Context, id string) (*APIToken, error) {
+	query := `
+		SELECT id, user_id, name, secret_hash, scopes, last_used_at,
+			expires_at, revoked_at, created_at
+		FROM api_tokens
+		WHERE id = $1`
+
+	var token APIToken
+	err := r.db.GetContext(ctx, &token, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("find api token: %w", ErrAPITokenNotFound)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("find api token failed", "error", err)
+		return nil, fmt.Errorf("find api token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r *apiTokenRepository) ListForUser(
+	ctx context.Context,
+	userID string,
+) ([]APIToken, error) {
+	query := `
+		SELECT id, user_id, name, secret_hash, scopes, last_used_at,
+			expires_at, revoked_at, created_at
+		FROM api_tokens
+		WHERE user_id = $1
+		ORDER BY created_at DESC`
+
+	var tokens []APIToken
+	err := r.db.SelectContext(ctx, &tokens, query, userID)
+	if err != nil {
+		logging.FromContext(ctx).Error("list api tokens failed", "error", err)
+		return nil, fmt.Errorf("list api tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *apiTokenRepository) Revoke(ctx context.Context, userID, id string) error {
+	query := `
+		UPDATE api_tokens
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		logging.FromContext(ctx).Error("revoke api token failed", "error", err)
+		return fmt.Errorf("revoke api token: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("revoke api token: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("revoke api token: %w", core.ErrNotFound)
+	}
+
+	return nil
+}
+
+// TouchLastUsed batches a set of token IDs into a single UPDATE, called
+// from APITokenService's flush loop rather than once per request.
+func (r *apiTokenRepository) TouchLastUsed(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `UPDATE api_tokens SET last_used_at = NOW() WHERE id = ANY($1)`
+
+	_, err := r.db.ExecContext(ctx, query, ids)
+	if err != nil {
+		logging.FromContext(ctx).Error("touch api token last_used_at failed", "error", err)
+		return fmt.Errorf("touch api token last_used_at: %w", err)
+	}
+
+	return nil
+}
+
+// APITokenConfig mirrors config.APITokenConfig; it's a separate type so
+// this package doesn't import internal/config.
+type APITokenConfig struct {
+	LastUsedFlushInterval time.Duration
+}
+
+// APITokenService issues and verifies pat_<id>.<secret> personal access
+// tokens: a credential a user mints once for a CI job or script, which
+// can then authenticate on every request without going through the
+// password-login or refresh-token-rotation flow. The secret is hashed
+// with the KDF registry's fast "blake2b" hasher rather than Argon2id —
+// Argon2id's deliberate slowness is right for a low-entropy human
+// password checked once at login, but wrong for a 256-bit random secret
+// verified on every API call, where the secret's own entropy (not hash
+// cost) is the defense.
+type APITokenService struct {
+	repo APITokenRepository
+	cfg  APITokenConfig
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+}
+
+func NewAPITokenService(repo APITokenRepository, cfg APITokenConfig) *APITokenService {
+	return &APITokenService{
+		repo:    repo,
+		cfg:     cfg,
+		pending: make(map[string]struct{}),
+	}
+}
+
+// Create mints a new token for userID, returning the stored record and the
+// one-time-visible bearer value (pat_<id>.<secret>). The secret never
+// touches storage in the clear; this return value is the only place it
+// exists outside the caller's hands.
+func (s *APITokenService) Create(
+	ctx context.Context,
+	userID, name string,
+	scopes []string,
+	ttl time.Duration,
+) (*APIToken, string, error) {
+	id, err := generateAPITokenID()
+	if err != nil {
+		return nil, "", fmt.Errorf("generate api token id: %w", err)
+	}
+
+	secret, err := core.GenerateSecureToken(apiTokenSecretBytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("generate api token secret: %w", err)
+	}
+
+	secretHash, err := core.HashWithAlgorithm(apiTokenHashAlgorithm, secret)
+	if err != nil {
+		return nil, "", fmt.Errorf("hash api token secret: %w", err)
+	}
+
+	token := &APIToken{
+		ID:         id,
+		UserID:     userID,
+		Name:       name,
+		SecretHash: secretHash,
+		Scopes:     scopes,
+	}
+
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	return token, apiTokenPrefix + id + "." + secret, nil
+}
+
+// Verify parses a pat_<id>.<secret> bearer value, looks up the token by
+// its plaintext ID, and checks the secret against the stored hash. A
+// valid token's ID is queued for a batched last_used_at update rather
+// than written synchronously on this request.
+func (s *APITokenService) Verify(ctx context.Context, bearer string) (*APIToken, error) {
+	id, secret, err := parseAPIToken(bearer)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.IsRevoked() {
+		return nil, ErrAPITokenRevoked
+	}
+
+	if token.IsExpired() {
+		return nil, ErrAPITokenExpired
+	}
+
+	valid, err := core.VerifyPassword(secret, token.SecretHash)
+	if err != nil {
+		return nil, fmt.Errorf("verify api token secret: %w", err)
+	}
+
+	if !valid {
+		return nil, fmt.Errorf("verify api token: %w", ErrAPITokenNotFound)
+	}
+
+	s.markSeen(token.ID)
+
+	return token, nil
+}
+
+func (s *APITokenService) markSeen(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[id] = struct{}{}
+}
+
+// StartLastUsedFlusher periodically writes every token ID seen by Verify
+// since the last tick, batching writes the same way the health registry
+// batches probes on its own ticker instead of per-request. A
+// non-positive LastUsedFlushInterval disables flushing entirely.
+func (s *APITokenService) StartLastUsedFlusher(ctx context.Context) {
+	if s.cfg.LastUsedFlushInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.LastUsedFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.flush(ctx)
+		}
+	}
+}
+
+func (s *APITokenService) flush(ctx context.Context) {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return
+	}
+
+	ids := make([]string, 0, len(s.pending))
+	for id := range s.pending {
+		ids = append(ids, id)
+	}
+	s.pending = make(map[string]struct{})
+	s.mu.Unlock()
+
+	if err := s.repo.TouchLastUsed(ctx, ids); err != nil {
+		logging.FromContext(ctx).Error("flush api token last_used_at failed", "error", err)
+	}
+}
+
+func (s *APITokenService) List(ctx context.Context, userID string) ([]APIToken, error) {
+	return s.repo.ListForUser(ctx, userID)
+}
+
+func (s *APITokenService) Revoke(ctx context.Context, userID, id string) error {
+	return s.repo.Revoke(ctx, userID, id)
+}
+
+func generateAPITokenID() (string, error) {
+	raw := make([]byte, apiTokenIDBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate random bytes: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// parseAPIToken splits a pat_<id>.<secret> bearer value into its id and
+// secret halves.
+func parseAPIToken(bearer string) (id, secret string, err error) {
+	trimmed := strings.TrimPrefix(bearer, apiTokenPrefix)
+
+	parts := strings.SplitN(trimmed, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("parse api token: %w", ErrAPITokenNotFound)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// IsAPIToken reports whether bearer looks like a personal access token
+// rather than a JWT, so Authenticator can route it to VerifyAPIToken
+// instead of its JWT verifier. It satisfies middleware.APITokenVerifier.
+func (s *APITokenService) IsAPIToken(bearer string) bool {
+	return strings.HasPrefix(bearer, apiTokenPrefix)
+}
+
+// VerifyAPIToken adapts Verify to middleware.APITokenVerifier, called by
+// Authenticator for any bearer value IsAPIToken accepted.
+func (s *APITokenService) VerifyAPIToken(
+	ctx context.Context,
+	bearer string,
+) (*middleware.APITokenClaims, error) {
+	token, err := s.Verify(ctx, bearer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &middleware.APITokenClaims{UserID: token.UserID, Scopes: token.Scopes}, nil
+}
@@ -0,0 +1,384 @@
+// AngelaMos | 2026
+// device.go
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+// DeviceAuthStatus is the lifecycle of one device authorization request,
+// per RFC 8628.
+type DeviceAuthStatus string
+
+const (
+	DeviceStatusPending  DeviceAuthStatus = "pending"
+	DeviceStatusApproved DeviceAuthStatus = "approved"
+	DeviceStatusDenied   DeviceAuthStatus = "denied"
+	DeviceStatusExpired  DeviceAuthStatus = "expired"
+)
+
+var (
+	ErrDeviceCodeNotFound         = errors.New("device code not found")
+	ErrDeviceUserCodeNotFound     = errors.New("user code not found or already claimed")
+	ErrDeviceAuthorizationPending = errors.New("authorization_pending")
+	ErrDeviceSlowDown             = errors.New("slow_down")
+	ErrDeviceAccessDenied         = errors.New("access_denied")
+	ErrDeviceCodeExpired          = errors.New("expired_token")
+)
+
+const (
+	deviceCodeBytes     = 32
+	userCodeLength      = 8
+	userCodeAlphabet    = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // excludes 0/O/1/I
+	defaultPollInterval = 5 * time.Second
+	slowDownIncrement   = 5 * time.Second
+	maxPollInterval     = 30 * time.Second
+)
+
+// DeviceAuthorization tracks one RFC 8628 device-flow authorization
+// request from POST /auth/device/code through to the CLI redeeming it at
+// POST /auth/device/token. Only the device code's hash is stored; the user
+// code is stored in the clear since it's short-lived, low-entropy, and
+// meant to be read aloud or typed by a human.
+type DeviceAuthorization struct {
+	ID             string           `db:"id"`
+	DeviceCodeHash string           `db:"device_code_hash"`
+	UserCode       string           `db:"user_code"`
+	Status         DeviceAuthStatus `db:"status"`
+	UserID         *string          `db:"user_id"`
+	ExpiresAt      time.Time        `db:"expires_at"`
+	CreatedAt      time.Time        `db:"created_at"`
+}
+
+func (d *DeviceAuthorization) IsExpired() bool {
+	return time.Now().After(d.ExpiresAt)
+}
+
+// DeviceAuthorizationRepository persists device-flow authorization
+// requests keyed by either the hashed device code (polled by the CLI) or
+// the plaintext user code (looked up from the browser verification page).
+type DeviceAuthorizationRepository interface {
+	Create(ctx context.Context, auth *DeviceAuthorization) error
+	FindByDeviceCodeHash(ctx context.Context, hash string) (*DeviceAuthorization, error)
+	FindByUserCode(ctx context.Context, userCode string) (*DeviceAuthorization, error)
+	Approve(ctx context.Context, userCode, userID string) error
+	Delete(ctx context.Context, id string) error
+}
+
+type deviceAuthorizationRepository struct {
+	db core.DBTX
+}
+
+func NewDeviceAuthorizationRepository(db core.DBTX) DeviceAuthorizationRepository {
+	return &deviceAuthorizationRepository{db: db}
+}
+
+func (r *deviceAuthorizationRepository) Create(
+	ctx context.Context,
+	auth *DeviceAuthorization,
+) error {
+	query := `
+		INSERT INTO device_authorizations (
+			id, device_code_hash, user_code, status, expires_at
+		) VALUES (
+			$1, $2, $3, $4, $5
+		)
+		RETURNING created_at`
+
+	err := r.db.GetContext(ctx, &auth.CreatedAt, query,
+		auth.ID, auth.DeviceCodeHash, auth.UserCode, auth.Status, auth.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create device authorization: %w", err)
+	}
+
+	return nil
+}
+
+func (r *deviceAuthorizationRepository) FindByDeviceCodeHash(
+	ctx context.Context,
+	hash string,
+) (*DeviceAuthorization, error) {
+	query := `
+		SELECT id, device_code_hash, user_code, status, user_id, expires_at, created_at
+		FROM device_authorizations
+		WHERE device_code_hash = $1`
+
+	var auth DeviceAuthorization
+	err := r.db.GetContext(ctx, &auth, query, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("find device authorization: %w", ErrDeviceCodeNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find device authorization: %w", err)
+	}
+
+	return &auth, nil
+}
+
+func (r *deviceAuthorizationRepository) FindByUserCode(
+	ctx context.Context,
+	userCode string,
+) (*DeviceAuthorization, error) {
+	query := `
+		SELECT id, device_code_hash, user_code, status, user_id, expires_at, created_at
+		FROM device_authorizations
+		WHERE user_code = $1`
+
+	var auth DeviceAuthorization
+	err := r.db.GetContext(ctx, &auth, query, userCode)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("find device authorization: %w", ErrDeviceUserCodeNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find device authorization: %w", err)
+	}
+
+	return &auth, nil
+}
+
+// Approve binds userID to the pending, unexpired authorization for
+// userCode. It's a no-op error if the code is unknown, expired, or already
+// resolved, so a user can't replay an old or stolen code to approve a new
+// session.
+func (r *deviceAuthorizationRepository) Approve(
+	ctx context.Context,
+	userCode, userID string,
+) error {
+	query := `
+		UPDATE device_authorizations
+		SET status = $3, user_id = $2
+		WHERE user_code = $1 AND status = $4 AND expires_at > NOW()`
+
+	result, err := r.db.ExecContext(ctx, query,
+		userCode, userID, DeviceStatusApproved, DeviceStatusPending,
+	)
+	if err != nil {
+		return fmt.Errorf("approve device authorization: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("approve device authorization: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("approve device authorization: %w", ErrDeviceUserCodeNotFound)
+	}
+
+	return nil
+}
+
+// Delete removes the authorization once it's been redeemed for tokens, so
+// the device code can't be polled a second time.
+func (r *deviceAuthorizationRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM device_authorizations WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("delete device authorization: %w", err)
+	}
+
+	return nil
+}
+
+// DeviceConfig mirrors config.DeviceAuthConfig; it's a separate type so
+// this package doesn't import internal/config.
+type DeviceConfig struct {
+	CodeTTL         time.Duration
+	VerificationURI string
+}
+
+// DeviceService implements the RFC 8628 device authorization grant: a
+// polling client (CLI, TV, IoT) requests a code pair, a human approves it
+// from an authenticated browser tab, and the client's poll eventually
+// resolves to the same AuthResponse a password login would produce. Poll
+// throttling state lives in Redis since it's short-lived and per-device,
+// unlike the authorization record itself.
+type DeviceService struct {
+	repo  DeviceAuthorizationRepository
+	redis *redis.Client
+	cfg   DeviceConfig
+}
+
+func NewDeviceService(
+	repo DeviceAuthorizationRepository,
+	redisClient *redis.Client,
+	cfg DeviceConfig,
+) *DeviceService {
+	return &DeviceService{repo: repo, redis: redisClient, cfg: cfg}
+}
+
+// RequestCode mints a fresh device/user code pair and stores the pending
+// authorization, returning the device_code to the polling client. The
+// device_code is never stored in the clear, mirroring how refresh and
+// password reset tokens are only ever persisted as a hash.
+func (s *DeviceService) RequestCode(ctx context.Context) (*DeviceCodeResponse, error) {
+	deviceCode, err := core.GenerateSecureToken(deviceCodeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("generate device code: %w", err)
+	}
+
+	userCode, err := generateUserCode()
+	if err != nil {
+		return nil, fmt.Errorf("generate user code: %w", err)
+	}
+
+	auth := &DeviceAuthorization{
+		ID:             uuid.New().String(),
+		DeviceCodeHash: core.HashToken(deviceCode),
+		UserCode:       userCode,
+		Status:         DeviceStatusPending,
+		ExpiresAt:      time.Now().Add(s.cfg.CodeTTL),
+	}
+
+	if err := s.repo.Create(ctx, auth); err != nil {
+		return nil, fmt.Errorf("create device authorization: %w", err)
+	}
+
+	return &DeviceCodeResponse{
+		DeviceCode:      deviceCode,
+		UserCode:        formatUserCode(userCode),
+		VerificationURI: s.cfg.VerificationURI,
+		ExpiresIn:       int(s.cfg.CodeTTL / time.Second),
+		Interval:        int(defaultPollInterval / time.Second),
+	}, nil
+}
+
+// Verify approves the pending authorization identified by userCode on
+// behalf of userID, called from the authenticated browser verification
+// page after the human types the code shown on their device.
+func (s *DeviceService) Verify(ctx context.Context, userCode, userID string) error {
+	normalized := normalizeUserCode(userCode)
+
+	if err := s.repo.Approve(ctx, normalized, userID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Poll is called by the CLI on its polling interval. It enforces the
+// interval server-side: a client polling faster than its current interval
+// gets ErrDeviceSlowDown and the interval is escalated, exactly as RFC
+// 8628 recommends for abusive clients. A resolved (approved) authorization
+// is returned once and then deleted so the device code can't be redeemed
+// twice.
+func (s *DeviceService) Poll(
+	ctx context.Context,
+	deviceCode string,
+) (*DeviceAuthorization, error) {
+	hash := core.HashToken(deviceCode)
+
+	auth, err := s.repo.FindByDeviceCodeHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, ErrDeviceCodeNotFound) {
+			return nil, ErrDeviceCodeExpired
+		}
+		return nil, err
+	}
+
+	if auth.IsExpired() {
+		return nil, ErrDeviceCodeExpired
+	}
+
+	if err := s.enforceInterval(ctx, hash); err != nil {
+		return nil, err
+	}
+
+	switch auth.Status {
+	case DeviceStatusPending:
+		return nil, ErrDeviceAuthorizationPending
+	case DeviceStatusDenied:
+		return nil, ErrDeviceAccessDenied
+	case DeviceStatusExpired:
+		return nil, ErrDeviceCodeExpired
+	}
+
+	//nolint:errcheck // best-effort single-use cleanup; the tokens have already been issued
+	_ = s.repo.Delete(ctx, auth.ID)
+
+	return auth, nil
+}
+
+// enforceInterval checks hash's last poll time against its current
+// interval (escalated on prior violations), bumping the interval further
+// on yet another too-fast poll.
+func (s *DeviceService) enforceInterval(ctx context.Context, hash string) error {
+	intervalKey := "device:interval:" + hash
+	lastPollKey := "device:lastpoll:" + hash
+
+	interval := defaultPollInterval
+	if cached, err := s.redis.Get(ctx, intervalKey).Int64(); err == nil {
+		interval = time.Duration(cached) * time.Second
+	}
+
+	lastPoll, err := s.redis.Get(ctx, lastPollKey).Int64()
+	now := time.Now()
+
+	if err == nil && now.Before(time.Unix(lastPoll, 0).Add(interval)) {
+		escalated := interval + slowDownIncrement
+		if escalated > maxPollInterval {
+			escalated = maxPollInterval
+		}
+
+		//nolint:errcheck // best-effort throttle bookkeeping
+		_ = s.redis.Set(ctx, intervalKey, int64(escalated/time.Second), s.cfg.CodeTTL).Err()
+		//nolint:errcheck // best-effort throttle bookkeeping
+		_ = s.redis.Set(ctx, lastPollKey, now.Unix(), s.cfg.CodeTTL).Err()
+
+		return ErrDeviceSlowDown
+	}
+
+	//nolint:errcheck // best-effort throttle bookkeeping
+	_ = s.redis.Set(ctx, lastPollKey, now.Unix(), s.cfg.CodeTTL).Err()
+
+	return nil
+}
+
+// generateUserCode produces a userCodeLength-character code drawn from
+// userCodeAlphabet, which excludes the visually ambiguous 0/O/1/I so it
+// can be read off a screen and typed without transcription errors.
+func generateUserCode() (string, error) {
+	alphabetLen := big.NewInt(int64(len(userCodeAlphabet)))
+
+	code := make([]byte, userCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetLen)
+		if err != nil {
+			return "", fmt.Errorf("generate random index: %w", err)
+		}
+		code[i] = userCodeAlphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
+
+// formatUserCode renders the stored code as "XXXX-XXXX" for display.
+func formatUserCode(code string) string {
+	if len(code) != userCodeLength {
+		return code
+	}
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
+
+// normalizeUserCode undoes formatUserCode and case-folds the input, so a
+// human typing the code with or without its separator and in either case
+// still matches the stored value.
+func normalizeUserCode(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(code, "-", ""))
+	return strings.TrimSpace(code)
+}
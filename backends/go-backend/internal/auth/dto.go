@@ -7,9 +7,14 @@ import (
 	"time"
 )
 
+// LoginRequest supports two shapes: Email+Password for the initial login
+// attempt, or ChallengeToken+TOTPCode to complete the mfa_required
+// challenge an initial attempt returned for an account with TOTP enabled.
 type LoginRequest struct {
-	Email    string `json:"email"    validate:"required,email,max=255"`
-	Password string `json:"password" validate:"required,min=8,max=128"`
+	Email          string `json:"email,omitempty"           validate:"required_without=ChallengeToken,omitempty,email,max=255"`
+	Password       string `json:"password,omitempty"        validate:"required_without=ChallengeToken,omitempty,min=8,max=128"`
+	ChallengeToken string `json:"challenge_token,omitempty" validate:"required_without=Email"`
+	TOTPCode       string `json:"totp_code,omitempty"       validate:"required_without=Email"`
 }
 
 type RegisterRequest struct {
@@ -28,15 +33,17 @@ type TokenResponse struct {
 	TokenType    string    `json:"token_type"`
 	ExpiresIn    int       `json:"expires_in"`
 	ExpiresAt    time.Time `json:"expires_at"`
+	AuthMethod   string    `json:"auth_method"`
 }
 
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	Role      string    `json:"role"`
-	Tier      string    `json:"tier"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            string    `json:"id"`
+	Email         string    `json:"email"`
+	Name          string    `json:"name"`
+	Role          string    `json:"role"`
+	Tier          string    `json:"tier"`
+	CreatedAt     time.Time `json:"created_at"`
+	EmailVerified bool      `json:"email_verified"`
 }
 
 type AuthResponse struct {
@@ -44,6 +51,17 @@ type AuthResponse struct {
 	Tokens TokenResponse `json:"tokens"`
 }
 
+// LoginResult is Login's response. Most of the time *AuthResponse is set
+// and the other two fields are omitted; for an account with TOTP enabled,
+// the first call instead returns MFARequired with a ChallengeToken and
+// leaves *AuthResponse nil until the caller resubmits it alongside a
+// TOTPCode.
+type LoginResult struct {
+	*AuthResponse
+	MFARequired    bool   `json:"mfa_required,omitempty"`
+	ChallengeToken string `json:"challenge_token,omitempty"`
+}
+
 type SessionInfo struct {
 	ID        string    `json:"id"`
 	UserAgent string    `json:"user_agent"`
@@ -60,3 +78,313 @@ type ChangePasswordRequest struct {
 	CurrentPassword string `json:"current_password" validate:"required"`
 	NewPassword     string `json:"new_password"     validate:"required,min=8,max=128"`
 }
+
+type ReauthenticateRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token"        validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8,max=128"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// DeviceCodeResponse is the immediate response to POST /auth/device/code.
+type DeviceCodeResponse struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+type DeviceTokenRequest struct {
+	DeviceCode string `json:"device_code" validate:"required"`
+}
+
+type DeviceVerifyRequest struct {
+	UserCode string `json:"user_code" validate:"required"`
+}
+
+type BeginRegistrationRequest struct {
+	Email string `json:"email" validate:"omitempty,email,max=255"`
+}
+
+type BeginRegistrationResponse struct {
+	Challenge string `json:"challenge"`
+	RPID      string `json:"rp_id"`
+	RPName    string `json:"rp_name"`
+	UserID    string `json:"user_id"`
+	Timeout   int    `json:"timeout_ms"`
+}
+
+type FinishRegistrationRequest struct {
+	Name              string `json:"name"              validate:"required,min=1,max=100"`
+	Challenge         string `json:"challenge"          validate:"required"`
+	AttestationObject string `json:"attestation_object" validate:"required"`
+	ClientDataJSON    string `json:"client_data_json"   validate:"required"`
+}
+
+type BeginAssertionRequest struct {
+	Email string `json:"email" validate:"omitempty,email,max=255"`
+}
+
+type BeginAssertionResponse struct {
+	Challenge        string   `json:"challenge"`
+	RPID             string   `json:"rp_id"`
+	AllowCredentials []string `json:"allow_credentials,omitempty"`
+	Timeout          int      `json:"timeout_ms"`
+}
+
+type FinishAssertionRequest struct {
+	Challenge         string `json:"challenge"          validate:"required"`
+	CredentialID      string `json:"credential_id"      validate:"required"`
+	AuthenticatorData string `json:"authenticator_data" validate:"required"`
+	ClientDataJSON    string `json:"client_data_json"   validate:"required"`
+	Signature         string `json:"signature"          validate:"required"`
+}
+
+type PasskeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	AAGUID     string     `json:"aaguid"`
+	Transports []string   `json:"transports"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+type PasskeyListResponse struct {
+	Passkeys []PasskeyResponse `json:"passkeys"`
+}
+
+type IdentityLinkResponse struct {
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type IdentityLinkListResponse struct {
+	Links []IdentityLinkResponse `json:"links"`
+}
+
+func ToIdentityLinkResponseList(links []IdentityLink) []IdentityLinkResponse {
+	responses := make([]IdentityLinkResponse, len(links))
+	for i, link := range links {
+		responses[i] = IdentityLinkResponse{
+			Provider:  link.Provider,
+			Email:     link.Email,
+			CreatedAt: link.CreatedAt,
+		}
+	}
+	return responses
+}
+
+type CreateAPITokenRequest struct {
+	Name   string   `json:"name"             validate:"required,min=1,max=100"`
+	Scopes []string `json:"scopes,omitempty" validate:"omitempty,dive,min=1,max=100"`
+	TTL    int      `json:"ttl_seconds,omitempty" validate:"omitempty,min=0"`
+}
+
+// CreateAPITokenResponse includes Token, the one-time-visible pat_<id>.
+// <secret> bearer value; no other endpoint ever returns it again.
+type CreateAPITokenResponse struct {
+	APITokenResponse
+	Token string `json:"token"`
+}
+
+type APITokenResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type APITokenListResponse struct {
+	Tokens []APITokenResponse `json:"tokens"`
+}
+
+func ToAPITokenResponse(token *APIToken) APITokenResponse {
+	return APITokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scopes:     token.Scopes,
+		LastUsedAt: token.LastUsedAt,
+		ExpiresAt:  token.ExpiresAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+func ToAPITokenResponseList(tokens []APIToken) []APITokenResponse {
+	responses := make([]APITokenResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = ToAPITokenResponse(&token)
+	}
+	return responses
+}
+
+// EnrollTOTPResponse includes Secret and RecoveryCodes, each visible only
+// this once; ConfirmTOTP must be called with a code generated from Secret
+// before they actually take effect.
+type EnrollTOTPResponse struct {
+	Secret        string   `json:"secret"`
+	OTPAuthURL    string   `json:"otpauth_url"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// DisableTOTPRequest.Code isn't length-constrained since either a 6-digit
+// TOTP code or a recovery code is accepted.
+type DisableTOTPRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// OAuthAuthorizeRequest is GET /oauth/authorize's query string, read by an
+// already-authenticated resource owner's browser/SPA. Only the S256
+// code_challenge_method is accepted; see Service.Authorize.
+type OAuthAuthorizeRequest struct {
+	ClientID            string `validate:"required"`
+	RedirectURI         string `validate:"required"`
+	ResponseType        string `validate:"required,eq=code"`
+	Scope               string
+	State               string
+	CodeChallenge       string `validate:"required"`
+	CodeChallengeMethod string `validate:"required"`
+}
+
+// OAuthTokenRequest is POST /oauth/token's body, carrying whichever fields
+// GrantType needs: Code/RedirectURI/CodeVerifier for authorization_code,
+// RefreshToken for refresh_token, or neither for client_credentials (which
+// needs only ClientID/ClientSecret, required for every grant type).
+type OAuthTokenRequest struct {
+	GrantType    string `json:"grant_type"    validate:"required,oneof=authorization_code refresh_token client_credentials"`
+	ClientID     string `json:"client_id"     validate:"required"`
+	ClientSecret string `json:"client_secret" validate:"required"`
+	Code         string `json:"code,omitempty"`
+	RedirectURI  string `json:"redirect_uri,omitempty"`
+	CodeVerifier string `json:"code_verifier,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// OAuthIntrospectRequest requires client credentials alongside the token
+// per RFC 7662 §2.1, so introspection can't be used as an unauthenticated
+// oracle for a token's validity and subject.
+type OAuthIntrospectRequest struct {
+	Token        string `json:"token"         validate:"required"`
+	ClientID     string `json:"client_id"     validate:"required"`
+	ClientSecret string `json:"client_secret" validate:"required"`
+}
+
+// OAuthIntrospectResponse is RFC 7662's response shape. Every field but
+// Active is omitted when the token isn't active, since the spec forbids
+// leaking anything else about a token that isn't currently valid.
+type OAuthIntrospectResponse struct {
+	Active    bool   `json:"active"`
+	Sub       string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+}
+
+// OAuthRevokeRequest requires client credentials alongside the token per
+// RFC 7009 §2.1, for the same reason OAuthIntrospectRequest does.
+type OAuthRevokeRequest struct {
+	Token        string `json:"token"         validate:"required"`
+	ClientID     string `json:"client_id"     validate:"required"`
+	ClientSecret string `json:"client_secret" validate:"required"`
+}
+
+// OIDCDiscoveryDocument is GET /.well-known/openid-configuration's body,
+// per the OpenID Connect Discovery 1.0 spec, complementing the JWKS
+// endpoint JWTManager.GetJWKSHandler already serves.
+type OIDCDiscoveryDocument struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// CreateOAuthClientRequest is POST /v1/admin/oauth/clients's body.
+type CreateOAuthClientRequest struct {
+	Name          string   `json:"name"           validate:"required,min=1,max=100"`
+	RedirectURIs  []string `json:"redirect_uris"  validate:"required,min=1,dive,required,url"`
+	AllowedScopes []string `json:"allowed_scopes" validate:"required,min=1,dive,required"`
+	GrantTypes    []string `json:"grant_types"    validate:"required,min=1,dive,oneof=authorization_code refresh_token client_credentials"`
+}
+
+type UpdateOAuthClientRequest struct {
+	Name          string   `json:"name"           validate:"required,min=1,max=100"`
+	RedirectURIs  []string `json:"redirect_uris"  validate:"required,min=1,dive,required,url"`
+	AllowedScopes []string `json:"allowed_scopes" validate:"required,min=1,dive,required"`
+	GrantTypes    []string `json:"grant_types"    validate:"required,min=1,dive,oneof=authorization_code refresh_token client_credentials"`
+}
+
+type OAuthClientResponse struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	RedirectURIs  []string   `json:"redirect_uris"`
+	AllowedScopes []string   `json:"allowed_scopes"`
+	GrantTypes    []string   `json:"grant_types"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// CreateOAuthClientResponse includes ClientSecret, the one-time-visible
+// client secret; no other endpoint ever returns it again, mirroring
+// CreateAPITokenResponse.
+type CreateOAuthClientResponse struct {
+	OAuthClientResponse
+	ClientSecret string `json:"client_secret"`
+}
+
+type OAuthClientListResponse struct {
+	Clients []OAuthClientResponse `json:"clients"`
+}
+
+func ToOAuthClientResponse(client *OAuthClient) OAuthClientResponse {
+	return OAuthClientResponse{
+		ID:            client.ID,
+		Name:          client.Name,
+		RedirectURIs:  client.RedirectURIs,
+		AllowedScopes: client.AllowedScopes,
+		GrantTypes:    client.GrantTypes,
+		RevokedAt:     client.RevokedAt,
+		CreatedAt:     client.CreatedAt,
+	}
+}
+
+func ToOAuthClientResponseList(clients []OAuthClient) []OAuthClientResponse {
+	responses := make([]OAuthClientResponse, len(clients))
+	for i, client := range clients {
+		responses[i] = ToOAuthClientResponse(&client)
+	}
+	return responses
+}
@@ -0,0 +1,123 @@
+// AngelaMos | 2026
+// email_verification.go
+
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+// EmailVerificationConfig mirrors config.EmailVerificationConfig; it's a
+// separate type so this package doesn't import internal/config.
+type EmailVerificationConfig struct {
+	TokenTTL  time.Duration
+	RateLimit RateWindow
+}
+
+const emailVerificationTokenBytes = 32
+
+// EmailVerificationRepository persists single-use email verification
+// tokens.
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, token *EmailVerificationToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*EmailVerificationToken, error)
+	MarkUsed(ctx context.Context, id string) error
+	InvalidateForUser(ctx context.Context, userID string) error
+}
+
+type emailVerificationRepository struct {
+	db core.DBTX
+}
+
+func NewEmailVerificationRepository(db core.DBTX) EmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+func (r *emailVerificationRepository) Create(
+	ctx context.Context,
+	token *EmailVerificationToken,
+) error {
+	query := `
+		INSERT INTO email_verification_tokens (id, user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at`
+
+	err := r.db.GetContext(ctx, &token.CreatedAt, query,
+		token.ID, token.UserID, token.TokenHash, token.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create email verification token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *emailVerificationRepository) FindByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*EmailVerificationToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM email_verification_tokens
+		WHERE token_hash = $1`
+
+	var token EmailVerificationToken
+	err := r.db.GetContext(ctx, &token, query, tokenHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("find email verification token: %w", core.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find email verification token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r *emailVerificationRepository) MarkUsed(ctx context.Context, id string) error {
+	query := `
+		UPDATE email_verification_tokens
+		SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("mark email verification token used: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("mark email verification token used: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("mark email verification token used: %w", core.ErrNotFound)
+	}
+
+	return nil
+}
+
+// InvalidateForUser marks every still-usable token for userID as used, so
+// requesting a new verification email retires any earlier one still
+// sitting in an inbox instead of leaving both valid at once.
+func (r *emailVerificationRepository) InvalidateForUser(
+	ctx context.Context,
+	userID string,
+) error {
+	query := `
+		UPDATE email_verification_tokens
+		SET used_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL AND expires_at > NOW()`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("invalidate email verification tokens: %w", err)
+	}
+
+	return nil
+}
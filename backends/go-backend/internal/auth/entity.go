@@ -4,6 +4,7 @@
 package auth
 
 import (
+	"strings"
 	"time"
 )
 
@@ -20,6 +21,7 @@ type RefreshToken struct {
 	ReplacedByID *string    `db:"replaced_by_id"`
 	UserAgent    string     `db:"user_agent"`
 	IPAddress    string     `db:"ip_address"`
+	LastUsedAt   time.Time  `db:"last_used_at"`
 }
 
 func (t *RefreshToken) IsExpired() bool {
@@ -34,6 +36,16 @@ func (t *RefreshToken) IsValid() bool {
 	return !t.IsExpired() && !t.IsRevoked() && !t.IsUsed
 }
 
+// IsIdle reports whether the token's family has gone longer than
+// idleTimeout since it was last rotated, independent of the token's
+// absolute ExpiresAt. A non-positive idleTimeout disables the check.
+func (t *RefreshToken) IsIdle(idleTimeout time.Duration) bool {
+	if idleTimeout <= 0 {
+		return false
+	}
+	return time.Since(t.LastUsedAt) > idleTimeout
+}
+
 func (t *RefreshToken) MarkAsUsed(replacedByID string) {
 	now := time.Now()
 	t.IsUsed = true
@@ -45,3 +57,187 @@ func (t *RefreshToken) Revoke() {
 	now := time.Now()
 	t.RevokedAt = &now
 }
+
+// PasswordResetToken is a single-use, short-lived credential minted by
+// ForgotPassword and consumed by ResetPassword. Only its hash is stored,
+// mirroring how RefreshToken never persists the bearer value itself.
+type PasswordResetToken struct {
+	ID        string     `db:"id"`
+	UserID    string     `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+func (t *PasswordResetToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+func (t *PasswordResetToken) IsValid() bool {
+	return !t.IsExpired() && t.UsedAt == nil
+}
+
+// EmailVerificationToken is a single-use, short-lived credential minted by
+// SendVerificationEmail and consumed by ConfirmEmail. Only its hash is
+// stored, mirroring PasswordResetToken.
+type EmailVerificationToken struct {
+	ID        string     `db:"id"`
+	UserID    string     `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+func (t *EmailVerificationToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+func (t *EmailVerificationToken) IsValid() bool {
+	return !t.IsExpired() && t.UsedAt == nil
+}
+
+type PasskeyCredential struct {
+	ID           string     `db:"id"`
+	UserID       string     `db:"user_id"`
+	Name         string     `db:"name"`
+	CredentialID string     `db:"credential_id"`
+	PublicKey    []byte     `db:"public_key"`
+	SignCount    uint32     `db:"sign_count"`
+	AAGUID       string     `db:"aaguid"`
+	Transports   []string   `db:"transports"`
+	CreatedAt    time.Time  `db:"created_at"`
+	LastUsedAt   *time.Time `db:"last_used_at"`
+}
+
+// UpdateSignCount records a successful assertion, returning false if the
+// authenticator-reported counter did not increase — a signal that the
+// credential may have been cloned.
+func (c *PasskeyCredential) UpdateSignCount(newCount uint32) bool {
+	if newCount != 0 && newCount <= c.SignCount {
+		return false
+	}
+
+	now := time.Now()
+	c.SignCount = newCount
+	c.LastUsedAt = &now
+	return true
+}
+
+// APIToken is a long-lived, user-issued credential for script and CI/CD
+// clients: pat_<ID>.<secret>. Only SecretHash is ever persisted; ID is
+// stored in the clear as the lookup key since Argon2id/BLAKE2b's random
+// per-hash salt rules out using the hash itself as a unique index —
+// mirroring why DeviceAuthorization stores its user_code unhashed too.
+type APIToken struct {
+	ID         string     `db:"id"`
+	UserID     string     `db:"user_id"`
+	Name       string     `db:"name"`
+	SecretHash string     `db:"secret_hash"`
+	Scopes     []string   `db:"scopes"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+func (t *APIToken) IsExpired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+func (t *APIToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+func (t *APIToken) IsValid() bool {
+	return !t.IsExpired() && !t.IsRevoked()
+}
+
+// HasScope reports whether scope was granted to this token. A token
+// issued with no scopes at all carries the full authority of its owning
+// user, the same way an unrestricted refresh token does.
+func (t *APIToken) HasScope(scope string) bool {
+	if len(t.Scopes) == 0 {
+		return true
+	}
+
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OAuthClient is a registered first-party or third-party OIDC client:
+// client_id is stored in the clear as the lookup key, mirroring APIToken
+// and DeviceAuthorization's reasoning for why a salted hash can't serve as
+// one; only ClientSecretHash is ever persisted. Unlike APIToken's
+// zero-scopes-means-everything convention, an OAuthClient with no
+// AllowedScopes can request none at all — client registration is expected
+// to be explicit about what it's trusted for.
+type OAuthClient struct {
+	ID               string     `db:"id"`
+	Name             string     `db:"name"`
+	ClientSecretHash string     `db:"client_secret_hash"`
+	RedirectURIs     []string   `db:"redirect_uris"`
+	AllowedScopes    []string   `db:"allowed_scopes"`
+	GrantTypes       []string   `db:"grant_types"`
+	RevokedAt        *time.Time `db:"revoked_at"`
+	CreatedAt        time.Time  `db:"created_at"`
+}
+
+func (c *OAuthClient) IsRevoked() bool {
+	return c.RevokedAt != nil
+}
+
+// AllowsRedirectURI reports whether uri is one of the client's registered
+// redirect URIs. Per RFC 6749 §3.1.2, this must be an exact match — no
+// prefix or wildcard matching, which would let a malicious path on the
+// same host siphon off an authorization code.
+func (c *OAuthClient) AllowsRedirectURI(uri string) bool {
+	for _, registered := range c.RedirectURIs {
+		if registered == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsGrantType reports whether grantType is one of the client's
+// registered grant types (e.g. "authorization_code", "refresh_token",
+// "client_credentials").
+func (c *OAuthClient) AllowsGrantType(grantType string) bool {
+	for _, g := range c.GrantTypes {
+		if g == grantType {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveScope validates requested (a space-delimited scope string) against
+// AllowedScopes, returning the granted scope string. An empty requested
+// scope grants the client's full AllowedScopes, mirroring how an
+// authorization request is allowed to omit "scope" entirely; a non-empty
+// requested scope must be a subset or ErrOAuthInvalidScope is returned.
+func (c *OAuthClient) ResolveScope(requested string) (string, error) {
+	if requested == "" {
+		return strings.Join(c.AllowedScopes, " "), nil
+	}
+
+	allowed := make(map[string]bool, len(c.AllowedScopes))
+	for _, s := range c.AllowedScopes {
+		allowed[s] = true
+	}
+
+	for _, s := range strings.Fields(requested) {
+		if !allowed[s] {
+			return "", ErrOAuthInvalidScope
+		}
+	}
+
+	return requested, nil
+}
@@ -6,37 +6,108 @@ package auth
 import (
 	"encoding/json"
 	"errors"
-	"net"
 	"net/http"
-	"strings"
+	"net/url"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 
 	"github.com/carterperez-dev/templates/go-backend/internal/core"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/clientip"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/logging"
 	"github.com/carterperez-dev/templates/go-backend/internal/middleware"
 )
 
 type Handler struct {
-	service   *Service
-	validator *validator.Validate
+	service      *Service
+	passkeys     *PasskeyService
+	providers    *ProviderRegistry
+	devices      *DeviceService
+	apiTokens    *APITokenService
+	oauthClients ClientRepository
+	oauthCfg     OAuthProviderConfig
+	validator    *validator.Validate
+	ipResolver   *clientip.Resolver
 }
 
-func NewHandler(service *Service) *Handler {
+func NewHandler(service *Service, ipResolver *clientip.Resolver) *Handler {
 	return &Handler{
-		service:   service,
-		validator: validator.New(validator.WithRequiredStructEnabled()),
+		service:    service,
+		validator:  validator.New(validator.WithRequiredStructEnabled()),
+		ipResolver: ipResolver,
 	}
 }
 
+// WithPasskeys enables the WebAuthn routes on this handler. Passkey support
+// is optional (it requires Redis for challenge storage), so it is wired in
+// after construction rather than as a required NewHandler argument.
+func (h *Handler) WithPasskeys(passkeys *PasskeyService) *Handler {
+	h.passkeys = passkeys
+	return h
+}
+
+// WithIdentityProviders enables the social-login routes on this handler.
+// Like passkeys, connectors are optional and wired in after construction.
+func (h *Handler) WithIdentityProviders(providers *ProviderRegistry) *Handler {
+	h.providers = providers
+	return h
+}
+
+// WithDeviceAuthorization enables the RFC 8628 device-flow routes on this
+// handler. Like passkeys and identity providers, it's optional and wired in
+// after construction.
+func (h *Handler) WithDeviceAuthorization(devices *DeviceService) *Handler {
+	h.devices = devices
+	return h
+}
+
+// WithAPITokens enables the personal-access-token management routes on
+// this handler. Like the other optional credential types above, it's
+// wired in after construction.
+func (h *Handler) WithAPITokens(apiTokens *APITokenService) *Handler {
+	h.apiTokens = apiTokens
+	return h
+}
+
+// WithOAuthProvider enables this handler's OIDC authorization-server
+// routes (RegisterOAuthRoutes, RegisterOAuthAdminRoutes). Like the other
+// optional credential types above, it's wired in after construction.
+func (h *Handler) WithOAuthProvider(oauthClients ClientRepository, cfg OAuthProviderConfig) *Handler {
+	h.oauthClients = oauthClients
+	h.oauthCfg = cfg
+	return h
+}
+
 func (h *Handler) RegisterRoutes(
 	r chi.Router,
 	authenticator func(http.Handler) http.Handler,
+	loginThrottle func(http.Handler) http.Handler,
+	reauth func(http.Handler) http.Handler,
+	reauthStrict func(http.Handler) http.Handler,
 ) {
 	r.Route("/auth", func(r chi.Router) {
-		r.Post("/login", h.Login)
+		r.With(loginThrottle).Post("/login", h.Login)
 		r.Post("/register", h.Register)
-		r.Post("/refresh", h.Refresh)
+		r.With(loginThrottle).Post("/refresh", h.Refresh)
+		r.Post("/forgot-password", h.ForgotPassword)
+		r.Post("/reset-password", h.ResetPassword)
+		r.Post("/email/verify", h.VerifyEmail)
+
+		if h.passkeys != nil {
+			r.Post("/passkeys/assertion/begin", h.BeginPasskeyAssertion)
+			r.Post("/passkeys/assertion/finish", h.FinishPasskeyAssertion)
+		}
+
+		if h.providers != nil {
+			r.Get("/oidc/{provider}/start", h.StartOIDC)
+			r.Get("/oidc/{provider}/callback", h.OIDCCallback)
+		}
+
+		if h.devices != nil {
+			r.Post("/device/code", h.DeviceCode)
+			r.Post("/device/token", h.DeviceToken)
+		}
 
 		r.Group(func(r chi.Router) {
 			r.Use(authenticator)
@@ -44,12 +115,395 @@ func (h *Handler) RegisterRoutes(
 			r.Post("/logout", h.Logout)
 			r.Post("/logout-all", h.LogoutAll)
 			r.Get("/sessions", h.GetSessions)
-			r.Delete("/sessions/{sessionID}", h.RevokeSession)
-			r.Post("/change-password", h.ChangePassword)
+			r.With(reauthStrict).Delete("/sessions/{sessionID}", h.RevokeSession)
+			r.With(reauth).Post("/change-password", h.ChangePassword)
+			r.Post("/mfa/totp/enroll", h.EnrollTOTP)
+			r.Post("/mfa/totp/confirm", h.ConfirmTOTP)
+			r.With(reauthStrict).Post("/mfa/totp/disable", h.DisableTOTP)
+			r.Post("/email/verify/send", h.SendVerificationEmail)
+			r.With(loginThrottle).Post("/reauthenticate", h.Reauthenticate)
+
+			if h.passkeys != nil {
+				r.Post("/passkeys/registration/begin", h.BeginPasskeyRegistration)
+				r.Post("/passkeys/registration/finish", h.FinishPasskeyRegistration)
+			}
+
+			if h.providers != nil {
+				r.Get("/identities", h.ListIdentityLinks)
+				r.Delete("/identities/{provider}", h.UnlinkIdentity)
+			}
+
+			if h.devices != nil {
+				r.Post("/device/verify", h.DeviceVerify)
+			}
+
+			if h.apiTokens != nil {
+				r.Post("/api-tokens", h.CreateAPIToken)
+				r.Get("/api-tokens", h.ListAPITokens)
+				r.Delete("/api-tokens/{tokenID}", h.RevokeAPIToken)
+			}
 		})
 	})
 }
 
+// RegisterOAuthRoutes registers this provider's OIDC authorization-server
+// endpoints. /oauth/authorize sits behind authenticator since it needs an
+// already-authenticated resource owner to approve the request; the other
+// three are hit directly by clients (not browsers) and carry their own
+// client_id/client_secret, so they're unauthenticated at the HTTP layer.
+// A nil oauthClients leaves the handler with nothing to wire, mirroring the
+// other optional credential types.
+func (h *Handler) RegisterOAuthRoutes(
+	r chi.Router,
+	authenticator func(http.Handler) http.Handler,
+) {
+	if h.oauthClients == nil {
+		return
+	}
+
+	r.Route("/oauth", func(r chi.Router) {
+		r.With(authenticator).Get("/authorize", h.Authorize)
+		r.Post("/token", h.Token)
+		r.Post("/introspect", h.Introspect)
+		r.Post("/revoke", h.Revoke)
+	})
+}
+
+// RegisterOAuthAdminRoutes registers CRUD endpoints for managing registered
+// OIDC clients, gated the same way RegisterAdminRoutes gates user
+// management: authenticator then adminOnly on the whole group.
+func (h *Handler) RegisterOAuthAdminRoutes(
+	r chi.Router,
+	authenticator, adminOnly func(http.Handler) http.Handler,
+) {
+	if h.oauthClients == nil {
+		return
+	}
+
+	r.Route("/admin/oauth/clients", func(r chi.Router) {
+		r.Use(authenticator)
+		r.Use(adminOnly)
+
+		r.Post("/", h.CreateOAuthClient)
+		r.Get("/", h.ListOAuthClients)
+		r.Put("/{clientID}", h.UpdateOAuthClient)
+		r.Delete("/{clientID}", h.RevokeOAuthClient)
+	})
+}
+
+func (h *Handler) BeginPasskeyRegistration(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	resp, err := h.passkeys.BeginRegistration(r.Context(), userID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, resp)
+}
+
+func (h *Handler) FinishPasskeyRegistration(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	var req FinishRegistrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	resp, err := h.passkeys.FinishRegistration(r.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, ErrChallengeExpired) ||
+			errors.Is(err, ErrOriginMismatch) ||
+			errors.Is(err, ErrRPIDHashMismatch) {
+			core.BadRequest(w, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.Created(w, resp)
+}
+
+func (h *Handler) BeginPasskeyAssertion(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	resp, err := h.passkeys.BeginAssertion(r.Context(), nil)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, resp)
+}
+
+func (h *Handler) FinishPasskeyAssertion(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var req FinishAssertionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	cred, err := h.passkeys.FinishAssertion(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, ErrChallengeExpired) ||
+			errors.Is(err, ErrOriginMismatch) ||
+			errors.Is(err, ErrRPIDHashMismatch) ||
+			errors.Is(err, ErrCredentialCloned) {
+			core.JSONError(w, core.UnauthorizedError(err.Error()))
+			return
+		}
+		if errors.Is(err, ErrCredentialNotFound) {
+			core.JSONError(w, core.UnauthorizedError("unknown credential"))
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	userAgent := r.UserAgent()
+	ipAddress := h.ipResolver.ClientIP(r)
+
+	resp, err := h.service.LoginWithPasskey(r.Context(), cred.UserID, userAgent, ipAddress)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, resp)
+}
+
+const oidcStateCookie = "oidc_state"
+
+// StartOIDC redirects the browser to the provider's authorization URL,
+// binding the opaque state token to a short-lived cookie so the callback
+// can reject a state that didn't originate from this browser.
+func (h *Handler) StartOIDC(w http.ResponseWriter, r *http.Request) {
+	providerName := chi.URLParam(r, "provider")
+
+	provider, err := h.providers.Get(providerName)
+	if err != nil {
+		core.NotFound(w, "unknown identity provider")
+		return
+	}
+
+	redirectTo := r.URL.Query().Get("redirect_to")
+
+	authURL, state, err := provider.StartAuth(r.Context(), redirectTo)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/v1/auth/oidc/" + providerName,
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func (h *Handler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	providerName := chi.URLParam(r, "provider")
+
+	provider, err := h.providers.Get(providerName)
+	if err != nil {
+		core.NotFound(w, "unknown identity provider")
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	state := r.URL.Query().Get("state")
+	if code == "" || state == "" {
+		core.BadRequest(w, "missing code or state")
+		return
+	}
+
+	stateCookie, err := r.Cookie(oidcStateCookie)
+	if err != nil || stateCookie.Value != state {
+		core.JSONError(w, core.UnauthorizedError("oauth state invalid or expired"))
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    "",
+		Path:     "/v1/auth/oidc/" + providerName,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	identity, err := provider.HandleCallback(r.Context(), code, state)
+	if err != nil {
+		if errors.Is(err, ErrOIDCStateInvalid) {
+			core.JSONError(w, core.UnauthorizedError("oauth state invalid or expired"))
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	userAgent := r.UserAgent()
+	ipAddress := h.ipResolver.ClientIP(r)
+
+	resp, err := h.service.LoginWithIdentity(r.Context(), identity, userAgent, ipAddress)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, resp)
+}
+
+func (h *Handler) DeviceCode(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.devices.RequestCode(r.Context())
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, resp)
+}
+
+// DeviceToken is polled by the CLI at the interval returned from
+// DeviceCode. Per RFC 8628, every outcome short of success is still
+// reported with a 4xx status and a machine-readable error code rather than
+// an internal error, since "not yet approved" is the expected steady
+// state of this endpoint.
+func (h *Handler) DeviceToken(w http.ResponseWriter, r *http.Request) {
+	var req DeviceTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	auth, err := h.devices.Poll(r.Context(), req.DeviceCode)
+	if err != nil {
+		code := deviceErrorCode(err)
+		if code == "" {
+			logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+			core.InternalServerError(w, err)
+			return
+		}
+		core.JSONError(w, core.NewAppError(err, err.Error(), http.StatusBadRequest, code))
+		return
+	}
+
+	userAgent := r.UserAgent()
+	ipAddress := h.ipResolver.ClientIP(r)
+
+	resp, err := h.service.LoginWithDeviceAuthorization(r.Context(), *auth.UserID, userAgent, ipAddress)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, resp)
+}
+
+// deviceErrorCode maps a DeviceService.Poll error to the RFC 8628 "error"
+// value, or "" if err isn't one of the sentinels it produces.
+func deviceErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrDeviceAuthorizationPending):
+		return "authorization_pending"
+	case errors.Is(err, ErrDeviceSlowDown):
+		return "slow_down"
+	case errors.Is(err, ErrDeviceAccessDenied):
+		return "access_denied"
+	case errors.Is(err, ErrDeviceCodeExpired):
+		return "expired_token"
+	default:
+		return ""
+	}
+}
+
+// DeviceVerify is hit from an authenticated browser tab after the user
+// types the code shown on their device, binding the pending authorization
+// to their account so the next DeviceToken poll succeeds.
+func (h *Handler) DeviceVerify(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	var req DeviceVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	if err := h.devices.Verify(r.Context(), req.UserCode, userID); err != nil {
+		if errors.Is(err, ErrDeviceUserCodeNotFound) {
+			core.NotFound(w, "user code")
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -63,7 +517,7 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userAgent := r.UserAgent()
-	ipAddress := extractIPAddress(r)
+	ipAddress := h.ipResolver.ClientIP(r)
 
 	resp, err := h.service.Login(r.Context(), req, userAgent, ipAddress)
 	if err != nil {
@@ -74,6 +528,15 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 			)
 			return
 		}
+		if errors.Is(err, ErrTOTPInvalidCode) {
+			core.JSONError(w, core.UnauthorizedError("invalid totp code"))
+			return
+		}
+		if errors.Is(err, ErrMFAChallengeInvalid) {
+			core.JSONError(w, core.UnauthorizedError("mfa challenge is invalid or expired"))
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
 		core.InternalServerError(w, err)
 		return
 	}
@@ -94,7 +557,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userAgent := r.UserAgent()
-	ipAddress := extractIPAddress(r)
+	ipAddress := h.ipResolver.ClientIP(r)
 
 	resp, err := h.service.Register(r.Context(), req, userAgent, ipAddress)
 	if err != nil {
@@ -102,6 +565,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 			core.JSONError(w, core.DuplicateError("email"))
 			return
 		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
 		core.InternalServerError(w, err)
 		return
 	}
@@ -122,7 +586,7 @@ func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userAgent := r.UserAgent()
-	ipAddress := extractIPAddress(r)
+	ipAddress := h.ipResolver.ClientIP(r)
 
 	resp, err := h.service.Refresh(
 		r.Context(),
@@ -132,6 +596,9 @@ func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
 	)
 	if err != nil {
 		if errors.Is(err, ErrTokenReuse) {
+			logging.FromContext(r.Context()).Warn("security alert: refresh token reuse detected, all sessions revoked",
+				"remote_ip", ipAddress,
+			)
 			core.JSONError(w, core.NewAppError(
 				core.ErrTokenRevoked,
 				"security alert: token reuse detected, all sessions revoked",
@@ -152,6 +619,7 @@ func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
 			core.JSONError(w, core.TokenInvalidError())
 			return
 		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
 		core.InternalServerError(w, err)
 		return
 	}
@@ -177,6 +645,7 @@ func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
 			core.Forbidden(w, "cannot revoke another user's token")
 			return
 		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
 		core.InternalServerError(w, err)
 		return
 	}
@@ -192,6 +661,7 @@ func (h *Handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.LogoutAll(r.Context(), userID); err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
 		core.InternalServerError(w, err)
 		return
 	}
@@ -208,6 +678,7 @@ func (h *Handler) GetSessions(w http.ResponseWriter, r *http.Request) {
 
 	sessions, err := h.service.GetActiveSessions(r.Context(), userID)
 	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
 		core.InternalServerError(w, err)
 		return
 	}
@@ -237,6 +708,7 @@ func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
 			core.Forbidden(w, "cannot revoke another user's session")
 			return
 		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
 		core.InternalServerError(w, err)
 		return
 	}
@@ -270,6 +742,7 @@ func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 			)
 			return
 		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
 		core.InternalServerError(w, err)
 		return
 	}
@@ -277,40 +750,736 @@ func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	core.NoContent(w)
 }
 
-func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
+// Reauthenticate re-verifies the caller's password and, on success, mints a
+// fresh token pair whose access token carries a short-lived "reauth_at"
+// claim for the caller's current session — the sid claim already on the
+// request's access token — satisfied by middleware.RequireReauth on
+// sensitive routes until the configured window elapses.
+func (h *Handler) Reauthenticate(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == "" {
 		core.Unauthorized(w, "")
 		return
 	}
 
-	user, err := h.service.GetCurrentUser(r.Context(), userID)
-	if err != nil {
-		if errors.Is(err, core.ErrNotFound) {
-			core.NotFound(w, "user")
-			return
-		}
-		core.InternalServerError(w, err)
+	claims := middleware.GetClaims(r.Context())
+	if claims == nil || claims.SessionID == "" {
+		core.Unauthorized(w, "")
 		return
 	}
 
-	core.OK(w, user)
-}
-
-func extractIPAddress(r *http.Request) string {
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		ips := strings.Split(xff, ",")
-		return strings.TrimSpace(ips[len(ips)-1])
+	var req ReauthenticateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
 	}
 
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return xri
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
 	}
 
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		return r.RemoteAddr
-	}
+	userAgent := r.UserAgent()
+	ipAddress := h.ipResolver.ClientIP(r)
 
-	return ip
+	resp, err := h.service.Reauthenticate(r.Context(), userID, claims.SessionID, req.Password, userAgent, ipAddress)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			core.JSONError(
+				w,
+				core.UnauthorizedError("current password is incorrect"),
+			)
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, resp)
+}
+
+// ForgotPassword always responds 204 regardless of whether the email
+// belongs to an account, so the response itself can't be used to enumerate
+// registered addresses.
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	ipAddress := h.ipResolver.ClientIP(r)
+
+	if err := h.service.ForgotPassword(r.Context(), req.Email, ipAddress); err != nil {
+		if errors.Is(err, ErrResetRateLimited) {
+			core.JSONError(w, core.TooManyRequestsError(
+				"too many password reset requests, try again later",
+			))
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	if err := h.service.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
+		if errors.Is(err, ErrResetTokenInvalid) {
+			core.BadRequest(w, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+// SendVerificationEmail is authenticated (unlike ForgotPassword) since
+// there's no enumeration concern in re-sending a link to the caller's own
+// address: the account is already known from the access token.
+func (h *Handler) SendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	if err := h.service.SendVerificationEmail(r.Context(), userID); err != nil {
+		if errors.Is(err, ErrEmailAlreadyVerified) {
+			core.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Is(err, ErrVerifyRateLimited) {
+			core.JSONError(w, core.TooManyRequestsError(err.Error()))
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	if err := h.service.ConfirmEmail(r.Context(), req.Token); err != nil {
+		if errors.Is(err, ErrVerifyTokenInvalid) {
+			core.BadRequest(w, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	user, err := h.service.GetCurrentUser(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			core.NotFound(w, "user")
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, user)
+}
+
+func (h *Handler) ListIdentityLinks(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	links, err := h.service.ListIdentityLinks(r.Context(), userID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, IdentityLinkListResponse{Links: ToIdentityLinkResponseList(links)})
+}
+
+func (h *Handler) UnlinkIdentity(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	provider := chi.URLParam(r, "provider")
+
+	if err := h.service.UnlinkIdentity(r.Context(), userID, provider); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			core.NotFound(w, "identity link")
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+// CreateAPIToken mints a new personal access token for the caller and
+// returns its one-time-visible bearer value; it can't be retrieved again
+// after this response.
+func (h *Handler) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	var req CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	token, bearer, err := h.apiTokens.Create(
+		r.Context(),
+		userID,
+		req.Name,
+		req.Scopes,
+		time.Duration(req.TTL)*time.Second,
+	)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.Created(w, CreateAPITokenResponse{
+		APITokenResponse: ToAPITokenResponse(token),
+		Token:            bearer,
+	})
+}
+
+func (h *Handler) ListAPITokens(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	tokens, err := h.apiTokens.List(r.Context(), userID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, APITokenListResponse{Tokens: ToAPITokenResponseList(tokens)})
+}
+
+func (h *Handler) RevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	tokenID := chi.URLParam(r, "tokenID")
+
+	if err := h.apiTokens.Revoke(r.Context(), userID, tokenID); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			core.NotFound(w, "api token")
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+// EnrollTOTP stages a new TOTP secret and recovery codes for the caller;
+// neither takes effect until ConfirmTOTP verifies a code from the returned
+// secret.
+func (h *Handler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	secret, otpauthURL, recoveryCodes, err := h.service.EnrollTOTP(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, ErrTOTPAlreadyEnabled) {
+			core.BadRequest(w, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, EnrollTOTPResponse{
+		Secret:        secret,
+		OTPAuthURL:    otpauthURL,
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// ConfirmTOTP verifies a code generated from the secret EnrollTOTP just
+// staged and, on success, turns MFA on for the caller's account.
+func (h *Handler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	var req ConfirmTOTPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	if err := h.service.ConfirmTOTP(r.Context(), userID, req.Code); err != nil {
+		if errors.Is(err, ErrTOTPInvalidCode) ||
+			errors.Is(err, ErrTOTPNotEnrolled) ||
+			errors.Is(err, ErrTOTPAlreadyEnabled) {
+			core.BadRequest(w, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+// DisableTOTP requires a current TOTP code or an unused recovery code
+// before turning MFA back off for the caller's account.
+func (h *Handler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	var req DisableTOTPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	if err := h.service.DisableTOTP(r.Context(), userID, req.Code); err != nil {
+		if errors.Is(err, ErrTOTPInvalidCode) || errors.Is(err, ErrTOTPNotEnabled) {
+			core.BadRequest(w, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+// Authorize reads an authorization request off the query string (per
+// RFC 6749 §4.1.1, not a JSON body, since this endpoint is hit by a
+// browser navigation rather than a programmatic client) and redirects the
+// already-authenticated caller back to the client's redirect_uri with the
+// issued code and the caller-supplied state, mirroring StartOIDC's
+// redirect-based flow.
+func (h *Handler) Authorize(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		core.Unauthorized(w, "")
+		return
+	}
+
+	q := r.URL.Query()
+	req := OAuthAuthorizeRequest{
+		ClientID:            q.Get("client_id"),
+		RedirectURI:         q.Get("redirect_uri"),
+		ResponseType:        q.Get("response_type"),
+		Scope:               q.Get("scope"),
+		State:               q.Get("state"),
+		CodeChallenge:       q.Get("code_challenge"),
+		CodeChallengeMethod: q.Get("code_challenge_method"),
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	code, err := h.service.Authorize(
+		r.Context(),
+		userID,
+		req.ClientID,
+		req.RedirectURI,
+		req.Scope,
+		req.CodeChallenge,
+		req.CodeChallengeMethod,
+	)
+	if err != nil {
+		// ErrOAuthInvalidScope is the only failure Service.Authorize can
+		// return after it has already confirmed redirect_uri is one of the
+		// client's registered URIs, so per RFC 6749 §4.1.2.1 it's reported
+		// by redirecting back to that URI with an error query, not a bare
+		// API error — unlike an unknown client or unregistered redirect_uri,
+		// there's nowhere safe to send the browser back to for those.
+		if errors.Is(err, ErrOAuthInvalidScope) {
+			h.redirectOAuthError(w, r, req.RedirectURI, req.State, "invalid_scope")
+			return
+		}
+		if errors.Is(err, ErrOAuthInvalidClient) ||
+			errors.Is(err, ErrOAuthInvalidRedirectURI) ||
+			errors.Is(err, ErrOAuthUnsupportedGrantType) ||
+			errors.Is(err, ErrOAuthUnsupportedChallenge) {
+			core.BadRequest(w, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	redirectURL, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		core.BadRequest(w, "invalid redirect_uri")
+		return
+	}
+
+	query := redirectURL.Query()
+	query.Set("code", code)
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+	redirectURL.RawQuery = query.Encode()
+
+	http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+}
+
+// redirectOAuthError sends the browser back to redirectURI with an
+// "error"/"state" query per RFC 6749 §4.1.2.1, for an Authorize failure
+// that occurred after redirectURI was already confirmed to be one of the
+// client's registered URIs. Falls back to a bare API error if redirectURI
+// itself doesn't parse, since there's nowhere safe to send the browser.
+func (h *Handler) redirectOAuthError(w http.ResponseWriter, r *http.Request, redirectURI, state, code string) {
+	redirectURL, err := url.Parse(redirectURI)
+	if err != nil {
+		core.BadRequest(w, "invalid redirect_uri")
+		return
+	}
+
+	query := redirectURL.Query()
+	query.Set("error", code)
+	if state != "" {
+		query.Set("state", state)
+	}
+	redirectURL.RawQuery = query.Encode()
+
+	http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+}
+
+// Token implements POST /oauth/token across every grant type this
+// provider supports; see Service.ExchangeToken.
+func (h *Handler) Token(w http.ResponseWriter, r *http.Request) {
+	var req OAuthTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	userAgent := r.UserAgent()
+	ipAddress := h.ipResolver.ClientIP(r)
+
+	resp, err := h.service.ExchangeToken(r.Context(), req, userAgent, ipAddress)
+	if err != nil {
+		// Per RFC 6749 §5.2, a bad client_id/client_secret is reported as
+		// "invalid_client"/401, distinct from the grant-shaped failures
+		// below ("invalid_grant"/400) — the same split Introspect and
+		// Revoke make for the identical error.
+		if errors.Is(err, ErrOAuthInvalidClient) {
+			core.JSONError(w, core.NewAppError(err, err.Error(), http.StatusUnauthorized, "invalid_client"))
+			return
+		}
+		if errors.Is(err, ErrOAuthUnsupportedGrantType) ||
+			errors.Is(err, ErrOAuthInvalidGrant) ||
+			errors.Is(err, ErrOAuthInvalidCodeVerifier) {
+			core.JSONError(w, core.NewAppError(err, err.Error(), http.StatusBadRequest, "invalid_grant"))
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, resp)
+}
+
+// Introspect implements RFC 7662.
+func (h *Handler) Introspect(w http.ResponseWriter, r *http.Request) {
+	var req OAuthIntrospectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	resp, err := h.service.Introspect(r.Context(), req.Token, req.ClientID, req.ClientSecret)
+	if err != nil {
+		if errors.Is(err, ErrOAuthInvalidClient) {
+			core.JSONError(w, core.NewAppError(err, err.Error(), http.StatusUnauthorized, "invalid_client"))
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, resp)
+}
+
+// Revoke implements RFC 7009. Per the RFC, the response is unconditional
+// success whether or not token was ever valid; see Service.RevokeOAuthToken.
+func (h *Handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	var req OAuthRevokeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	if err := h.service.RevokeOAuthToken(r.Context(), req.Token, req.ClientID, req.ClientSecret); err != nil {
+		if errors.Is(err, ErrOAuthInvalidClient) {
+			core.JSONError(w, core.NewAppError(err, err.Error(), http.StatusUnauthorized, "invalid_client"))
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+// OIDCDiscovery serves GET /.well-known/openid-configuration, the OpenID
+// Connect Discovery 1.0 document, complementing the JWKS endpoint
+// JWTManager.GetJWKSHandler already serves at the same top level. baseURL
+// is the externally-reachable origin this API is served from (h.oauthCfg's
+// BaseURL), since every endpoint listed here must be an absolute URL.
+func (h *Handler) OIDCDiscovery(w http.ResponseWriter, r *http.Request) {
+	baseURL := h.oauthCfg.BaseURL
+
+	doc := OIDCDiscoveryDocument{
+		Issuer:                            baseURL,
+		AuthorizationEndpoint:             baseURL + "/v1/oauth/authorize",
+		TokenEndpoint:                     baseURL + "/v1/oauth/token",
+		IntrospectionEndpoint:             baseURL + "/v1/oauth/introspect",
+		RevocationEndpoint:                baseURL + "/v1/oauth/revoke",
+		JWKSURI:                           baseURL + "/.well-known/jwks.json",
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code", "refresh_token", "client_credentials"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{"ES256"},
+		ScopesSupported:                   []string{"openid", "profile", "email"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_post"},
+	}
+
+	core.OK(w, doc)
+}
+
+// CreateOAuthClient registers a new first- or third-party OIDC client. The
+// client secret is returned only in this response; no other endpoint ever
+// reveals it again, mirroring CreateAPIToken.
+func (h *Handler) CreateOAuthClient(w http.ResponseWriter, r *http.Request) {
+	var req CreateOAuthClientRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	clientID, secret, secretHash, err := GenerateClientCredentials()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	client := &OAuthClient{
+		ID:               clientID,
+		Name:             req.Name,
+		ClientSecretHash: secretHash,
+		RedirectURIs:     req.RedirectURIs,
+		AllowedScopes:    req.AllowedScopes,
+		GrantTypes:       req.GrantTypes,
+	}
+
+	if err := h.oauthClients.Create(r.Context(), client); err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.Created(w, CreateOAuthClientResponse{
+		OAuthClientResponse: ToOAuthClientResponse(client),
+		ClientSecret:        secret,
+	})
+}
+
+// ListOAuthClients returns every registered client. Like CreateOAuthClient,
+// this never includes a secret or its hash.
+func (h *Handler) ListOAuthClients(w http.ResponseWriter, r *http.Request) {
+	clients, err := h.oauthClients.List(r.Context())
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, OAuthClientListResponse{Clients: ToOAuthClientResponseList(clients)})
+}
+
+// UpdateOAuthClient overwrites a client's name, redirect URIs, allowed
+// scopes, and grant types. The secret is untouched; see
+// ClientRepository.Update.
+func (h *Handler) UpdateOAuthClient(w http.ResponseWriter, r *http.Request) {
+	clientID := chi.URLParam(r, "clientID")
+
+	var req UpdateOAuthClientRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	client := &OAuthClient{
+		ID:            clientID,
+		Name:          req.Name,
+		RedirectURIs:  req.RedirectURIs,
+		AllowedScopes: req.AllowedScopes,
+		GrantTypes:    req.GrantTypes,
+	}
+
+	if err := h.oauthClients.Update(r.Context(), client); err != nil {
+		if errors.Is(err, ErrOAuthClientNotFound) {
+			core.NotFound(w, "oauth client")
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	updated, err := h.oauthClients.FindByID(r.Context(), clientID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, ToOAuthClientResponse(updated))
+}
+
+// RevokeOAuthClient disables a client so it can no longer authenticate at
+// /oauth/token; it does not delete the row, matching how RevokeAPIToken
+// and RevokeSession leave an auditable record behind.
+func (h *Handler) RevokeOAuthClient(w http.ResponseWriter, r *http.Request) {
+	clientID := chi.URLParam(r, "clientID")
+
+	if err := h.oauthClients.Revoke(r.Context(), clientID); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			core.NotFound(w, "oauth client")
+			return
+		}
+		logging.FromContext(r.Context()).Error("unexpected error", "error", err)
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
 }
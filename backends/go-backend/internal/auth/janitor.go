@@ -0,0 +1,131 @@
+// AngelaMos | 2026
+// janitor.go
+
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/config"
+)
+
+// janitorLockKey guards a single cleanup run so that, with multiple API
+// replicas running the same Run loop on the same config, only one of them
+// does the work for a given tick; the rest see the lock held and skip.
+const janitorLockKey = "janitor:refresh_tokens:lock"
+
+// blacklistScanPattern matches the access-token revocation keys
+// Service.RevokeAccessToken sets. Every one of those is written with a
+// TTL (see RevokeAccessToken), so any found with none (persisting
+// forever) is a bug, not an expected state, and is swept away.
+const blacklistScanPattern = "blacklist:*"
+
+// Janitor periodically purges long-expired and long-revoked rows from
+// refresh_tokens, which Logout/RevokeAllForUser/natural expiry only ever
+// mark rather than delete.
+type Janitor struct {
+	repo  Repository
+	redis *redis.Client
+	cfg   config.JanitorConfig
+}
+
+// NewJanitor builds a Janitor; call Run in its own goroutine to start it.
+func NewJanitor(repo Repository, redisClient *redis.Client, cfg config.JanitorConfig) *Janitor {
+	return &Janitor{repo: repo, redis: redisClient, cfg: cfg}
+}
+
+// Run sweeps on cfg.Interval until ctx is canceled. A non-positive
+// Interval disables the janitor entirely, the same convention
+// JWTManager.StartRotationLoop uses for its own interval.
+func (j *Janitor) Run(ctx context.Context) {
+	if j.cfg.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.runOnce(ctx)
+		}
+	}
+}
+
+func (j *Janitor) runOnce(ctx context.Context) {
+	acquired, err := j.redis.SetNX(ctx, janitorLockKey, "1", j.cfg.Interval).Result()
+	if err != nil {
+		slog.Error("janitor lock acquisition failed", "error", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	start := time.Now()
+	cutoff := start.Add(-j.cfg.Retention)
+
+	deletedExpired, err := j.repo.PurgeExpiredBefore(ctx, cutoff)
+	if err != nil {
+		slog.Error("janitor purge expired tokens failed", "error", err)
+	}
+
+	deletedRevoked, err := j.repo.PurgeRevokedBefore(ctx, cutoff)
+	if err != nil {
+		slog.Error("janitor purge revoked tokens failed", "error", err)
+	}
+
+	staleBlacklistKeys := j.sweepBlacklist(ctx)
+
+	slog.Info("auth janitor run complete",
+		"deleted_expired", deletedExpired,
+		"deleted_revoked", deletedRevoked,
+		"stale_blacklist_keys", staleBlacklistKeys,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+}
+
+// sweepBlacklist scans for blacklist:* keys with no TTL set and deletes
+// them, returning how many it found. A well-formed entry always has one
+// (see Service.RevokeAccessToken), so a missing TTL means the key would
+// otherwise sit in Redis forever.
+func (j *Janitor) sweepBlacklist(ctx context.Context) int {
+	var (
+		cursor uint64
+		found  int
+	)
+
+	for {
+		keys, next, err := j.redis.Scan(ctx, cursor, blacklistScanPattern, 0).Result()
+		if err != nil {
+			slog.Error("janitor blacklist scan failed", "error", err)
+			return found
+		}
+
+		for _, key := range keys {
+			ttl, err := j.redis.TTL(ctx, key).Result()
+			if err != nil {
+				continue
+			}
+			if ttl == -1 {
+				//nolint:errcheck // best-effort cleanup of an already-anomalous key
+				_ = j.redis.Del(ctx, key).Err()
+				found++
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return found
+}
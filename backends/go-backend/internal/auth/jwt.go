@@ -10,9 +10,11 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -25,15 +27,90 @@ import (
 	"github.com/carterperez-dev/templates/go-backend/internal/middleware"
 )
 
+// clockSkew is added to AccessTokenExpire when deciding whether a retired
+// key is safe to evict, so a token issued moments before a rotation still
+// verifies even if the verifying node's clock runs slightly behind.
+const clockSkew = 5 * time.Minute
+
+// KeyState is where a key sits in the rotation ring.
+type KeyState string
+
+const (
+	KeyStatePrimary  KeyState = "primary"
+	KeyStateRetiring KeyState = "retiring"
+	KeyStateStaged   KeyState = "staged"
+)
+
+// KeyEntry is one ES256 key in the ring. Primary signs new tokens;
+// retiring keys remain valid for verification until they age out; a
+// staged key (not yet produced by this minimal rotation algorithm, but
+// tracked for forward compatibility) is published in JWKS ahead of its
+// first use as primary.
+type KeyEntry struct {
+	ID        string
+	Private   jwk.Key
+	Public    jwk.Key
+	State     KeyState
+	CreatedAt time.Time
+	RetiredAt time.Time
+}
+
+// JWTManager mints and verifies this module's ES256 access tokens from a
+// rotating ring of keys, so a key compromise or scheduled rotation doesn't
+// invalidate tokens issued moments earlier.
 type JWTManager struct {
-	privateKey jwk.Key
-	publicKey  jwk.Key
+	mu         sync.RWMutex
+	keys       map[string]*KeyEntry
+	primaryID  string
 	publicJWKS jwk.Set
 	config     config.JWTConfig
 }
 
 func NewJWTManager(cfg config.JWTConfig) (*JWTManager, error) {
-	privateKeyPEM, err := os.ReadFile(cfg.PrivateKeyPath)
+	m := &JWTManager{
+		keys:   make(map[string]*KeyEntry),
+		config: cfg,
+	}
+
+	loaded, err := loadKeySet(cfg.KeySetPath)
+	if err != nil {
+		return nil, fmt.Errorf("load keyset: %w", err)
+	}
+
+	if loaded != nil {
+		for _, entry := range loaded {
+			m.keys[entry.ID] = entry
+			if entry.State == KeyStatePrimary {
+				m.primaryID = entry.ID
+			}
+		}
+	} else {
+		entry, bootstrapErr := bootstrapFromPEM(cfg.PrivateKeyPath)
+		if bootstrapErr != nil {
+			return nil, fmt.Errorf("bootstrap keyset: %w", bootstrapErr)
+		}
+		m.keys[entry.ID] = entry
+		m.primaryID = entry.ID
+
+		if saveErr := saveKeySet(cfg.KeySetPath, m.keys); saveErr != nil {
+			return nil, fmt.Errorf("persist bootstrapped keyset: %w", saveErr)
+		}
+	}
+
+	if m.primaryID == "" {
+		return nil, fmt.Errorf("keyset %s has no primary key", cfg.KeySetPath)
+	}
+
+	m.rebuildJWKS()
+
+	return m, nil
+}
+
+// bootstrapFromPEM migrates a pre-rotation single-key deployment's PEM
+// file into a single primary KeyEntry, so upgrading doesn't invalidate
+// the keys already on disk.
+func bootstrapFromPEM(privateKeyPath string) (*KeyEntry, error) {
+	privateKeyPEM, err := os.ReadFile(privateKeyPath)
 	if err != nil {
 		return nil, fmt.Errorf("read private key: %w", err)
 	}
@@ -52,6 +129,15 @@ func NewJWTManager(cfg config.JWTConfig) (*JWTManager, error) {
 		return nil, fmt.Errorf("set key id: %w", setErr)
 	}
 
+	return newKeyEntry(keyID, privateKey, KeyStatePrimary, time.Now())
+}
+
+func newKeyEntry(
+	keyID string,
+	privateKey jwk.Key,
+	state KeyState,
+	createdAt time.Time,
+) (*KeyEntry, error) {
 	publicKey, err := privateKey.PublicKey()
 	if err != nil {
 		return nil, fmt.Errorf("derive public key: %w", err)
@@ -60,20 +146,211 @@ func NewJWTManager(cfg config.JWTConfig) (*JWTManager, error) {
 	if setErr := publicKey.Set(jwk.KeyUsageKey, "sig"); setErr != nil {
 		return nil, fmt.Errorf("set key usage: %w", setErr)
 	}
-
-	publicJWKS := jwk.NewSet()
-	if addErr := publicJWKS.AddKey(publicKey); addErr != nil {
-		return nil, fmt.Errorf("add key to set: %w", addErr)
+	if setErr := publicKey.Set(jwk.KeyIDKey, keyID); setErr != nil {
+		return nil, fmt.Errorf("set key id: %w", setErr)
 	}
 
-	return &JWTManager{
-		privateKey: privateKey,
-		publicKey:  publicKey,
-		publicJWKS: publicJWKS,
-		config:     cfg,
+	return &KeyEntry{
+		ID:        keyID,
+		Private:   privateKey,
+		Public:    publicKey,
+		State:     state,
+		CreatedAt: createdAt,
 	}, nil
 }
 
+func generateESKeyEntry(state KeyState) (*KeyEntry, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+
+	jwkPrivate, err := jwk.Import(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("import private key: %w", err)
+	}
+
+	if setErr := jwkPrivate.Set(jwk.AlgorithmKey, jwa.ES256()); setErr != nil {
+		return nil, fmt.Errorf("set algorithm: %w", setErr)
+	}
+
+	keyID := uuid.New().String()[:8]
+	if setErr := jwkPrivate.Set(jwk.KeyIDKey, keyID); setErr != nil {
+		return nil, fmt.Errorf("set key id: %w", setErr)
+	}
+
+	return newKeyEntry(keyID, jwkPrivate, state, time.Now())
+}
+
+// RotateKeys generates a new primary ES256 key, demotes the previous
+// primary to retiring, and evicts any key that retired more than
+// AccessTokenExpire+clockSkew ago — the overlap window in which tokens
+// signed by the old primary remain verifiable. It persists the resulting
+// keyset so a restart picks up the same ring.
+func (m *JWTManager) RotateKeys(ctx context.Context) error {
+	newPrimary, err := generateESKeyEntry(KeyStatePrimary)
+	if err != nil {
+		return fmt.Errorf("rotate keys: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if previous, ok := m.keys[m.primaryID]; ok {
+		previous.State = KeyStateRetiring
+		previous.RetiredAt = time.Now()
+	}
+
+	m.keys[newPrimary.ID] = newPrimary
+	m.primaryID = newPrimary.ID
+
+	maxAge := m.config.MaxKeyAge
+	if maxAge <= 0 {
+		maxAge = m.config.AccessTokenExpire + clockSkew
+	}
+
+	for id, entry := range m.keys {
+		if entry.State == KeyStateRetiring &&
+			!entry.RetiredAt.IsZero() &&
+			time.Since(entry.RetiredAt) > maxAge {
+			delete(m.keys, id)
+		}
+	}
+
+	m.rebuildJWKSLocked()
+
+	if err := saveKeySet(m.config.KeySetPath, m.keys); err != nil {
+		return fmt.Errorf("rotate keys: persist: %w", err)
+	}
+
+	slog.Info("jwt signing keys rotated",
+		"new_primary", newPrimary.ID,
+		"ring_size", len(m.keys),
+	)
+
+	return nil
+}
+
+// StartRotationLoop rotates keys on cfg.RotationInterval until ctx is
+// canceled. A zero interval disables automatic rotation; RotateKeys can
+// still be triggered manually (e.g. from a CLI flag).
+func (m *JWTManager) StartRotationLoop(ctx context.Context) {
+	if m.config.RotationInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.config.RotationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.RotateKeys(ctx); err != nil {
+				slog.Error("scheduled jwt key rotation failed", "error", err)
+			}
+		}
+	}
+}
+
+func (m *JWTManager) rebuildJWKS() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rebuildJWKSLocked()
+}
+
+func (m *JWTManager) rebuildJWKSLocked() {
+	set := jwk.NewSet()
+	for _, entry := range m.keys {
+		//nolint:errcheck // a key derived from a valid private key always adds cleanly
+		_ = set.AddKey(entry.Public)
+	}
+	m.publicJWKS = set
+}
+
+type persistedKey struct {
+	ID         string    `json:"kid"`
+	PrivatePEM string    `json:"private_pem"`
+	State      KeyState  `json:"state"`
+	CreatedAt  time.Time `json:"created_at"`
+	RetiredAt  time.Time `json:"retired_at,omitempty"`
+}
+
+type persistedKeySet struct {
+	Keys []persistedKey `json:"keys"`
+}
+
+func loadKeySet(path string) (map[string]*KeyEntry, error) {
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read keyset: %w", err)
+	}
+
+	var persisted persistedKeySet
+	if err := json.Unmarshal(raw, &persisted); err != nil {
+		return nil, fmt.Errorf("unmarshal keyset: %w", err)
+	}
+
+	keys := make(map[string]*KeyEntry, len(persisted.Keys))
+	for _, pk := range persisted.Keys {
+		privateKey, err := jwk.ParseKey([]byte(pk.PrivatePEM), jwk.WithPEM(true))
+		if err != nil {
+			return nil, fmt.Errorf("parse key %s: %w", pk.ID, err)
+		}
+		if setErr := privateKey.Set(jwk.AlgorithmKey, jwa.ES256()); setErr != nil {
+			return nil, fmt.Errorf("set algorithm for %s: %w", pk.ID, setErr)
+		}
+		if setErr := privateKey.Set(jwk.KeyIDKey, pk.ID); setErr != nil {
+			return nil, fmt.Errorf("set key id for %s: %w", pk.ID, setErr)
+		}
+
+		entry, err := newKeyEntry(pk.ID, privateKey, pk.State, pk.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("rebuild entry %s: %w", pk.ID, err)
+		}
+		entry.RetiredAt = pk.RetiredAt
+
+		keys[pk.ID] = entry
+	}
+
+	return keys, nil
+}
+
+func saveKeySet(path string, keys map[string]*KeyEntry) error {
+	persisted := persistedKeySet{Keys: make([]persistedKey, 0, len(keys))}
+
+	for _, entry := range keys {
+		pem, err := jwk.Pem(entry.Private)
+		if err != nil {
+			return fmt.Errorf("encode key %s: %w", entry.ID, err)
+		}
+
+		persisted.Keys = append(persisted.Keys, persistedKey{
+			ID:         entry.ID,
+			PrivatePEM: string(pem),
+			State:      entry.State,
+			CreatedAt:  entry.CreatedAt,
+			RetiredAt:  entry.RetiredAt,
+		})
+	}
+
+	raw, err := json.MarshalIndent(persisted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal keyset: %w", err)
+	}
+
+	//nolint:gosec // G306: private keyset, owner-only
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		return fmt.Errorf("write keyset: %w", err)
+	}
+
+	return nil
+}
+
 func GenerateKeyPair(privateKeyPath, publicKeyPath string) error {
 	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	if err != nil {
@@ -125,31 +402,80 @@ type AccessTokenClaims struct {
 	Role         string `json:"role"`
 	Tier         string `json:"tier"`
 	TokenVersion int    `json:"token_version"`
+	SessionID    string `json:"sid"`
+	// ActorID, when set, names the admin impersonating UserID; it is
+	// minted only by CreateImpersonationToken and surfaces as the "act"
+	// claim, following the OIDC convention for delegated actors.
+	ActorID string `json:"act,omitempty"`
+	// ReauthAt, when non-zero, is when the caller last re-entered their
+	// password via Service.Reauthenticate. It surfaces as the "reauth_at"
+	// claim; middleware.RequireReauth rejects requests where it's older
+	// than the configured window.
+	ReauthAt time.Time `json:"-"`
 }
 
+// impersonationTokenExpire bounds how long an admin's impersonation token
+// for another user stays valid, deliberately much shorter than a normal
+// session's AccessTokenExpire.
+const impersonationTokenExpire = 5 * time.Minute
+
 func (m *JWTManager) CreateAccessToken(
 	claims AccessTokenClaims,
 ) (string, error) {
+	return m.buildAccessToken(claims, m.config.AccessTokenExpire)
+}
+
+// CreateImpersonationToken issues a short-lived access token for target
+// bearing an "act" claim naming actorID, the admin performing the
+// impersonation. Downstream authorization treats it like any other access
+// token; audit logging and token review tooling use "act" to tell it apart
+// from the target's own sessions.
+func (m *JWTManager) CreateImpersonationToken(
+	target AccessTokenClaims,
+	actorID string,
+) (string, error) {
+	target.ActorID = actorID
+	return m.buildAccessToken(target, impersonationTokenExpire)
+}
+
+func (m *JWTManager) buildAccessToken(
+	claims AccessTokenClaims,
+	expire time.Duration,
+) (string, error) {
+	m.mu.RLock()
+	primary := m.keys[m.primaryID]
+	m.mu.RUnlock()
+
 	now := time.Now()
 
-	token, err := jwt.NewBuilder().
+	builder := jwt.NewBuilder().
 		JwtID(uuid.New().String()).
 		Issuer(m.config.Issuer).
 		Audience([]string{m.config.Audience}).
 		Subject(claims.UserID).
 		IssuedAt(now).
-		Expiration(now.Add(m.config.AccessTokenExpire)).
+		Expiration(now.Add(expire)).
 		NotBefore(now).
 		Claim("role", claims.Role).
 		Claim("tier", claims.Tier).
 		Claim("token_version", claims.TokenVersion).
-		Claim("type", "access").
-		Build()
+		Claim("sid", claims.SessionID).
+		Claim("type", "access")
+
+	if claims.ActorID != "" {
+		builder = builder.Claim("act", claims.ActorID)
+	}
+
+	if !claims.ReauthAt.IsZero() {
+		builder = builder.Claim("reauth_at", claims.ReauthAt.Unix())
+	}
+
+	token, err := builder.Build()
 	if err != nil {
 		return "", fmt.Errorf("build token: %w", err)
 	}
 
-	signed, err := jwt.Sign(token, jwt.WithKey(jwa.ES256(), m.privateKey))
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.ES256(), primary.Private))
 	if err != nil {
 		return "", fmt.Errorf("sign token: %w", err)
 	}
@@ -157,13 +483,64 @@ func (m *JWTManager) CreateAccessToken(
 	return string(signed), nil
 }
 
+// CreateIDToken mints an OpenID Connect ID token for userID, audienced to
+// clientID rather than the shared m.config.Audience every access token
+// carries — an ID token is proof of authentication handed to one specific
+// client, not a bearer credential accepted across this API, so it must
+// not verify against VerifyAccessToken's fixed audience check. email and
+// name are only set as claims when non-empty, since the caller is expected
+// to pass "" for any claim not covered by the granted scope (e.g. omitting
+// email when "email" wasn't requested).
+func (m *JWTManager) CreateIDToken(
+	userID, clientID, email, name string,
+	expire time.Duration,
+) (string, error) {
+	m.mu.RLock()
+	primary := m.keys[m.primaryID]
+	m.mu.RUnlock()
+
+	now := time.Now()
+
+	builder := jwt.NewBuilder().
+		JwtID(uuid.New().String()).
+		Issuer(m.config.Issuer).
+		Audience([]string{clientID}).
+		Subject(userID).
+		IssuedAt(now).
+		Expiration(now.Add(expire)).
+		NotBefore(now)
+
+	if email != "" {
+		builder = builder.Claim("email", email)
+	}
+	if name != "" {
+		builder = builder.Claim("name", name)
+	}
+
+	token, err := builder.Build()
+	if err != nil {
+		return "", fmt.Errorf("build id token: %w", err)
+	}
+
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.ES256(), primary.Private))
+	if err != nil {
+		return "", fmt.Errorf("sign id token: %w", err)
+	}
+
+	return string(signed), nil
+}
+
 func (m *JWTManager) VerifyAccessToken(
 	ctx context.Context,
 	tokenString string,
 ) (*middleware.AccessTokenClaims, error) {
+	m.mu.RLock()
+	verificationSet := m.publicJWKS
+	m.mu.RUnlock()
+
 	token, err := jwt.Parse(
 		[]byte(tokenString),
-		jwt.WithKey(jwa.ES256(), m.publicKey),
+		jwt.WithKeySet(verificationSet),
 		jwt.WithValidate(true),
 		jwt.WithIssuer(m.config.Issuer),
 		jwt.WithAudience(m.config.Audience),
@@ -216,11 +593,26 @@ func (m *JWTManager) VerifyAccessToken(
 		)
 	}
 
+	var sidStr string
+	_ = token.Get("sid", &sidStr)
+
+	var actStr string
+	_ = token.Get("act", &actStr)
+
+	var reauthAt time.Time
+	var reauthUnix float64
+	if err := token.Get("reauth_at", &reauthUnix); err == nil {
+		reauthAt = time.Unix(int64(reauthUnix), 0)
+	}
+
 	return &middleware.AccessTokenClaims{
 		UserID:       subject,
 		Role:         roleStr,
 		Tier:         tierStr,
 		TokenVersion: int(versionFloat),
+		SessionID:    sidStr,
+		ActorID:      actStr,
+		ReauthAt:     reauthAt,
 	}, nil
 }
 
@@ -235,10 +627,14 @@ func isTokenExpiredError(err error) bool {
 
 func (m *JWTManager) GetJWKSHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		m.mu.RLock()
+		set := m.publicJWKS
+		m.mu.RUnlock()
+
 		w.Header().Set("Content-Type", "application/json")
 		w.Header().Set("Cache-Control", "public, max-age=3600")
 
-		if err := json.NewEncoder(w).Encode(m.publicJWKS); err != nil {
+		if err := json.NewEncoder(w).Encode(set); err != nil {
 			http.Error(
 				w,
 				"Internal Server Error",
@@ -250,14 +646,15 @@ func (m *JWTManager) GetJWKSHandler() http.HandlerFunc {
 }
 
 func (m *JWTManager) GetPublicKey() jwk.Key {
-	return m.publicKey
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.keys[m.primaryID].Public
 }
 
 func (m *JWTManager) GetKeyID() string {
-	var kid string
-	//nolint:errcheck // key ID always set during NewJWTManager init
-	_ = m.privateKey.Get(jwk.KeyIDKey, &kid)
-	return kid
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.primaryID
 }
 
 type RefreshTokenData struct {
@@ -0,0 +1,332 @@
+// AngelaMos | 2026
+// mfa.go
+
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // RFC 6238 mandates SHA-1 for TOTP, not used for anything else
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+var (
+	ErrTOTPAlreadyEnabled  = errors.New("totp is already enabled")
+	ErrTOTPNotEnrolled     = errors.New("totp has not been enrolled")
+	ErrTOTPNotEnabled      = errors.New("totp is not enabled")
+	ErrTOTPInvalidCode     = errors.New("totp code is invalid")
+	ErrMFAChallengeInvalid = errors.New("mfa challenge is invalid or expired")
+)
+
+const (
+	totpStepSeconds = 30
+	totpSkewSteps   = 1
+	totpDigits      = 6
+	totpSecretBytes = 20 // 160 bits, per RFC 6238's recommended HMAC-SHA-1 key size
+
+	mfaChallengeTTL   = 5 * time.Minute
+	mfaChallengeBytes = 32
+
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 10
+)
+
+var totpBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// EnrollTOTP stages a fresh TOTP secret and a batch of recovery codes on
+// userID's account without enabling MFA yet — ConfirmTOTP must verify a
+// code generated from the returned secret before Login actually starts
+// challenging this user, so a botched authenticator-app scan can't lock
+// someone out of their own account.
+func (s *Service) EnrollTOTP(
+	ctx context.Context,
+	userID string,
+) (secret, otpauthURL string, recoveryCodes []string, err error) {
+	user, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("get user: %w", err)
+	}
+
+	if user.TOTPEnabled {
+		return "", "", nil, ErrTOTPAlreadyEnabled
+	}
+
+	secret, err = generateTOTPSecret()
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	recoveryCodes, err = generateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	hashes := make([]string, len(recoveryCodes))
+	for i, code := range recoveryCodes {
+		hashes[i] = core.HashToken(code)
+	}
+
+	if err := s.userProvider.SetTOTPSecret(ctx, userID, secret, hashes); err != nil {
+		return "", "", nil, fmt.Errorf("set totp secret: %w", err)
+	}
+
+	return secret, buildOTPAuthURL(s.totpIssuer, user.Email, secret), recoveryCodes, nil
+}
+
+// ConfirmTOTP verifies code against the secret EnrollTOTP staged and, on
+// success, flips totp_enabled on so Login starts issuing an mfa_required
+// challenge for this user instead of a session.
+func (s *Service) ConfirmTOTP(ctx context.Context, userID, code string) error {
+	user, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	if user.TOTPEnabled {
+		return ErrTOTPAlreadyEnabled
+	}
+
+	if user.TOTPSecret == "" {
+		return ErrTOTPNotEnrolled
+	}
+
+	if !verifyTOTPCode(user.TOTPSecret, code) {
+		return ErrTOTPInvalidCode
+	}
+
+	if err := s.userProvider.EnableTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("enable totp: %w", err)
+	}
+
+	return nil
+}
+
+// DisableTOTP requires the same proof of possession ChangePassword demands
+// of the current password: a current TOTP code or an unused recovery code,
+// either of which verifyMFACode accepts.
+func (s *Service) DisableTOTP(ctx context.Context, userID, code string) error {
+	user, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	if !user.TOTPEnabled {
+		return ErrTOTPNotEnabled
+	}
+
+	if err := s.verifyMFACode(ctx, user, code); err != nil {
+		return err
+	}
+
+	if err := s.userProvider.DisableTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("disable totp: %w", err)
+	}
+
+	return nil
+}
+
+// verifyMFACode accepts either a current TOTP code or an unused recovery
+// code, consuming the recovery code if that's what matched.
+func (s *Service) verifyMFACode(ctx context.Context, user *UserInfo, code string) error {
+	if verifyTOTPCode(user.TOTPSecret, code) {
+		return nil
+	}
+
+	remaining, matched := consumeRecoveryCode(user.RecoveryCodeHashes, code)
+	if !matched {
+		return ErrTOTPInvalidCode
+	}
+
+	if err := s.userProvider.UpdateRecoveryCodeHashes(ctx, user.ID, remaining); err != nil {
+		return fmt.Errorf("update recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+// completeMFALogin resolves req.ChallengeToken back to the user Login
+// challenged, verifies req.TOTPCode against it, and — only on success —
+// consumes the challenge and issues the session createAuthResponse would
+// have returned directly had MFA not been enabled.
+func (s *Service) completeMFALogin(
+	ctx context.Context,
+	req LoginRequest,
+	userAgent, ipAddress string,
+) (*AuthResponse, error) {
+	userID, err := s.resolveMFAChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	if err := s.verifyMFACode(ctx, user, req.TOTPCode); err != nil {
+		return nil, err
+	}
+
+	s.consumeMFAChallenge(ctx, req.ChallengeToken)
+
+	return s.createAuthResponse(ctx, user, userAgent, ipAddress, "", nil, "password")
+}
+
+// createMFAChallenge mints a single-use challenge token and maps it to
+// userID in Redis, mirroring passkey.go's newChallenge/consumeChallenge
+// pair. The mapped value is the user ID itself rather than a boolean flag,
+// since the second login step needs to resolve straight back to a user
+// without the client resending email or password.
+func (s *Service) createMFAChallenge(ctx context.Context, userID string) (string, error) {
+	raw := make([]byte, mfaChallengeBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate mfa challenge: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.redis.Set(ctx, mfaChallengeKey(token), userID, mfaChallengeTTL).Err(); err != nil {
+		return "", fmt.Errorf("store mfa challenge: %w", err)
+	}
+
+	return token, nil
+}
+
+func (s *Service) resolveMFAChallenge(ctx context.Context, token string) (string, error) {
+	userID, err := s.redis.Get(ctx, mfaChallengeKey(token)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrMFAChallengeInvalid
+	}
+	if err != nil {
+		return "", fmt.Errorf("get mfa challenge: %w", err)
+	}
+
+	return userID, nil
+}
+
+// consumeMFAChallenge deletes the challenge only after a successful
+// verification, so a mistyped code doesn't burn the user's one attempt at
+// the challenge — it can still be retried until mfaChallengeTTL expires.
+func (s *Service) consumeMFAChallenge(ctx context.Context, token string) {
+	//nolint:errcheck // best-effort cleanup; the key's own TTL is the backstop
+	_ = s.redis.Del(ctx, mfaChallengeKey(token)).Err()
+}
+
+func mfaChallengeKey(token string) string {
+	return "mfa:challenge:" + token
+}
+
+// generateTOTPSecret returns a Base32-encoded 160-bit random secret, the
+// key length RFC 6238 recommends for HMAC-SHA-1.
+func generateTOTPSecret() (string, error) {
+	raw := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate totp secret: %w", err)
+	}
+
+	return totpBase32.EncodeToString(raw), nil
+}
+
+// verifyTOTPCode checks code against the current 30-second step and the
+// one step on either side of it, the standard tolerance for clock drift
+// between the server and the authenticator app.
+func verifyTOTPCode(secret, code string) bool {
+	key, err := totpBase32.DecodeString(secret)
+	if err != nil {
+		return false
+	}
+
+	counter := time.Now().Unix() / totpStepSeconds
+
+	for _, skew := range []int64{0, -1, 1} {
+		if totpCode(key, counter+int64(skew)) == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// totpCode computes the RFC 4226 HOTP value for counter and formats it as
+// a zero-padded totpDigits-digit string, per RFC 6238 §4.
+func totpCode(key []byte, counter int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	truncated := (uint32(sum[offset]&0x7f) << 24) |
+		(uint32(sum[offset+1]) << 16) |
+		(uint32(sum[offset+2]) << 8) |
+		uint32(sum[offset+3])
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, truncated%mod)
+}
+
+// buildOTPAuthURL builds the otpauth:// URI most authenticator apps use to
+// import a TOTP secret via QR code.
+func buildOTPAuthURL(issuer, accountName, secret string) string {
+	label := issuer + ":" + accountName
+
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", strconv.Itoa(totpDigits))
+	v.Set("period", strconv.Itoa(totpStepSeconds))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", url.PathEscape(label), v.Encode())
+}
+
+// generateRecoveryCodes returns n single-use, Base32-encoded codes a user
+// can redeem in place of a TOTP code if they lose their authenticator.
+func generateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+
+	for i := range codes {
+		raw := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+		codes[i] = totpBase32.EncodeToString(raw)
+	}
+
+	return codes, nil
+}
+
+// consumeRecoveryCode looks for a hash matching code among hashes and, if
+// found, returns the remaining hashes with it removed along with matched
+// set to true — the caller is responsible for persisting the remainder so
+// the code can't be redeemed twice.
+func consumeRecoveryCode(hashes []string, code string) (remaining []string, matched bool) {
+	remaining = make([]string, 0, len(hashes))
+
+	for _, h := range hashes {
+		if !matched && core.CompareTokenHash(code, h) {
+			matched = true
+			continue
+		}
+		remaining = append(remaining, h)
+	}
+
+	return remaining, matched
+}
@@ -0,0 +1,432 @@
+// AngelaMos | 2026
+// oauth_provider.go
+
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+var (
+	ErrOAuthInvalidClient        = errors.New("invalid or unknown oauth client")
+	ErrOAuthInvalidRedirectURI   = errors.New("redirect_uri is not registered for this client")
+	ErrOAuthUnsupportedGrantType = errors.New("unsupported or unregistered grant_type")
+	ErrOAuthInvalidScope         = errors.New("requested scope exceeds what this client is allowed")
+	ErrOAuthInvalidGrant         = errors.New("authorization code is invalid, expired, or already used")
+	ErrOAuthInvalidCodeVerifier  = errors.New("code_verifier does not match code_challenge")
+	ErrOAuthUnsupportedChallenge = errors.New("only the S256 code_challenge_method is supported")
+)
+
+const (
+	oauthCodeBytes          = 32
+	authorizationCodeKeyFmt = "oauth:code:%s"
+	oauthScopeOpenID        = "openid"
+	oauthScopeProfile       = "profile"
+	oauthScopeEmail         = "email"
+)
+
+// authorizationCode is the Redis-resident record behind one issued
+// authorization code, looked up and deleted atomically by ExchangeToken so
+// a code can never be redeemed twice — the same single-use guarantee
+// Poll's device-flow delete provides, but via GetDel rather than a
+// separate Set+Del pair, since an authorization code exchanges directly
+// for tokens and a race in that window is worth closing outright.
+type authorizationCode struct {
+	ClientID            string `json:"client_id"`
+	UserID              string `json:"user_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Scope               string `json:"scope"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+}
+
+// WithOAuthProvider wires this package's OIDC authorization-server mode
+// into the service. It's optional — a Service without one simply can't
+// call Authorize/ExchangeToken/Introspect/RevokeOAuthToken — mirroring how
+// WithIdentityLinks works. OAuth protocol methods live directly on
+// *Service, rather than a separate type, so the authorization_code and
+// refresh_token grants can call s.repo/s.jwt/s.createAuthResponse and
+// reuse the exact same refresh-token storage and reuse-detection Refresh
+// already implements for password logins.
+func (s *Service) WithOAuthProvider(clients ClientRepository, cfg OAuthProviderConfig) *Service {
+	s.oauthClients = clients
+	s.oauthCfg = cfg
+	return s
+}
+
+// Authorize validates an authorization request from an already-
+// authenticated resource owner and mints a short-lived, single-use
+// authorization code bound to client, redirectURI, scope, and the PKCE
+// code_challenge, per RFC 6749 §4.1 and RFC 7636. Only the S256
+// code_challenge_method is accepted — "plain" defeats the point of PKCE
+// against an attacker who can observe the authorization request.
+func (s *Service) Authorize(
+	ctx context.Context,
+	userID, clientID, redirectURI, scope, codeChallenge, codeChallengeMethod string,
+) (string, error) {
+	if codeChallengeMethod != "S256" {
+		return "", ErrOAuthUnsupportedChallenge
+	}
+
+	client, err := s.getAuthorizableClient(ctx, clientID, "authorization_code")
+	if err != nil {
+		return "", err
+	}
+
+	if !client.AllowsRedirectURI(redirectURI) {
+		return "", ErrOAuthInvalidRedirectURI
+	}
+
+	grantedScope, err := client.ResolveScope(scope)
+	if err != nil {
+		return "", err
+	}
+
+	code, err := core.GenerateSecureToken(oauthCodeBytes)
+	if err != nil {
+		return "", fmt.Errorf("generate authorization code: %w", err)
+	}
+
+	record := authorizationCode{
+		ClientID:            client.ID,
+		UserID:              userID,
+		RedirectURI:         redirectURI,
+		Scope:               grantedScope,
+		CodeChallenge:       codeChallenge,
+		CodeChallengeMethod: codeChallengeMethod,
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("marshal authorization code: %w", err)
+	}
+
+	key := fmt.Sprintf(authorizationCodeKeyFmt, code)
+	if err := s.redis.Set(ctx, key, raw, s.oauthCfg.AuthCodeTTL).Err(); err != nil {
+		return "", fmt.Errorf("store authorization code: %w", err)
+	}
+
+	return code, nil
+}
+
+// ExchangeToken implements POST /oauth/token across the three grant types
+// this provider supports. client authentication (client_id/client_secret)
+// is required for every grant type, including refresh_token, since an
+// OAuth refresh token is still scoped to the client it was issued to.
+func (s *Service) ExchangeToken(
+	ctx context.Context,
+	req OAuthTokenRequest,
+	userAgent, ipAddress string,
+) (*OAuthTokenResponse, error) {
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret, req.GrantType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case "authorization_code":
+		return s.exchangeAuthorizationCode(ctx, client, req, userAgent, ipAddress)
+	case "refresh_token":
+		return s.exchangeRefreshToken(ctx, req, userAgent, ipAddress)
+	case "client_credentials":
+		return s.exchangeClientCredentials(client)
+	default:
+		return nil, ErrOAuthUnsupportedGrantType
+	}
+}
+
+func (s *Service) exchangeAuthorizationCode(
+	ctx context.Context,
+	client *OAuthClient,
+	req OAuthTokenRequest,
+	userAgent, ipAddress string,
+) (*OAuthTokenResponse, error) {
+	key := fmt.Sprintf(authorizationCodeKeyFmt, req.Code)
+
+	raw, err := s.redis.GetDel(ctx, key).Result()
+	if err != nil {
+		return nil, ErrOAuthInvalidGrant
+	}
+
+	var record authorizationCode
+	if err := json.Unmarshal([]byte(raw), &record); err != nil {
+		return nil, fmt.Errorf("unmarshal authorization code: %w", err)
+	}
+
+	if record.ClientID != client.ID || record.RedirectURI != req.RedirectURI {
+		return nil, ErrOAuthInvalidGrant
+	}
+
+	if !verifyCodeChallenge(record.CodeChallenge, req.CodeVerifier) {
+		return nil, ErrOAuthInvalidCodeVerifier
+	}
+
+	user, err := s.userProvider.GetByID(ctx, record.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	// createAuthResponse mints the same full-privilege access token a
+	// password login gets — AccessTokenClaims has no per-token scope
+	// concept, so record.Scope (and the Scope field below) is advisory
+	// only, matching what the client asked for and was granted, but not
+	// enforced by Authenticator against individual routes. Narrowing what
+	// an OAuth-issued access token can actually do would mean threading a
+	// scope claim through AccessTokenClaims and every route that should
+	// check it — out of scope here.
+	authResp, err := s.createAuthResponse(ctx, user, userAgent, ipAddress, "", nil, "oauth")
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &OAuthTokenResponse{
+		AccessToken:  authResp.Tokens.AccessToken,
+		RefreshToken: authResp.Tokens.RefreshToken,
+		TokenType:    authResp.Tokens.TokenType,
+		ExpiresIn:    authResp.Tokens.ExpiresIn,
+		Scope:        record.Scope,
+	}
+
+	if scopeIncludes(record.Scope, oauthScopeOpenID) {
+		var email, name string
+		if scopeIncludes(record.Scope, oauthScopeEmail) {
+			email = user.Email
+		}
+		if scopeIncludes(record.Scope, oauthScopeProfile) {
+			name = user.Name
+		}
+
+		idToken, err := s.jwt.CreateIDToken(
+			user.ID, client.ID, email, name, s.oauthCfg.IDTokenExpire,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("create id token: %w", err)
+		}
+		resp.IDToken = idToken
+	}
+
+	return resp, nil
+}
+
+// exchangeRefreshToken hands the bearer refresh token straight to Refresh,
+// so an OAuth client's refresh grant gets the exact same family-based
+// reuse detection (revoke-on-replay, idle timeout) a password login's
+// /auth/refresh call gets — there is no parallel OAuth-specific refresh
+// token mechanism. RefreshToken has no client binding of its own, so this
+// only confirms the caller authenticated as *some* registered client, not
+// that this specific refresh token was issued to it; closing that gap
+// would mean adding a client_id column to refresh_tokens and threading it
+// through every grant of a refresh token, including password login's,
+// which is a larger change than this request covers.
+func (s *Service) exchangeRefreshToken(
+	ctx context.Context,
+	req OAuthTokenRequest,
+	userAgent, ipAddress string,
+) (*OAuthTokenResponse, error) {
+	authResp, err := s.Refresh(ctx, req.RefreshToken, userAgent, ipAddress)
+	if err != nil {
+		if errors.Is(err, core.ErrTokenInvalid) ||
+			errors.Is(err, core.ErrTokenExpired) ||
+			errors.Is(err, core.ErrTokenRevoked) ||
+			errors.Is(err, ErrTokenReuse) {
+			return nil, ErrOAuthInvalidGrant
+		}
+		return nil, err
+	}
+
+	return &OAuthTokenResponse{
+		AccessToken:  authResp.Tokens.AccessToken,
+		RefreshToken: authResp.Tokens.RefreshToken,
+		TokenType:    authResp.Tokens.TokenType,
+		ExpiresIn:    authResp.Tokens.ExpiresIn,
+	}, nil
+}
+
+// exchangeClientCredentials issues an access token naming the client
+// itself rather than a human user, per RFC 6749 §4.4. No refresh token or
+// ID token is minted: there's no user session to keep alive, and no user
+// to authenticate.
+func (s *Service) exchangeClientCredentials(client *OAuthClient) (*OAuthTokenResponse, error) {
+	accessToken, err := s.jwt.CreateAccessToken(AccessTokenClaims{
+		UserID: client.ID,
+		Role:   "service",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create access token: %w", err)
+	}
+
+	return &OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.jwt.config.AccessTokenExpire / time.Second),
+		Scope:       strings.Join(client.AllowedScopes, " "),
+	}, nil
+}
+
+// Introspect implements RFC 7662: it reports whether token is a currently
+// active access token or refresh token, trying the JWT access-token
+// verifier first and falling back to a refresh-token hash lookup, since
+// the two are opaque to the caller and the spec doesn't require them to
+// say which kind they're introspecting.
+func (s *Service) Introspect(
+	ctx context.Context,
+	token, clientID, clientSecret string,
+) (*OAuthIntrospectResponse, error) {
+	if _, err := s.authenticateAnyClient(ctx, clientID, clientSecret); err != nil {
+		return nil, err
+	}
+
+	if claims, err := s.jwt.VerifyAccessToken(ctx, token); err == nil {
+		return &OAuthIntrospectResponse{
+			Active:    true,
+			Sub:       claims.UserID,
+			TokenType: "access_token",
+		}, nil
+	}
+
+	storedToken, err := s.repo.FindByHash(ctx, core.HashToken(token))
+	if err != nil || !storedToken.IsValid() {
+		return &OAuthIntrospectResponse{Active: false}, nil
+	}
+
+	return &OAuthIntrospectResponse{
+		Active:    true,
+		Sub:       storedToken.UserID,
+		TokenType: "refresh_token",
+		Exp:       storedToken.ExpiresAt.Unix(),
+	}, nil
+}
+
+// RevokeOAuthToken implements RFC 7009 for refresh tokens: it marks token
+// revoked in the repository, the same effect Logout has on the token it's
+// given. The client itself must still authenticate first, per RFC 7009
+// §2.1 — only once that succeeds does the "always report success" rule
+// below apply. Access tokens aren't handled here — VerifyAccessToken's
+// claims don't expose the token's own jti, only RevokeAccessToken's
+// caller-supplied one, so there's no way to blacklist the specific access
+// token presented without plumbing that claim through first; a short
+// AccessTokenExpire is this provider's mitigation in the meantime. Per the
+// RFC, the endpoint reports success even when token is unknown, so a
+// caller probing for valid tokens learns nothing from the response.
+func (s *Service) RevokeOAuthToken(ctx context.Context, token, clientID, clientSecret string) error {
+	if _, err := s.authenticateAnyClient(ctx, clientID, clientSecret); err != nil {
+		return err
+	}
+
+	storedToken, err := s.repo.FindByHash(ctx, core.HashToken(token))
+	if err != nil {
+		return nil
+	}
+
+	//nolint:errcheck // RFC 7009: revocation always reports success regardless
+	_ = s.repo.RevokeByID(ctx, storedToken.ID)
+	return nil
+}
+
+// getAuthorizableClient loads clientID and checks it's neither revoked nor
+// missing the requested grant type.
+func (s *Service) getAuthorizableClient(
+	ctx context.Context,
+	clientID, grantType string,
+) (*OAuthClient, error) {
+	if s.oauthClients == nil {
+		return nil, ErrOAuthInvalidClient
+	}
+
+	client, err := s.oauthClients.FindByID(ctx, clientID)
+	if err != nil {
+		return nil, ErrOAuthInvalidClient
+	}
+
+	if client.IsRevoked() {
+		return nil, ErrOAuthInvalidClient
+	}
+
+	if !client.AllowsGrantType(grantType) {
+		return nil, ErrOAuthUnsupportedGrantType
+	}
+
+	return client, nil
+}
+
+// authenticateAnyClient loads and validates clientID/clientSecret for
+// endpoints that aren't tied to a single grant type — Introspect and
+// RevokeOAuthToken accept a token that could have come from any grant this
+// client is registered for.
+func (s *Service) authenticateAnyClient(
+	ctx context.Context,
+	clientID, clientSecret string,
+) (*OAuthClient, error) {
+	if s.oauthClients == nil {
+		return nil, ErrOAuthInvalidClient
+	}
+
+	client, err := s.oauthClients.FindByID(ctx, clientID)
+	if err != nil {
+		return nil, ErrOAuthInvalidClient
+	}
+
+	if client.IsRevoked() {
+		return nil, ErrOAuthInvalidClient
+	}
+
+	valid, err := VerifyClientSecret(client, clientSecret)
+	if err != nil {
+		return nil, fmt.Errorf("verify client secret: %w", err)
+	}
+	if !valid {
+		return nil, ErrOAuthInvalidClient
+	}
+
+	return client, nil
+}
+
+// authenticateClient loads and validates clientID/clientSecret together,
+// the client_secret_post method (RFC 6749 §2.3.1) — the only one this
+// provider supports.
+func (s *Service) authenticateClient(
+	ctx context.Context,
+	clientID, clientSecret, grantType string,
+) (*OAuthClient, error) {
+	client, err := s.getAuthorizableClient(ctx, clientID, grantType)
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := VerifyClientSecret(client, clientSecret)
+	if err != nil {
+		return nil, fmt.Errorf("verify client secret: %w", err)
+	}
+	if !valid {
+		return nil, ErrOAuthInvalidClient
+	}
+
+	return client, nil
+}
+
+// verifyCodeChallenge recomputes BASE64URL(SHA256(verifier)) and compares
+// it against challenge in constant time, per RFC 7636 §4.6.
+func verifyCodeChallenge(challenge, verifier string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
+
+func scopeIncludes(scope, target string) bool {
+	for _, s := range strings.Fields(scope) {
+		if s == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+)
+
+func TestVerifyCodeChallenge(t *testing.T) {
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	if !verifyCodeChallenge(challenge, verifier) {
+		t.Fatal("expected BASE64URL(SHA256(verifier)) to match its own challenge")
+	}
+
+	t.Run("rejects a verifier that doesn't hash to the challenge", func(t *testing.T) {
+		if verifyCodeChallenge(challenge, "some-other-verifier") {
+			t.Fatal("expected a mismatched verifier to fail")
+		}
+	})
+
+	t.Run("rejects the plain verifier passed off as its own challenge", func(t *testing.T) {
+		// Guards against a client (or attacker) sending code_challenge ==
+		// code_verifier unhashed — PKCE's whole point is that only the
+		// hash, not the verifier itself, is observable during Authorize.
+		if verifyCodeChallenge(verifier, verifier) {
+			t.Fatal("the raw verifier must not verify against itself as the challenge")
+		}
+	})
+
+	t.Run("rejects an empty verifier against a real challenge", func(t *testing.T) {
+		if verifyCodeChallenge(challenge, "") {
+			t.Fatal("an empty verifier must not satisfy a non-empty challenge")
+		}
+	})
+}
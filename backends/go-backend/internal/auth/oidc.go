@@ -0,0 +1,670 @@
+// AngelaMos | 2026
+// oidc.go
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+var (
+	ErrProviderUnknown  = errors.New("unknown identity provider")
+	ErrOIDCStateInvalid = errors.New("oauth state invalid or expired")
+)
+
+// ExternalIdentity is what every IdentityProvider implementation resolves a
+// successful callback down to, regardless of how the upstream provider
+// shapes its userinfo response.
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// IdentityProvider is implemented by each social-login backend (Google,
+// GitHub, generic OIDC). StartAuth builds the redirect URL and persists
+// whatever state the provider needs to validate on callback; HandleCallback
+// exchanges the authorization code and resolves the caller's identity.
+type IdentityProvider interface {
+	Name() string
+	// StartAuth returns the authorization URL to redirect the browser to,
+	// along with the opaque state token embedded in it — the caller binds
+	// that token to a short-lived cookie to defend the callback against CSRF.
+	StartAuth(ctx context.Context, redirectTo string) (authURL, state string, err error)
+	HandleCallback(ctx context.Context, code, state string) (*ExternalIdentity, error)
+}
+
+// IdentityLink maps an external (provider, subject) pair to a local user,
+// stored so a single account can be reached through multiple providers.
+type IdentityLink struct {
+	ID        string    `db:"id"`
+	UserID    string    `db:"user_id"`
+	Provider  string    `db:"provider"`
+	Subject   string    `db:"subject"`
+	Email     string    `db:"email"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type IdentityLinkRepository interface {
+	Create(ctx context.Context, link *IdentityLink) error
+	FindByProviderSubject(
+		ctx context.Context,
+		provider, subject string,
+	) (*IdentityLink, error)
+	ListForUser(ctx context.Context, userID string) ([]IdentityLink, error)
+	Delete(ctx context.Context, userID, provider string) error
+}
+
+type identityLinkRepository struct {
+	db core.DBTX
+}
+
+func NewIdentityLinkRepository(db core.DBTX) IdentityLinkRepository {
+	return &identityLinkRepository{db: db}
+}
+
+func (r *identityLinkRepository) Create(ctx context.Context, link *IdentityLink) error {
+	query := `
+		INSERT INTO identity_links (id, user_id, provider, subject, email)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`
+
+	err := r.db.GetContext(ctx, &link.CreatedAt, query,
+		link.ID, link.UserID, link.Provider, link.Subject, link.Email,
+	)
+	if err != nil {
+		return fmt.Errorf("create identity link: %w", err)
+	}
+
+	return nil
+}
+
+func (r *identityLinkRepository) FindByProviderSubject(
+	ctx context.Context,
+	provider, subject string,
+) (*IdentityLink, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at
+		FROM identity_links
+		WHERE provider = $1 AND subject = $2`
+
+	var link IdentityLink
+	err := r.db.GetContext(ctx, &link, query, provider, subject)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("find identity link: %w", core.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find identity link: %w", err)
+	}
+
+	return &link, nil
+}
+
+func (r *identityLinkRepository) ListForUser(
+	ctx context.Context,
+	userID string,
+) ([]IdentityLink, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at
+		FROM identity_links
+		WHERE user_id = $1
+		ORDER BY created_at ASC`
+
+	var links []IdentityLink
+	if err := r.db.SelectContext(ctx, &links, query, userID); err != nil {
+		return nil, fmt.Errorf("list identity links: %w", err)
+	}
+
+	return links, nil
+}
+
+func (r *identityLinkRepository) Delete(ctx context.Context, userID, provider string) error {
+	query := `DELETE FROM identity_links WHERE user_id = $1 AND provider = $2`
+
+	result, err := r.db.ExecContext(ctx, query, userID, provider)
+	if err != nil {
+		return fmt.Errorf("delete identity link: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete identity link: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("delete identity link: %w", core.ErrNotFound)
+	}
+
+	return nil
+}
+
+// ProviderRegistry holds the social-login connectors enabled for this
+// deployment, keyed by provider name ("google", "github", ...), so the
+// handler can look one up by the {provider} path parameter.
+type ProviderRegistry struct {
+	providers map[string]IdentityProvider
+}
+
+func NewProviderRegistry(providers ...IdentityProvider) *ProviderRegistry {
+	reg := &ProviderRegistry{providers: make(map[string]IdentityProvider, len(providers))}
+	for _, p := range providers {
+		reg.providers[p.Name()] = p
+	}
+	return reg
+}
+
+func (r *ProviderRegistry) Get(name string) (IdentityProvider, error) {
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, ErrProviderUnknown
+	}
+	return provider, nil
+}
+
+type oauthPKCEState struct {
+	CodeVerifier string `json:"code_verifier"`
+	Nonce        string `json:"nonce"`
+	RedirectTo   string `json:"redirect_to"`
+}
+
+// oauthStateStore persists the (state, code_verifier, nonce) tuple for the
+// duration of the authorization-code round trip. Redis is the natural home
+// for this: single flight, short TTL, no durability requirement.
+type oauthStateStore struct {
+	redis *redis.Client
+	ttl   time.Duration
+}
+
+func NewOAuthStateStore(redisClient *redis.Client) *oauthStateStore {
+	return &oauthStateStore{redis: redisClient, ttl: 10 * time.Minute}
+}
+
+func (s *oauthStateStore) put(ctx context.Context, provider string, state oauthPKCEState) (string, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate state: %w", err)
+	}
+
+	stateToken := base64.RawURLEncoding.EncodeToString(raw)
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("marshal oauth state: %w", err)
+	}
+
+	key := oauthStateKey(provider, stateToken)
+	if err := s.redis.Set(ctx, key, payload, s.ttl).Err(); err != nil {
+		return "", fmt.Errorf("store oauth state: %w", err)
+	}
+
+	return stateToken, nil
+}
+
+func (s *oauthStateStore) take(ctx context.Context, provider, stateToken string) (*oauthPKCEState, error) {
+	key := oauthStateKey(provider, stateToken)
+
+	payload, err := s.redis.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrOIDCStateInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load oauth state: %w", err)
+	}
+
+	//nolint:errcheck // single-use regardless of delete outcome
+	_ = s.redis.Del(ctx, key)
+
+	var state oauthPKCEState
+	if err := json.Unmarshal(payload, &state); err != nil {
+		return nil, fmt.Errorf("parse oauth state: %w", err)
+	}
+
+	return &state, nil
+}
+
+func oauthStateKey(provider, stateToken string) string {
+	return fmt.Sprintf("oauth:state:%s:%s", provider, stateToken)
+}
+
+func newPKCEVerifier() (verifier, challenge string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", fmt.Errorf("generate code verifier: %w", err)
+	}
+
+	verifier = base64.RawURLEncoding.EncodeToString(raw)
+	challenge = s256Challenge(verifier)
+	return verifier, challenge, nil
+}
+
+// s256Challenge derives the PKCE code_challenge from a code_verifier per
+// RFC 7636 section 4.2 (S256 transform: base64url(sha256(verifier))).
+func s256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// OAuthProviderConfig describes one configured social-login backend; Google
+// and GitHub are pre-wired shapes of the same OAuth2 + discovery flow.
+type OAuthProviderConfig struct {
+	Name         string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	// AuthURL/TokenURL/UserInfoURL/Issuer are filled from either the
+	// provider's known endpoints or an OIDC discovery document.
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+	Issuer      string
+	JWKS        jwk.Set
+}
+
+// oidcProvider implements IdentityProvider for any authorization-code +
+// PKCE OIDC-compliant issuer (Google, Okta, a generic discovery-driven IdP).
+type oidcProvider struct {
+	cfg        OAuthProviderConfig
+	states     *oauthStateStore
+	httpClient *http.Client
+}
+
+func NewOIDCProvider(cfg OAuthProviderConfig, states *oauthStateStore) IdentityProvider {
+	return &oidcProvider{cfg: cfg, states: states, httpClient: http.DefaultClient}
+}
+
+// NewGoogleProvider wires up Google's well-known OAuth2/OIDC endpoints and
+// fetches its current signing keys, so callers only need to supply a
+// client ID, secret and redirect URL.
+func NewGoogleProvider(
+	ctx context.Context,
+	clientID, clientSecret, redirectURL string,
+	states *oauthStateStore,
+) (IdentityProvider, error) {
+	const googleJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+	keySet, err := jwk.Fetch(ctx, googleJWKSURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetch google jwks: %w", err)
+	}
+
+	return NewOIDCProvider(OAuthProviderConfig{
+		Name:         "google",
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+		AuthURL:      "https://accounts.google.com/o/oauth2/v2/auth",
+		TokenURL:     "https://oauth2.googleapis.com/token",
+		Issuer:       "https://accounts.google.com",
+		JWKS:         keySet,
+	}, states), nil
+}
+
+// oidcDiscoveryDocument is the subset of a provider's
+// `/.well-known/openid-configuration` response NewDiscoveredOIDCProvider
+// needs to build an OAuthProviderConfig.
+type oidcDiscoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewDiscoveredOIDCProvider builds an IdentityProvider for any standard
+// OIDC issuer by fetching its discovery document and JWKS, so deployments
+// can plug in an arbitrary IdP (Okta, Auth0, a corporate SSO tenant) from
+// config alone instead of hardcoding endpoints the way NewGoogleProvider
+// does.
+func NewDiscoveredOIDCProvider(
+	ctx context.Context,
+	connectorID, clientID, clientSecret, redirectURL, discoveryURL string,
+	scopes []string,
+	states *oauthStateStore,
+) (IdentityProvider, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build discovery request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch discovery document: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only response body
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery endpoint returned %d", resp.StatusCode)
+	}
+
+	var doc oidcDiscoveryDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode discovery document: %w", err)
+	}
+
+	keySet, err := jwk.Fetch(ctx, doc.JWKSURI)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	return NewOIDCProvider(OAuthProviderConfig{
+		Name:         connectorID,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       scopes,
+		AuthURL:      doc.AuthorizationEndpoint,
+		TokenURL:     doc.TokenEndpoint,
+		Issuer:       doc.Issuer,
+		JWKS:         keySet,
+	}, states), nil
+}
+
+func (p *oidcProvider) Name() string { return p.cfg.Name }
+
+func (p *oidcProvider) StartAuth(ctx context.Context, redirectTo string) (string, string, error) {
+	verifier, challenge, err := newPKCEVerifier()
+	if err != nil {
+		return "", "", err
+	}
+
+	nonce, err := core.GenerateSecureToken(16)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := p.states.put(ctx, p.cfg.Name, oauthPKCEState{
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		RedirectTo:   redirectTo,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	values := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	return p.cfg.AuthURL + "?" + values.Encode(), state, nil
+}
+
+func (p *oidcProvider) HandleCallback(
+	ctx context.Context,
+	code, state string,
+) (*ExternalIdentity, error) {
+	pkce, err := p.states.take(ctx, p.cfg.Name, state)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenSet, err := p.exchangeCode(ctx, code, pkce.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := jwt.Parse(
+		[]byte(tokenSet.IDToken),
+		jwt.WithKeySet(p.cfg.JWKS),
+		jwt.WithValidate(true),
+		jwt.WithIssuer(p.cfg.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("verify id token: %w", err)
+	}
+
+	var nonceClaim string
+	if err := idToken.Get("nonce", &nonceClaim); err != nil || nonceClaim != pkce.Nonce {
+		return nil, fmt.Errorf("id token nonce mismatch")
+	}
+
+	subject, _ := idToken.Subject()
+
+	var email string
+	_ = idToken.Get("email", &email) //nolint:errcheck // optional claim
+
+	var emailVerified bool
+	_ = idToken.Get("email_verified", &emailVerified) //nolint:errcheck // optional claim
+
+	var name string
+	_ = idToken.Get("name", &name) //nolint:errcheck // optional claim
+
+	return &ExternalIdentity{
+		Provider:      p.cfg.Name,
+		Subject:       subject,
+		Email:         email,
+		EmailVerified: emailVerified,
+		Name:          name,
+	}, nil
+}
+
+type oauthTokenSet struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+}
+
+func (p *oidcProvider) exchangeCode(
+	ctx context.Context,
+	code, codeVerifier string,
+) (*oauthTokenSet, error) {
+	values := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"client_secret": {p.cfg.ClientSecret},
+		"code_verifier": {codeVerifier},
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, p.cfg.TokenURL, strings.NewReader(values.Encode()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("exchange code: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only response body
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned %d", resp.StatusCode)
+	}
+
+	var tokenSet oauthTokenSet
+	if err := json.NewDecoder(resp.Body).Decode(&tokenSet); err != nil {
+		return nil, fmt.Errorf("decode token response: %w", err)
+	}
+
+	return &tokenSet, nil
+}
+
+// githubProvider implements IdentityProvider for GitHub's OAuth2 apps,
+// which don't issue an ID token — identity is resolved via the REST API.
+type githubProvider struct {
+	cfg        OAuthProviderConfig
+	states     *oauthStateStore
+	httpClient *http.Client
+}
+
+func NewGitHubProvider(cfg OAuthProviderConfig, states *oauthStateStore) IdentityProvider {
+	cfg.Name = "github"
+	if cfg.AuthURL == "" {
+		cfg.AuthURL = "https://github.com/login/oauth/authorize"
+	}
+	if cfg.TokenURL == "" {
+		cfg.TokenURL = "https://github.com/login/oauth/access_token"
+	}
+	return &githubProvider{cfg: cfg, states: states, httpClient: http.DefaultClient}
+}
+
+func (p *githubProvider) Name() string { return "github" }
+
+func (p *githubProvider) StartAuth(ctx context.Context, redirectTo string) (string, string, error) {
+	state, err := p.states.put(ctx, p.cfg.Name, oauthPKCEState{RedirectTo: redirectTo})
+	if err != nil {
+		return "", "", err
+	}
+
+	values := url.Values{
+		"client_id":    {p.cfg.ClientID},
+		"redirect_uri": {p.cfg.RedirectURL},
+		"scope":        {strings.Join(p.cfg.Scopes, " ")},
+		"state":        {state},
+	}
+
+	return p.cfg.AuthURL + "?" + values.Encode(), state, nil
+}
+
+func (p *githubProvider) HandleCallback(
+	ctx context.Context,
+	code, state string,
+) (*ExternalIdentity, error) {
+	if _, err := p.states.take(ctx, p.cfg.Name, state); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := p.exchangeCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	type githubUser struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+
+	var user githubUser
+	if err := p.getJSON(ctx, "https://api.github.com/user", accessToken, &user); err != nil {
+		return nil, err
+	}
+
+	email := user.Email
+	emailVerified := false
+
+	if email == "" {
+		type githubEmail struct {
+			Email    string `json:"email"`
+			Primary  bool   `json:"primary"`
+			Verified bool   `json:"verified"`
+		}
+
+		var emails []githubEmail
+		if err := p.getJSON(ctx, "https://api.github.com/user/emails", accessToken, &emails); err == nil {
+			for _, e := range emails {
+				if e.Primary {
+					email = e.Email
+					emailVerified = e.Verified
+					break
+				}
+			}
+		}
+	}
+
+	return &ExternalIdentity{
+		Provider:      "github",
+		Subject:       fmt.Sprintf("%d", user.ID),
+		Email:         email,
+		EmailVerified: emailVerified,
+		Name:          firstNonEmpty(user.Name, user.Login),
+	}, nil
+}
+
+func (p *githubProvider) exchangeCode(ctx context.Context, code string) (string, error) {
+	values := url.Values{
+		"client_id":     {p.cfg.ClientID},
+		"client_secret": {p.cfg.ClientSecret},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, p.cfg.TokenURL, strings.NewReader(values.Encode()),
+	)
+	if err != nil {
+		return "", fmt.Errorf("build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("exchange code: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only response body
+
+	var tokenSet oauthTokenSet
+	if err := json.NewDecoder(resp.Body).Decode(&tokenSet); err != nil {
+		return "", fmt.Errorf("decode token response: %w", err)
+	}
+
+	return tokenSet.AccessToken, nil
+}
+
+func (p *githubProvider) getJSON(ctx context.Context, url, accessToken string, dest any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request %s: %w", url, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only response body
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s returned %d: %s", url, resp.StatusCode, body)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
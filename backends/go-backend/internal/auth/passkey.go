@@ -0,0 +1,608 @@
+// AngelaMos | 2026
+// passkey.go
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+var (
+	ErrChallengeExpired    = errors.New("challenge expired or already used")
+	ErrOriginMismatch      = errors.New("origin mismatch")
+	ErrRPIDHashMismatch    = errors.New("rp id hash mismatch")
+	ErrCredentialCloned    = errors.New("authenticator sign counter did not advance")
+	ErrCredentialNotFound  = errors.New("passkey credential not found")
+	ErrNoPasskeyForAccount = errors.New("account has no registered passkeys")
+)
+
+const (
+	challengeTTL    = 5 * time.Minute
+	challengeLength = 32
+)
+
+// PasskeyRepository persists WebAuthn credentials keyed by their raw,
+// base64url-encoded credential ID.
+type PasskeyRepository interface {
+	Create(ctx context.Context, cred *PasskeyCredential) error
+	FindByCredentialID(
+		ctx context.Context,
+		credentialID string,
+	) (*PasskeyCredential, error)
+	ListForUser(ctx context.Context, userID string) ([]PasskeyCredential, error)
+	UpdateSignCount(ctx context.Context, id string, signCount uint32) error
+	Delete(ctx context.Context, userID, credentialID string) error
+}
+
+type passkeyRepository struct {
+	db core.DBTX
+}
+
+func NewPasskeyRepository(db core.DBTX) PasskeyRepository {
+	return &passkeyRepository{db: db}
+}
+
+func (r *passkeyRepository) Create(
+	ctx context.Context,
+	cred *PasskeyCredential,
+) error {
+	query := `
+		INSERT INTO passkey_credentials (
+			id, user_id, name, credential_id, public_key, sign_count,
+			aaguid, transports
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+		RETURNING created_at`
+
+	err := r.db.GetContext(ctx, &cred.CreatedAt, query,
+		cred.ID,
+		cred.UserID,
+		cred.Name,
+		cred.CredentialID,
+		cred.PublicKey,
+		cred.SignCount,
+		cred.AAGUID,
+		cred.Transports,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return fmt.Errorf("create passkey: credential already registered")
+		}
+		return fmt.Errorf("create passkey: %w", err)
+	}
+
+	return nil
+}
+
+func (r *passkeyRepository) FindByCredentialID(
+	ctx context.Context,
+	credentialID string,
+) (*PasskeyCredential, error) {
+	query := `
+		SELECT id, user_id, name, credential_id, public_key, sign_count,
+		       aaguid, transports, created_at, last_used_at
+		FROM passkey_credentials
+		WHERE credential_id = $1`
+
+	var cred PasskeyCredential
+	err := r.db.GetContext(ctx, &cred, query, credentialID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("find passkey: %w", ErrCredentialNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find passkey: %w", err)
+	}
+
+	return &cred, nil
+}
+
+func (r *passkeyRepository) ListForUser(
+	ctx context.Context,
+	userID string,
+) ([]PasskeyCredential, error) {
+	query := `
+		SELECT id, user_id, name, credential_id, public_key, sign_count,
+		       aaguid, transports, created_at, last_used_at
+		FROM passkey_credentials
+		WHERE user_id = $1
+		ORDER BY created_at DESC`
+
+	var creds []PasskeyCredential
+	if err := r.db.SelectContext(ctx, &creds, query, userID); err != nil {
+		return nil, fmt.Errorf("list passkeys: %w", err)
+	}
+
+	return creds, nil
+}
+
+func (r *passkeyRepository) UpdateSignCount(
+	ctx context.Context,
+	id string,
+	signCount uint32,
+) error {
+	query := `
+		UPDATE passkey_credentials
+		SET sign_count = $2, last_used_at = NOW()
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id, signCount)
+	if err != nil {
+		return fmt.Errorf("update sign count: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update sign count: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("update sign count: %w", ErrCredentialNotFound)
+	}
+
+	return nil
+}
+
+func (r *passkeyRepository) Delete(
+	ctx context.Context,
+	userID, credentialID string,
+) error {
+	query := `
+		DELETE FROM passkey_credentials
+		WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, credentialID, userID)
+	if err != nil {
+		return fmt.Errorf("delete passkey: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete passkey: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("delete passkey: %w", ErrCredentialNotFound)
+	}
+
+	return nil
+}
+
+// PasskeyService implements WebAuthn registration and assertion ceremonies
+// on top of a PasskeyRepository, backed by Redis for single-use challenges.
+type PasskeyService struct {
+	repo   PasskeyRepository
+	redis  *redis.Client
+	rpID   string
+	rpName string
+	origin string
+}
+
+func NewPasskeyService(
+	repo PasskeyRepository,
+	redisClient *redis.Client,
+	rpID, rpName, origin string,
+) *PasskeyService {
+	return &PasskeyService{
+		repo:   repo,
+		redis:  redisClient,
+		rpID:   rpID,
+		rpName: rpName,
+		origin: origin,
+	}
+}
+
+func (s *PasskeyService) BeginRegistration(
+	ctx context.Context,
+	userID string,
+) (*BeginRegistrationResponse, error) {
+	challenge, err := s.newChallenge(ctx, "reg", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BeginRegistrationResponse{
+		Challenge: challenge,
+		RPID:      s.rpID,
+		RPName:    s.rpName,
+		UserID:    userID,
+		Timeout:   int(challengeTTL / time.Millisecond),
+	}, nil
+}
+
+func (s *PasskeyService) FinishRegistration(
+	ctx context.Context,
+	userID string,
+	req FinishRegistrationRequest,
+) (*PasskeyResponse, error) {
+	if err := s.consumeChallenge(ctx, "reg", userID, req.Challenge); err != nil {
+		return nil, err
+	}
+
+	clientData, err := decodeClientData(req.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyClientData(clientData, "webauthn.create", req.Challenge); err != nil {
+		return nil, err
+	}
+
+	attestation, err := decodeAttestationObject(req.AttestationObject)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyRPIDHash(attestation.RPIDHash); err != nil {
+		return nil, err
+	}
+
+	cred := &PasskeyCredential{
+		ID:           attestation.CredentialID,
+		UserID:       userID,
+		Name:         req.Name,
+		CredentialID: attestation.CredentialID,
+		PublicKey:    attestation.PublicKeyCOSE,
+		SignCount:    attestation.SignCount,
+		AAGUID:       attestation.AAGUID,
+		Transports:   attestation.Transports,
+	}
+
+	if err := s.repo.Create(ctx, cred); err != nil {
+		return nil, err
+	}
+
+	return &PasskeyResponse{
+		ID:         cred.ID,
+		Name:       cred.Name,
+		AAGUID:     cred.AAGUID,
+		Transports: cred.Transports,
+		CreatedAt:  cred.CreatedAt,
+	}, nil
+}
+
+func (s *PasskeyService) BeginAssertion(
+	ctx context.Context,
+	allowCredentials []string,
+) (*BeginAssertionResponse, error) {
+	challenge, err := s.newChallenge(ctx, "assert", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &BeginAssertionResponse{
+		Challenge:        challenge,
+		RPID:             s.rpID,
+		AllowCredentials: allowCredentials,
+		Timeout:          int(challengeTTL / time.Millisecond),
+	}, nil
+}
+
+func (s *PasskeyService) FinishAssertion(
+	ctx context.Context,
+	req FinishAssertionRequest,
+) (*PasskeyCredential, error) {
+	if err := s.consumeChallenge(ctx, "assert", "", req.Challenge); err != nil {
+		return nil, err
+	}
+
+	clientData, err := decodeClientData(req.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyClientData(clientData, "webauthn.get", req.Challenge); err != nil {
+		return nil, err
+	}
+
+	cred, err := s.repo.FindByCredentialID(ctx, req.CredentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	authData, err := decodeAuthenticatorData(req.AuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyRPIDHash(authData.RPIDHash); err != nil {
+		return nil, err
+	}
+
+	if !verifyAssertionSignature(cred.PublicKey, authData, req.ClientDataJSON, req.Signature) {
+		return nil, fmt.Errorf("verify assertion: %w", core.ErrUnauthorized)
+	}
+
+	if !cred.UpdateSignCount(authData.SignCount) {
+		return nil, ErrCredentialCloned
+	}
+
+	if err := s.repo.UpdateSignCount(ctx, cred.ID, cred.SignCount); err != nil {
+		return nil, err
+	}
+
+	return cred, nil
+}
+
+func (s *PasskeyService) ListPasskeys(
+	ctx context.Context,
+	userID string,
+) ([]PasskeyCredential, error) {
+	return s.repo.ListForUser(ctx, userID)
+}
+
+func (s *PasskeyService) RevokePasskey(
+	ctx context.Context,
+	userID, credentialID string,
+) error {
+	return s.repo.Delete(ctx, userID, credentialID)
+}
+
+func (s *PasskeyService) newChallenge(
+	ctx context.Context,
+	purpose, userID string,
+) (string, error) {
+	raw := make([]byte, challengeLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate challenge: %w", err)
+	}
+
+	challenge := base64.RawURLEncoding.EncodeToString(raw)
+	key := challengeKey(purpose, userID, challenge)
+
+	if err := s.redis.Set(ctx, key, "1", challengeTTL).Err(); err != nil {
+		return "", fmt.Errorf("store challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+func (s *PasskeyService) consumeChallenge(
+	ctx context.Context,
+	purpose, userID, challenge string,
+) error {
+	key := challengeKey(purpose, userID, challenge)
+
+	deleted, err := s.redis.Del(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("consume challenge: %w", err)
+	}
+
+	if deleted == 0 {
+		return ErrChallengeExpired
+	}
+
+	return nil
+}
+
+func (s *PasskeyService) verifyClientData(
+	clientData *clientDataJSON,
+	wantType, wantChallenge string,
+) error {
+	if clientData.Type != wantType {
+		return fmt.Errorf("unexpected ceremony type %q", clientData.Type)
+	}
+
+	if clientData.Challenge != wantChallenge {
+		return fmt.Errorf("challenge mismatch")
+	}
+
+	if clientData.Origin != s.origin {
+		return ErrOriginMismatch
+	}
+
+	return nil
+}
+
+// verifyRPIDHash checks rpIDHash against SHA-256(s.rpID), the
+// WebAuthn-mandated proof that the authenticator signed for this relying
+// party and not one an attacker substituted — distinct from (and in
+// addition to) verifyClientData's Origin check, which only confirms what
+// the browser claims it navigated to.
+func (s *PasskeyService) verifyRPIDHash(rpIDHash []byte) error {
+	expected := sha256.Sum256([]byte(s.rpID))
+	if !bytes.Equal(rpIDHash, expected[:]) {
+		return ErrRPIDHashMismatch
+	}
+	return nil
+}
+
+func challengeKey(purpose, userID, challenge string) string {
+	if userID == "" {
+		return fmt.Sprintf("passkey:challenge:%s:%s", purpose, challenge)
+	}
+	return fmt.Sprintf("passkey:challenge:%s:%s:%s", purpose, userID, challenge)
+}
+
+type clientDataJSON struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+func decodeClientData(encoded string) (*clientDataJSON, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode client data: %w", err)
+	}
+
+	var cd clientDataJSON
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return nil, fmt.Errorf("parse client data: %w", err)
+	}
+
+	return &cd, nil
+}
+
+type attestationResult struct {
+	CredentialID  string
+	PublicKeyCOSE []byte
+	SignCount     uint32
+	AAGUID        string
+	Transports    []string
+	RPIDHash      []byte
+}
+
+// decodeAttestationObject parses the CBOR attestation object produced by
+// navigator.credentials.create() and extracts the COSE public key, sign
+// counter and AAGUID from the authenticator data.
+func decodeAttestationObject(encoded string) (*attestationResult, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode attestation object: %w", err)
+	}
+
+	var attestation struct {
+		AuthData []byte         `cbor:"authData"`
+		Fmt      string         `cbor:"fmt"`
+		AttStmt  map[string]any `cbor:"attStmt"`
+	}
+	if err := cbor.Unmarshal(raw, &attestation); err != nil {
+		return nil, fmt.Errorf("parse attestation object: %w", err)
+	}
+
+	authData, err := parseAttestedAuthenticatorData(attestation.AuthData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attestationResult{
+		CredentialID:  base64.RawURLEncoding.EncodeToString(authData.CredentialID),
+		PublicKeyCOSE: authData.PublicKeyCOSE,
+		SignCount:     authData.SignCount,
+		AAGUID:        authData.AAGUID,
+		Transports:    []string{"internal", "hybrid"},
+		RPIDHash:      authData.RPIDHash,
+	}, nil
+}
+
+type authenticatorData struct {
+	SignCount     uint32
+	CredentialID  []byte
+	PublicKeyCOSE []byte
+	AAGUID        string
+	RPIDHash      []byte
+	// Raw is the full authData byte string the authenticator returned —
+	// rpIdHash || flags || signCount || [attestedCredentialData]. The
+	// WebAuthn assertion signature is computed over this entire string,
+	// not just RPIDHash, so it must be retained verbatim for
+	// verifyAssertionSignature.
+	Raw []byte
+}
+
+// parseAttestedAuthenticatorData unpacks the binary authData structure:
+// rpIdHash(32) || flags(1) || signCount(4) || [attestedCredentialData].
+func parseAttestedAuthenticatorData(data []byte) (*authenticatorData, error) {
+	const minLen = 37
+	if len(data) < minLen {
+		return nil, fmt.Errorf("authenticator data too short")
+	}
+
+	rpIDHash := data[0:32]
+	flags := data[32]
+	signCount := uint32(data[33])<<24 | uint32(data[34])<<16 |
+		uint32(data[35])<<8 | uint32(data[36])
+
+	const flagAttestedCredData = 0x40
+	if flags&flagAttestedCredData == 0 {
+		return &authenticatorData{SignCount: signCount, RPIDHash: rpIDHash, Raw: data}, nil
+	}
+
+	const aaguidOffset = 37
+	const aaguidLen = 16
+	if len(data) < aaguidOffset+aaguidLen+2 {
+		return nil, fmt.Errorf("attested credential data truncated")
+	}
+
+	aaguid := data[aaguidOffset : aaguidOffset+aaguidLen]
+	credIDLen := int(data[aaguidOffset+aaguidLen])<<8 | int(data[aaguidOffset+aaguidLen+1])
+
+	credIDStart := aaguidOffset + aaguidLen + 2
+	if len(data) < credIDStart+credIDLen {
+		return nil, fmt.Errorf("credential id truncated")
+	}
+
+	credentialID := data[credIDStart : credIDStart+credIDLen]
+	publicKeyCOSE := data[credIDStart+credIDLen:]
+
+	return &authenticatorData{
+		SignCount:     signCount,
+		CredentialID:  credentialID,
+		PublicKeyCOSE: publicKeyCOSE,
+		AAGUID:        fmt.Sprintf("%x", aaguid),
+		RPIDHash:      rpIDHash,
+		Raw:           data,
+	}, nil
+}
+
+func decodeAuthenticatorData(encoded string) (*authenticatorData, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode authenticator data: %w", err)
+	}
+
+	return parseAttestedAuthenticatorData(raw)
+}
+
+// verifyAssertionSignature checks the ECDSA (P-256/SHA-256) signature over
+// authenticatorData || SHA-256(clientDataJSON), the standard WebAuthn
+// signed-data construction for ES256 credentials.
+func verifyAssertionSignature(
+	publicKeyCOSE []byte,
+	authData *authenticatorData,
+	clientDataJSON, signatureB64 string,
+) bool {
+	pubKey, err := coseToECDSAPublicKey(publicKeyCOSE)
+	if err != nil {
+		return false
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(signatureB64)
+	if err != nil {
+		return false
+	}
+
+	clientDataHash := sha256.Sum256([]byte(clientDataJSON))
+
+	signedData := make([]byte, 0, len(authData.Raw)+32)
+	signedData = append(signedData, authData.Raw...)
+	signedData = append(signedData, clientDataHash[:]...)
+	digest := sha256.Sum256(signedData)
+
+	return ecdsa.VerifyASN1(pubKey, digest[:], signature)
+}
+
+// coseToECDSAPublicKey decodes a COSE_Key (CBOR map, kty=EC2, crv=P-256)
+// into a usable crypto/ecdsa public key.
+func coseToECDSAPublicKey(coseKey []byte) (*ecdsa.PublicKey, error) {
+	var key struct {
+		X []byte `cbor:"-2"`
+		Y []byte `cbor:"-3"`
+	}
+	if err := cbor.Unmarshal(coseKey, &key); err != nil {
+		return nil, fmt.Errorf("parse COSE key: %w", err)
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(key.X),
+		Y:     new(big.Int).SetBytes(key.Y),
+	}, nil
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// coseKey mirrors the anonymous struct coseToECDSAPublicKey decodes, so
+// tests can produce a COSE_Key byte string that round-trips through the
+// same (de)serialization the production code uses.
+type coseKey struct {
+	X []byte `cbor:"-2"`
+	Y []byte `cbor:"-3"`
+}
+
+func encodeCOSEKey(t *testing.T, pub *ecdsa.PublicKey) []byte {
+	t.Helper()
+	raw, err := cbor.Marshal(coseKey{X: pub.X.Bytes(), Y: pub.Y.Bytes()})
+	if err != nil {
+		t.Fatalf("encode COSE key: %v", err)
+	}
+	return raw
+}
+
+// signAssertion builds the exact signed-data construction
+// verifyAssertionSignature expects (authData.Raw || SHA-256(clientDataJSON))
+// and signs its SHA-256 digest, mirroring an authenticator's ES256
+// assertion signature.
+func signAssertion(t *testing.T, priv *ecdsa.PrivateKey, authData *authenticatorData, clientDataJSON string) string {
+	t.Helper()
+	clientDataHash := sha256.Sum256([]byte(clientDataJSON))
+	signedData := make([]byte, 0, len(authData.Raw)+32)
+	signedData = append(signedData, authData.Raw...)
+	signedData = append(signedData, clientDataHash[:]...)
+	digest := sha256.Sum256(signedData)
+
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatalf("sign assertion: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestVerifyAssertionSignature(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	publicKeyCOSE := encodeCOSEKey(t, &priv.PublicKey)
+
+	rpIDHash := sha256.Sum256([]byte("example.com"))
+	authData := &authenticatorData{
+		RPIDHash: rpIDHash[:],
+		Raw:      append(append([]byte{}, rpIDHash[:]...), 0x01, 0x00, 0x00, 0x00, 0x02),
+	}
+	clientDataJSON := `{"type":"webauthn.get","challenge":"abc123","origin":"https://example.com"}`
+	signatureB64 := signAssertion(t, priv, authData, clientDataJSON)
+
+	if !verifyAssertionSignature(publicKeyCOSE, authData, clientDataJSON, signatureB64) {
+		t.Fatal("expected a signature over the real authData/clientDataJSON to verify")
+	}
+
+	t.Run("rejects a signature over truncated authData (rpIDHash only)", func(t *testing.T) {
+		truncated := &authenticatorData{RPIDHash: rpIDHash[:], Raw: rpIDHash[:]}
+		if verifyAssertionSignature(publicKeyCOSE, truncated, clientDataJSON, signatureB64) {
+			t.Fatal("signature over full authData must not also verify against rpIDHash alone")
+		}
+	})
+
+	t.Run("rejects a tampered clientDataJSON", func(t *testing.T) {
+		tampered := `{"type":"webauthn.get","challenge":"abc123","origin":"https://attacker.example"}`
+		if verifyAssertionSignature(publicKeyCOSE, authData, tampered, signatureB64) {
+			t.Fatal("signature must not verify once clientDataJSON is altered")
+		}
+	})
+
+	t.Run("rejects a signature from the wrong key", func(t *testing.T) {
+		other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("generate key: %v", err)
+		}
+		wrongKeyCOSE := encodeCOSEKey(t, &other.PublicKey)
+		if verifyAssertionSignature(wrongKeyCOSE, authData, clientDataJSON, signatureB64) {
+			t.Fatal("signature must not verify against an unrelated public key")
+		}
+	})
+
+	t.Run("rejects a malformed signature", func(t *testing.T) {
+		if verifyAssertionSignature(publicKeyCOSE, authData, clientDataJSON, "not-base64url!!") {
+			t.Fatal("malformed base64url signature must fail to verify, not panic")
+		}
+	})
+}
+
+func TestPasskeyServiceVerifyRPIDHash(t *testing.T) {
+	s := NewPasskeyService(nil, nil, "example.com", "Example", "https://example.com")
+	validHash := sha256.Sum256([]byte("example.com"))
+
+	if err := s.verifyRPIDHash(validHash[:]); err != nil {
+		t.Fatalf("expected SHA-256(rpID) to verify, got: %v", err)
+	}
+
+	wrongHash := sha256.Sum256([]byte("attacker.example"))
+	if err := s.verifyRPIDHash(wrongHash[:]); err != ErrRPIDHashMismatch {
+		t.Fatalf("expected ErrRPIDHashMismatch for a foreign RP ID hash, got: %v", err)
+	}
+}
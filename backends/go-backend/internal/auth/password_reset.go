@@ -0,0 +1,130 @@
+// AngelaMos | 2026
+// password_reset.go
+
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+// PasswordResetConfig mirrors config.PasswordResetConfig; it's a separate
+// type so this package doesn't import internal/config.
+type PasswordResetConfig struct {
+	TokenTTL   time.Duration
+	IPLimit    RateWindow
+	EmailLimit RateWindow
+}
+
+// RateWindow is a plain requests-per-minute limit, mirroring
+// config.RateWindowConfig.
+type RateWindow struct {
+	RequestsPerMinute int
+	Burst             int
+}
+
+const passwordResetTokenBytes = 32
+
+// PasswordResetRepository persists single-use password reset tokens.
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *PasswordResetToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+	MarkUsed(ctx context.Context, id string) error
+	InvalidateForUser(ctx context.Context, userID string) error
+}
+
+type passwordResetRepository struct {
+	db core.DBTX
+}
+
+func NewPasswordResetRepository(db core.DBTX) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) Create(
+	ctx context.Context,
+	token *PasswordResetToken,
+) error {
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at`
+
+	err := r.db.GetContext(ctx, &token.CreatedAt, query,
+		token.ID, token.UserID, token.TokenHash, token.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create password reset token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *passwordResetRepository) FindByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*PasswordResetToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM password_reset_tokens
+		WHERE token_hash = $1`
+
+	var token PasswordResetToken
+	err := r.db.GetContext(ctx, &token, query, tokenHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("find password reset token: %w", core.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find password reset token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r *passwordResetRepository) MarkUsed(ctx context.Context, id string) error {
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("mark password reset token used: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("mark password reset token used: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("mark password reset token used: %w", core.ErrNotFound)
+	}
+
+	return nil
+}
+
+// InvalidateForUser marks every still-usable token for userID as used, so
+// requesting a new reset link retires any earlier one still sitting in an
+// inbox instead of leaving both valid at once.
+func (r *passwordResetRepository) InvalidateForUser(
+	ctx context.Context,
+	userID string,
+) error {
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL AND expires_at > NOW()`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("invalidate password reset tokens: %w", err)
+	}
+
+	return nil
+}
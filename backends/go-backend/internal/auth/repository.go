@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/carterperez-dev/templates/go-backend/internal/core"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/logging"
 )
 
 type Repository interface {
@@ -25,7 +26,8 @@ type Repository interface {
 		ctx context.Context,
 		userID string,
 	) ([]RefreshToken, error)
-	DeleteExpired(ctx context.Context) (int64, error)
+	PurgeExpiredBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	PurgeRevokedBefore(ctx context.Context, cutoff time.Time) (int64, error)
 }
 
 type repository struct {
@@ -40,13 +42,13 @@ func (r *repository) Create(ctx context.Context, token *RefreshToken) error {
 	query := `
 		INSERT INTO refresh_tokens (
 			id, user_id, token_hash, family_id, expires_at,
-			user_agent, ip_address
+			user_agent, ip_address, last_used_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7
+			$1, $2, $3, $4, $5, $6, $7, NOW()
 		)
-		RETURNING created_at`
+		RETURNING created_at, last_used_at`
 
-	err := r.db.GetContext(ctx, &token.CreatedAt, query,
+	err := r.db.GetContext(ctx, token, query,
 		token.ID,
 		token.UserID,
 		token.TokenHash,
@@ -56,6 +58,7 @@ func (r *repository) Create(ctx context.Context, token *RefreshToken) error {
 		token.IPAddress,
 	)
 	if err != nil {
+		logging.FromContext(ctx).Error("create refresh token failed", "error", err)
 		return fmt.Errorf("create refresh token: %w", err)
 	}
 
@@ -69,7 +72,8 @@ func (r *repository) FindByHash(
 	query := `
 		SELECT
 			id, user_id, token_hash, family_id, expires_at, created_at,
-			is_used, used_at, revoked_at, replaced_by_id, user_agent, ip_address
+			is_used, used_at, revoked_at, replaced_by_id, user_agent, ip_address,
+			last_used_at
 		FROM refresh_tokens
 		WHERE token_hash = $1`
 
@@ -79,6 +83,7 @@ func (r *repository) FindByHash(
 		return nil, fmt.Errorf("find refresh token: %w", core.ErrNotFound)
 	}
 	if err != nil {
+		logging.FromContext(ctx).Error("find refresh token failed", "error", err)
 		return nil, fmt.Errorf("find refresh token: %w", err)
 	}
 
@@ -92,7 +97,8 @@ func (r *repository) FindByID(
 	query := `
 		SELECT
 			id, user_id, token_hash, family_id, expires_at, created_at,
-			is_used, used_at, revoked_at, replaced_by_id, user_agent, ip_address
+			is_used, used_at, revoked_at, replaced_by_id, user_agent, ip_address,
+			last_used_at
 		FROM refresh_tokens
 		WHERE id = $1`
 
@@ -102,6 +108,7 @@ func (r *repository) FindByID(
 		return nil, fmt.Errorf("find refresh token: %w", core.ErrNotFound)
 	}
 	if err != nil {
+		logging.FromContext(ctx).Error("find refresh token failed", "error", err)
 		return nil, fmt.Errorf("find refresh token: %w", err)
 	}
 
@@ -114,11 +121,13 @@ func (r *repository) MarkAsUsed(
 ) error {
 	query := `
 		UPDATE refresh_tokens
-		SET is_used = true, used_at = NOW(), replaced_by_id = $2
+		SET is_used = true, used_at = NOW(), replaced_by_id = $2,
+			last_used_at = NOW()
 		WHERE id = $1 AND is_used = false`
 
 	result, err := r.db.ExecContext(ctx, query, id, replacedByID)
 	if err != nil {
+		logging.FromContext(ctx).Error("mark refresh token as used failed", "error", err)
 		return fmt.Errorf("mark refresh token as used: %w", err)
 	}
 
@@ -142,6 +151,7 @@ func (r *repository) RevokeByID(ctx context.Context, id string) error {
 
 	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
+		logging.FromContext(ctx).Error("revoke refresh token failed", "error", err)
 		return fmt.Errorf("revoke refresh token: %w", err)
 	}
 
@@ -168,6 +178,7 @@ func (r *repository) RevokeByFamilyID(
 
 	_, err := r.db.ExecContext(ctx, query, familyID)
 	if err != nil {
+		logging.FromContext(ctx).Error("revoke token family failed", "error", err)
 		return fmt.Errorf("revoke token family: %w", err)
 	}
 
@@ -185,6 +196,7 @@ func (r *repository) RevokeAllForUser(
 
 	_, err := r.db.ExecContext(ctx, query, userID)
 	if err != nil {
+		logging.FromContext(ctx).Error("revoke all user tokens failed", "error", err)
 		return fmt.Errorf("revoke all user tokens: %w", err)
 	}
 
@@ -198,7 +210,8 @@ func (r *repository) GetActiveSessionsForUser(
 	query := `
 		SELECT
 			id, user_id, token_hash, family_id, expires_at, created_at,
-			is_used, used_at, revoked_at, replaced_by_id, user_agent, ip_address
+			is_used, used_at, revoked_at, replaced_by_id, user_agent, ip_address,
+			last_used_at
 		FROM refresh_tokens
 		WHERE user_id = $1
 			AND revoked_at IS NULL
@@ -209,27 +222,52 @@ func (r *repository) GetActiveSessionsForUser(
 	var tokens []RefreshToken
 	err := r.db.SelectContext(ctx, &tokens, query, userID)
 	if err != nil {
+		logging.FromContext(ctx).Error("get active sessions failed", "error", err)
 		return nil, fmt.Errorf("get active sessions: %w", err)
 	}
 
 	return tokens, nil
 }
 
-func (r *repository) DeleteExpired(ctx context.Context) (int64, error) {
+// PurgeExpiredBefore deletes rows whose expires_at is older than cutoff,
+// regardless of revocation status — a long-expired token is safe to drop
+// even if it was never explicitly revoked.
+func (r *repository) PurgeExpiredBefore(ctx context.Context, cutoff time.Time) (int64, error) {
 	query := `
 		DELETE FROM refresh_tokens
 		WHERE expires_at < $1`
 
-	cutoff := time.Now().Add(-24 * time.Hour)
+	result, err := r.db.ExecContext(ctx, query, cutoff)
+	if err != nil {
+		logging.FromContext(ctx).Error("purge expired tokens failed", "error", err)
+		return 0, fmt.Errorf("purge expired tokens: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("purge expired tokens: %w", err)
+	}
+
+	return rows, nil
+}
+
+// PurgeRevokedBefore deletes rows revoked before cutoff — kept around
+// until then for audit purposes (e.g. investigating a reuse-detection
+// revocation) even though they're already unusable.
+func (r *repository) PurgeRevokedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		DELETE FROM refresh_tokens
+		WHERE revoked_at IS NOT NULL AND revoked_at < $1`
 
 	result, err := r.db.ExecContext(ctx, query, cutoff)
 	if err != nil {
-		return 0, fmt.Errorf("delete expired tokens: %w", err)
+		logging.FromContext(ctx).Error("purge revoked tokens failed", "error", err)
+		return 0, fmt.Errorf("purge revoked tokens: %w", err)
 	}
 
 	rows, err := result.RowsAffected()
 	if err != nil {
-		return 0, fmt.Errorf("delete expired tokens: %w", err)
+		return 0, fmt.Errorf("purge revoked tokens: %w", err)
 	}
 
 	return rows, nil
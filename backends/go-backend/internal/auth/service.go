@@ -7,28 +7,42 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
+	redis_rate "github.com/go-redis/redis_rate/v10"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 
 	"github.com/carterperez-dev/templates/go-backend/internal/core"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/mailer"
+	"github.com/carterperez-dev/templates/go-backend/internal/middleware"
 )
 
 var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrTokenReuse         = errors.New("token reuse detected")
-	ErrEmailExists        = errors.New("email already exists")
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrTokenReuse           = errors.New("token reuse detected")
+	ErrEmailExists          = errors.New("email already exists")
+	ErrResetTokenInvalid    = errors.New("password reset token is invalid or expired")
+	ErrResetRateLimited     = errors.New("too many password reset requests")
+	ErrVerifyTokenInvalid   = errors.New("email verification token is invalid or expired")
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+	ErrVerifyRateLimited    = errors.New("too many verification email requests")
 )
 
 type UserInfo struct {
-	ID           string
-	Email        string
-	Name         string
-	PasswordHash string
-	Role         string
-	Tier         string
-	TokenVersion int
+	ID                 string
+	Email              string
+	Name               string
+	PasswordHash       string
+	Role               string
+	Tier               string
+	TokenVersion       int
+	TOTPSecret         string
+	TOTPEnabled        bool
+	RecoveryCodeHashes []string
+	EmailVerified      bool
 }
 
 type UserProvider interface {
@@ -40,14 +54,30 @@ type UserProvider interface {
 	) (*UserInfo, error)
 	IncrementTokenVersion(ctx context.Context, userID string) error
 	UpdatePassword(ctx context.Context, userID, passwordHash string) error
+	SetTOTPSecret(ctx context.Context, userID, secret string, recoveryCodeHashes []string) error
+	EnableTOTP(ctx context.Context, userID string) error
+	DisableTOTP(ctx context.Context, userID string) error
+	UpdateRecoveryCodeHashes(ctx context.Context, userID string, hashes []string) error
+	SetEmailVerified(ctx context.Context, userID string) error
 }
 
 type Service struct {
-	repo         Repository
-	jwt          *JWTManager
-	userProvider UserProvider
-	redis        *redis.Client
-	blacklistTTL time.Duration
+	repo          Repository
+	jwt           *JWTManager
+	userProvider  UserProvider
+	redis         *redis.Client
+	blacklistTTL  time.Duration
+	identityLinks IdentityLinkRepository
+	passwordReset PasswordResetRepository
+	mailer        mailer.Mailer
+	telemetry     *core.Telemetry
+	resetLimiter  *redis_rate.Limiter
+	resetCfg      PasswordResetConfig
+	totpIssuer    string
+	emailVerify   EmailVerificationRepository
+	verifyCfg     EmailVerificationConfig
+	oauthClients  ClientRepository
+	oauthCfg      OAuthProviderConfig
 }
 
 func NewService(
@@ -55,6 +85,7 @@ func NewService(
 	jwt *JWTManager,
 	userProvider UserProvider,
 	redisClient *redis.Client,
+	totpIssuer string,
 ) *Service {
 	return &Service{
 		repo:         repo,
@@ -62,34 +93,147 @@ func NewService(
 		userProvider: userProvider,
 		redis:        redisClient,
 		blacklistTTL: 15 * time.Minute,
+		totpIssuer:   totpIssuer,
 	}
 }
 
+// WithIdentityLinks wires the social-login link store into the service.
+// It's optional — a Service without one simply can't call LoginWithIdentity,
+// mirroring how WithPasskeys works on the handler.
+func (s *Service) WithIdentityLinks(links IdentityLinkRepository) *Service {
+	s.identityLinks = links
+	return s
+}
+
+// WithPasswordReset wires the forgot-password/reset-password flow into the
+// service. It's optional — a Service without one simply can't call
+// ForgotPassword or ResetPassword, mirroring how WithIdentityLinks works.
+func (s *Service) WithPasswordReset(
+	repo PasswordResetRepository,
+	mlr mailer.Mailer,
+	cfg PasswordResetConfig,
+) *Service {
+	s.passwordReset = repo
+	s.mailer = mlr
+	s.resetLimiter = redis_rate.NewLimiter(s.redis)
+	s.resetCfg = cfg
+	return s
+}
+
+// WithEmailVerification wires the send-verification-email/confirm-email
+// flow into the service. It's optional, mirroring WithPasswordReset, and
+// reuses the same mailer — so it must be called after WithPasswordReset
+// has wired one in.
+func (s *Service) WithEmailVerification(
+	repo EmailVerificationRepository,
+	cfg EmailVerificationConfig,
+) *Service {
+	s.emailVerify = repo
+	s.verifyCfg = cfg
+	return s
+}
+
+// WithTelemetry wires an OTel meter into the service so its RED metrics
+// (request rate, errors, duration by endpoint + outcome) and refresh-token
+// reuse counter get recorded. It's optional — a Service without one simply
+// skips recording, mirroring how WithIdentityLinks works.
+func (s *Service) WithTelemetry(telemetry *core.Telemetry) *Service {
+	s.telemetry = telemetry
+	return s
+}
+
+// recordRED records one RED sample for endpoint if telemetry is wired,
+// deriving the outcome label from err: "success", or the error's own
+// message when it's a known sentinel, falling back to "error" otherwise.
+func (s *Service) recordRED(ctx context.Context, endpoint string, start time.Time, err error) {
+	if s.telemetry == nil {
+		return
+	}
+
+	outcome := "success"
+	if err != nil {
+		outcome = redOutcome(err)
+	}
+
+	s.telemetry.RecordAuthRequest(ctx, endpoint, outcome, time.Since(start))
+}
+
+// redOutcome maps a Service error to a bounded-cardinality outcome label
+// for RED metrics, so arbitrary wrapped error text never becomes a label
+// value.
+func redOutcome(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidCredentials):
+		return "invalid_credentials"
+	case errors.Is(err, ErrTokenReuse):
+		return "token_reuse"
+	case errors.Is(err, ErrEmailExists):
+		return "email_exists"
+	case errors.Is(err, core.ErrTokenExpired):
+		return "token_expired"
+	case errors.Is(err, core.ErrTokenRevoked):
+		return "token_revoked"
+	case errors.Is(err, core.ErrTokenInvalid):
+		return "token_invalid"
+	case errors.Is(err, core.ErrForbidden):
+		return "forbidden"
+	case errors.Is(err, ErrTOTPInvalidCode):
+		return "totp_invalid_code"
+	case errors.Is(err, ErrMFAChallengeInvalid):
+		return "mfa_challenge_invalid"
+	default:
+		return "error"
+	}
+}
+
+// Login is a two-step flow when the account has TOTP enabled: called with
+// Email and Password, it returns a LoginResult carrying only
+// MFARequired/ChallengeToken rather than a session; the caller then calls
+// it again with that ChallengeToken and a TOTPCode to actually receive
+// tokens. createAuthResponse is never reached for an MFA-enabled account
+// until that second call succeeds.
 func (s *Service) Login(
 	ctx context.Context,
 	req LoginRequest,
 	userAgent, ipAddress string,
-) (*AuthResponse, error) {
+) (result *LoginResult, err error) {
+	start := time.Now()
+	defer func() { s.recordRED(ctx, "login", start, err) }()
+
+	if req.ChallengeToken != "" {
+		resp, mfaErr := s.completeMFALogin(ctx, req, userAgent, ipAddress)
+		if mfaErr != nil {
+			err = mfaErr
+			return nil, err
+		}
+		result = &LoginResult{AuthResponse: resp}
+		return result, nil
+	}
+
 	user, err := s.userProvider.GetByEmail(ctx, req.Email)
 	if err != nil {
 		if errors.Is(err, core.ErrNotFound) {
 			//nolint:errcheck // timing attack prevention - always verify to prevent enumeration
 			_, _, _ = core.VerifyPasswordTimingSafe(req.Password, nil)
-			return nil, ErrInvalidCredentials
+			err = ErrInvalidCredentials
+			return nil, err
 		}
-		return nil, fmt.Errorf("get user: %w", err)
+		err = fmt.Errorf("get user: %w", err)
+		return nil, err
 	}
 
-	valid, newHash, err := core.VerifyPasswordTimingSafe(
+	valid, newHash, verifyErr := core.VerifyPasswordTimingSafe(
 		req.Password,
 		&user.PasswordHash,
 	)
-	if err != nil {
-		return nil, fmt.Errorf("verify password: %w", err)
+	if verifyErr != nil {
+		err = fmt.Errorf("verify password: %w", verifyErr)
+		return nil, err
 	}
 
 	if !valid {
-		return nil, ErrInvalidCredentials
+		err = ErrInvalidCredentials
+		return nil, err
 	}
 
 	if newHash != "" {
@@ -97,70 +241,211 @@ func (s *Service) Login(
 		_ = s.userProvider.UpdatePassword(ctx, user.ID, newHash)
 	}
 
-	return s.createAuthResponse(ctx, user, userAgent, ipAddress, "", nil)
+	if user.TOTPEnabled {
+		challengeToken, chErr := s.createMFAChallenge(ctx, user.ID)
+		if chErr != nil {
+			err = fmt.Errorf("create mfa challenge: %w", chErr)
+			return nil, err
+		}
+		result = &LoginResult{MFARequired: true, ChallengeToken: challengeToken}
+		return result, nil
+	}
+
+	resp, err := s.createAuthResponse(ctx, user, userAgent, ipAddress, "", nil, "password")
+	if err != nil {
+		return nil, err
+	}
+
+	result = &LoginResult{AuthResponse: resp}
+	return result, nil
+}
+
+// LoginWithPasskey mints tokens for a user who just completed a successful
+// WebAuthn assertion. The caller (the passkey handler) is responsible for
+// verifying the assertion itself; this only issues the session.
+func (s *Service) LoginWithPasskey(
+	ctx context.Context,
+	userID, userAgent, ipAddress string,
+) (*AuthResponse, error) {
+	user, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	return s.createAuthResponse(ctx, user, userAgent, ipAddress, "", nil, "passkey")
+}
+
+// LoginWithDeviceAuthorization mints tokens for the user who approved a
+// device-flow authorization from the browser verification page. The caller
+// (the device token handler) is responsible for confirming the
+// authorization actually resolved to approved; this only issues the
+// session, mirroring LoginWithPasskey.
+func (s *Service) LoginWithDeviceAuthorization(
+	ctx context.Context,
+	userID, userAgent, ipAddress string,
+) (*AuthResponse, error) {
+	user, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	return s.createAuthResponse(ctx, user, userAgent, ipAddress, "", nil, "device")
+}
+
+// LoginWithIdentity resolves an already-verified social-login identity to a
+// local account — linking it to an existing email match on first sign-in,
+// or provisioning a brand-new account — and mints a session for it.
+func (s *Service) LoginWithIdentity(
+	ctx context.Context,
+	identity *ExternalIdentity,
+	userAgent, ipAddress string,
+) (*AuthResponse, error) {
+	link, err := s.identityLinks.FindByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		return nil, fmt.Errorf("lookup identity link: %w", err)
+	}
+
+	var user *UserInfo
+
+	if link != nil {
+		user, err = s.userProvider.GetByID(ctx, link.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("get linked user: %w", err)
+		}
+	} else {
+		user, err = s.userProvider.GetByEmail(ctx, identity.Email)
+		if err != nil {
+			if !errors.Is(err, core.ErrNotFound) {
+				return nil, fmt.Errorf("get user: %w", err)
+			}
+
+			randomPassword, genErr := core.GenerateSecureToken(32)
+			if genErr != nil {
+				return nil, fmt.Errorf("generate account secret: %w", genErr)
+			}
+
+			passwordHash, hashErr := core.HashPassword(randomPassword)
+			if hashErr != nil {
+				return nil, fmt.Errorf("hash account secret: %w", hashErr)
+			}
+
+			user, err = s.userProvider.Create(ctx, identity.Email, passwordHash, identity.Name)
+			if err != nil {
+				return nil, fmt.Errorf("create user: %w", err)
+			}
+		}
+
+		if err := s.identityLinks.Create(ctx, &IdentityLink{
+			ID:       uuid.New().String(),
+			UserID:   user.ID,
+			Provider: identity.Provider,
+			Subject:  identity.Subject,
+			Email:    identity.Email,
+		}); err != nil {
+			return nil, fmt.Errorf("link identity: %w", err)
+		}
+	}
+
+	return s.createAuthResponse(ctx, user, userAgent, ipAddress, "", nil, identity.Provider)
+}
+
+func (s *Service) ListIdentityLinks(ctx context.Context, userID string) ([]IdentityLink, error) {
+	return s.identityLinks.ListForUser(ctx, userID)
+}
+
+func (s *Service) UnlinkIdentity(ctx context.Context, userID, provider string) error {
+	return s.identityLinks.Delete(ctx, userID, provider)
 }
 
 func (s *Service) Register(
 	ctx context.Context,
 	req RegisterRequest,
 	userAgent, ipAddress string,
-) (*AuthResponse, error) {
+) (resp *AuthResponse, err error) {
+	start := time.Now()
+	defer func() { s.recordRED(ctx, "register", start, err) }()
+
 	passwordHash, err := core.HashPassword(req.Password)
 	if err != nil {
-		return nil, fmt.Errorf("hash password: %w", err)
+		err = fmt.Errorf("hash password: %w", err)
+		return nil, err
 	}
 
 	user, err := s.userProvider.Create(ctx, req.Email, passwordHash, req.Name)
 	if err != nil {
 		if errors.Is(err, core.ErrDuplicateKey) {
-			return nil, ErrEmailExists
+			err = ErrEmailExists
+			return nil, err
 		}
-		return nil, fmt.Errorf("create user: %w", err)
+		err = fmt.Errorf("create user: %w", err)
+		return nil, err
 	}
 
-	return s.createAuthResponse(ctx, user, userAgent, ipAddress, "", nil)
+	resp, err = s.createAuthResponse(ctx, user, userAgent, ipAddress, "", nil, "password")
+	return resp, err
 }
 
 func (s *Service) Refresh(
 	ctx context.Context,
 	refreshToken, userAgent, ipAddress string,
-) (*AuthResponse, error) {
+) (resp *AuthResponse, err error) {
+	start := time.Now()
+	defer func() { s.recordRED(ctx, "refresh", start, err) }()
+
 	tokenHash := core.HashToken(refreshToken)
 
 	storedToken, err := s.repo.FindByHash(ctx, tokenHash)
 	if err != nil {
 		if errors.Is(err, core.ErrNotFound) {
-			return nil, fmt.Errorf("refresh: %w", core.ErrTokenInvalid)
+			err = fmt.Errorf("refresh: %w", core.ErrTokenInvalid)
+			return nil, err
 		}
-		return nil, fmt.Errorf("find token: %w", err)
+		err = fmt.Errorf("find token: %w", err)
+		return nil, err
 	}
 
 	if storedToken.IsUsed {
 		//nolint:errcheck // security revocation continues regardless
 		_ = s.repo.RevokeByFamilyID(ctx, storedToken.FamilyID)
-		return nil, ErrTokenReuse
+		if s.telemetry != nil {
+			s.telemetry.IncRefreshReuse(ctx)
+		}
+		err = ErrTokenReuse
+		return nil, err
 	}
 
 	if !storedToken.IsValid() {
 		if storedToken.IsRevoked() {
-			return nil, fmt.Errorf("refresh: %w", core.ErrTokenRevoked)
+			err = fmt.Errorf("refresh: %w", core.ErrTokenRevoked)
+			return nil, err
 		}
-		return nil, fmt.Errorf("refresh: %w", core.ErrTokenExpired)
+		err = fmt.Errorf("refresh: %w", core.ErrTokenExpired)
+		return nil, err
+	}
+
+	if storedToken.IsIdle(s.jwt.config.TokenIdleTimeout) {
+		//nolint:errcheck // security revocation continues regardless
+		_ = s.repo.RevokeByFamilyID(ctx, storedToken.FamilyID)
+		err = fmt.Errorf("refresh: %w", core.ErrTokenExpired)
+		return nil, err
 	}
 
 	user, err := s.userProvider.GetByID(ctx, storedToken.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("get user: %w", err)
+		err = fmt.Errorf("get user: %w", err)
+		return nil, err
 	}
 
-	return s.createAuthResponse(
+	resp, err = s.createAuthResponse(
 		ctx,
 		user,
 		userAgent,
 		ipAddress,
 		storedToken.FamilyID,
 		&storedToken.ID,
+		"password",
 	)
+	return resp, err
 }
 
 func (s *Service) Logout(
@@ -190,14 +475,38 @@ func (s *Service) Logout(
 }
 
 func (s *Service) LogoutAll(ctx context.Context, userID string) error {
+	// Best-effort: listed before revoking so the families are still
+	// distinguishable, but a failure here must not block the revoke-all
+	// below, the security-critical part of this call.
+	sessions, sessionsErr := s.repo.GetActiveSessionsForUser(ctx, userID)
+
 	if err := s.repo.RevokeAllForUser(ctx, userID); err != nil {
 		return fmt.Errorf("revoke all tokens: %w", err)
 	}
 
+	if sessionsErr != nil {
+		slog.Warn("reauth grant cleanup skipped: list active sessions failed",
+			"error", sessionsErr,
+			"user_id", userID,
+		)
+	} else {
+		for _, session := range sessions {
+			//nolint:errcheck // best-effort step-up state cleanup
+			_ = s.redis.Del(ctx, middleware.ReauthGrantKey(session.FamilyID)).Err()
+		}
+	}
+
 	if err := s.userProvider.IncrementTokenVersion(ctx, userID); err != nil {
 		return fmt.Errorf("increment token version: %w", err)
 	}
 
+	if err := s.redis.Del(ctx, tokenVersionCacheKey(userID)).Err(); err != nil {
+		slog.Warn("token version cache invalidation failed",
+			"error", err,
+			"user_id", userID,
+		)
+	}
+
 	return nil
 }
 
@@ -314,23 +623,351 @@ func (s *Service) ChangePassword(
 	return nil
 }
 
+// Reauthenticate verifies password again for an already-authenticated
+// caller and, on success, mints a fresh token pair carrying a short-lived
+// "reauth_at" claim for familyID — the caller's existing session, found on
+// its current access token's "sid" claim. Unlike Login/Register/Refresh,
+// it deliberately continues familyID rather than starting a new one, and
+// it also takes userAgent/ipAddress like every other session-minting
+// method here, since the reissued refresh token still belongs to a
+// browser/session. Since there's no single prior refresh-token id to chain
+// from (unlike Refresh), it revokes every other still-valid token in
+// familyID before minting the replacement, the same way Refresh's rotation
+// leaves exactly one valid token behind, just without a reuse-detection
+// link back to a specific predecessor. The grant itself is recorded in
+// Redis keyed by familyID (see middleware.ReauthGrantKey) so it's picked
+// up by createAuthResponse on every subsequent refresh of this family
+// until JWT.ReauthWindow elapses or the family is logged out.
+func (s *Service) Reauthenticate(
+	ctx context.Context,
+	userID, familyID, password, userAgent, ipAddress string,
+) (*AuthResponse, error) {
+	user, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	valid, newHash, err := core.VerifyPasswordWithRehash(password, user.PasswordHash)
+	if err != nil {
+		return nil, fmt.Errorf("verify password: %w", err)
+	}
+
+	if !valid {
+		return nil, ErrInvalidCredentials
+	}
+
+	if newHash != "" {
+		//nolint:errcheck // best-effort rehash upgrade
+		_ = s.userProvider.UpdatePassword(ctx, userID, newHash)
+	}
+
+	if err := s.grantReauth(ctx, familyID); err != nil {
+		return nil, fmt.Errorf("grant reauth: %w", err)
+	}
+
+	if err := s.repo.RevokeByFamilyID(ctx, familyID); err != nil {
+		return nil, fmt.Errorf("revoke prior session tokens: %w", err)
+	}
+
+	return s.createAuthResponse(ctx, user, userAgent, ipAddress, familyID, nil, "password")
+}
+
+// grantReauth records a fresh step-up reauthentication grant for familyID,
+// TTL'd to JWT.ReauthWindow so it expires on its own even if the family is
+// never refreshed again.
+func (s *Service) grantReauth(ctx context.Context, familyID string) error {
+	key := middleware.ReauthGrantKey(familyID)
+	if err := s.redis.Set(ctx, key, time.Now().Unix(), s.jwt.config.ReauthWindow).Err(); err != nil {
+		return fmt.Errorf("set reauth grant: %w", err)
+	}
+	return nil
+}
+
+// carryReauthGrant returns when familyID's reauth grant was recorded, or
+// the zero time if none is set, it has expired, or Redis can't be reached
+// — the grant's own TTL is what bounds its validity, so a read failure
+// here is treated the same as "no grant" rather than an error.
+func (s *Service) carryReauthGrant(ctx context.Context, familyID string) time.Time {
+	unixSeconds, err := s.redis.Get(ctx, middleware.ReauthGrantKey(familyID)).Int64()
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(unixSeconds, 0)
+}
+
+// ForgotPassword rate-limits by both ip and email (so neither alone can be
+// used to enumerate accounts or spam a mailbox), then — if an account
+// exists for email — mints a single-use reset token and emails it. A
+// missing account is not reported to the caller; the call simply succeeds
+// without sending anything, same as a real request would look from the
+// outside.
+func (s *Service) ForgotPassword(
+	ctx context.Context,
+	email, ip string,
+) error {
+	allowed, err := s.resetLimiter.Allow(ctx, "pwreset:ip:"+ip, resetLimit(s.resetCfg.IPLimit))
+	if err != nil {
+		return fmt.Errorf("check ip rate limit: %w", err)
+	}
+	if allowed.Allowed == 0 {
+		return ErrResetRateLimited
+	}
+
+	allowed, err = s.resetLimiter.Allow(ctx, "pwreset:email:"+email, resetLimit(s.resetCfg.EmailLimit))
+	if err != nil {
+		return fmt.Errorf("check email rate limit: %w", err)
+	}
+	if allowed.Allowed == 0 {
+		return ErrResetRateLimited
+	}
+
+	user, err := s.userProvider.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	rawToken, err := core.GenerateSecureToken(passwordResetTokenBytes)
+	if err != nil {
+		return fmt.Errorf("generate reset token: %w", err)
+	}
+
+	if err := s.passwordReset.InvalidateForUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("invalidate previous reset tokens: %w", err)
+	}
+
+	resetToken := &PasswordResetToken{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		TokenHash: core.HashToken(rawToken),
+		ExpiresAt: time.Now().Add(s.resetCfg.TokenTTL),
+	}
+
+	if err := s.passwordReset.Create(ctx, resetToken); err != nil {
+		return fmt.Errorf("create reset token: %w", err)
+	}
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Use this code to reset your password: %s\n\nIt expires in %s. If you didn't request this, you can ignore this email.",
+			rawToken,
+			s.resetCfg.TokenTTL,
+		),
+	})
+	if err != nil {
+		return fmt.Errorf("send reset email: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword consumes a single-use token minted by ForgotPassword,
+// setting the account's password and revoking every existing session —
+// the same precaution ChangePassword takes, since whoever triggered a
+// reset didn't necessarily have the old password to begin with.
+func (s *Service) ResetPassword(
+	ctx context.Context,
+	token, newPassword string,
+) error {
+	stored, err := s.passwordReset.FindByHash(ctx, core.HashToken(token))
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return ErrResetTokenInvalid
+		}
+		return fmt.Errorf("find reset token: %w", err)
+	}
+
+	if !stored.IsValid() {
+		return ErrResetTokenInvalid
+	}
+
+	newHash, err := core.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	if err := s.userProvider.UpdatePassword(ctx, stored.UserID, newHash); err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+
+	if err := s.passwordReset.MarkUsed(ctx, stored.ID); err != nil {
+		return fmt.Errorf("mark reset token used: %w", err)
+	}
+
+	if err := s.LogoutAll(ctx, stored.UserID); err != nil {
+		return fmt.Errorf("logout all: %w", err)
+	}
+
+	return nil
+}
+
+// SendVerificationEmail mints a single-use verification token for userID
+// and emails it, unless the account is already verified. Unlike
+// ForgotPassword, userID comes from an authenticated caller rather than an
+// email lookup, so there's no account-enumeration concern to guard
+// against — but it's still rate-limited per user, the same precaution
+// ForgotPassword takes, so repeatedly calling this can't be used to flood
+// the account's inbox or run up mailer costs.
+func (s *Service) SendVerificationEmail(ctx context.Context, userID string) error {
+	allowed, err := s.resetLimiter.Allow(ctx, "emailverify:"+userID, resetLimit(s.verifyCfg.RateLimit))
+	if err != nil {
+		return fmt.Errorf("check verification rate limit: %w", err)
+	}
+	if allowed.Allowed == 0 {
+		return ErrVerifyRateLimited
+	}
+
+	user, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	rawToken, err := core.GenerateSecureToken(emailVerificationTokenBytes)
+	if err != nil {
+		return fmt.Errorf("generate verification token: %w", err)
+	}
+
+	if err := s.emailVerify.InvalidateForUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("invalidate previous verification tokens: %w", err)
+	}
+
+	verifyToken := &EmailVerificationToken{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		TokenHash: core.HashToken(rawToken),
+		ExpiresAt: time.Now().Add(s.verifyCfg.TokenTTL),
+	}
+
+	if err := s.emailVerify.Create(ctx, verifyToken); err != nil {
+		return fmt.Errorf("create verification token: %w", err)
+	}
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf(
+			"Use this code to verify your email: %s\n\nIt expires in %s.",
+			rawToken,
+			s.verifyCfg.TokenTTL,
+		),
+	})
+	if err != nil {
+		return fmt.Errorf("send verification email: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmEmail consumes a single-use token minted by SendVerificationEmail,
+// marking the account's email verified. Unlike ResetPassword, this doesn't
+// call LogoutAll — verifying an email address doesn't change how the
+// account authenticates, so existing sessions stay valid.
+func (s *Service) ConfirmEmail(ctx context.Context, token string) error {
+	stored, err := s.emailVerify.FindByHash(ctx, core.HashToken(token))
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return ErrVerifyTokenInvalid
+		}
+		return fmt.Errorf("find verification token: %w", err)
+	}
+
+	if !stored.IsValid() {
+		return ErrVerifyTokenInvalid
+	}
+
+	if err := s.userProvider.SetEmailVerified(ctx, stored.UserID); err != nil {
+		return fmt.Errorf("set email verified: %w", err)
+	}
+
+	if err := s.emailVerify.MarkUsed(ctx, stored.ID); err != nil {
+		return fmt.Errorf("mark verification token used: %w", err)
+	}
+
+	return nil
+}
+
+func resetLimit(w RateWindow) redis_rate.Limit {
+	return redis_rate.Limit{
+		Rate:   w.RequestsPerMinute,
+		Burst:  w.Burst,
+		Period: time.Minute,
+	}
+}
+
+// tokenVersionCacheTTL bounds how stale the cached token version read by
+// ValidateTokenVersion can be: a revoke-tokens action takes effect on
+// every request within this window even without an explicit cache bust,
+// and LogoutAll also busts the cache directly for the common case.
+const tokenVersionCacheTTL = 30 * time.Second
+
+func tokenVersionCacheKey(userID string) string {
+	return "user:" + userID + ":token_version"
+}
+
 func (s *Service) ValidateTokenVersion(
 	ctx context.Context,
 	userID string,
 	tokenVersion int,
 ) error {
-	user, err := s.userProvider.GetByID(ctx, userID)
+	// client_credentials access tokens (see exchangeClientCredentials) name
+	// an OAuthClient, not a row in the users table, so there's no token
+	// version to look up — s.userProvider.GetByID would just error. A
+	// client_credentials token has no refresh/session concept to revoke
+	// mid-flight either; its client is disabled going forward by
+	// ClientRepository.Revoke, checked at mint time, not per-request here.
+	if strings.HasPrefix(userID, oauthClientIDPrefix) {
+		return nil
+	}
+
+	current, err := s.currentTokenVersion(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("get user: %w", err)
 	}
 
-	if tokenVersion < user.TokenVersion {
+	if tokenVersion < current {
 		return fmt.Errorf("validate token version: %w", core.ErrTokenRevoked)
 	}
 
 	return nil
 }
 
+// currentTokenVersion reads the user's token version from Redis, falling
+// back to the database on a cache miss or Redis error (failing open on
+// Redis errors rather than rejecting every request when Redis is down).
+func (s *Service) currentTokenVersion(
+	ctx context.Context,
+	userID string,
+) (int, error) {
+	key := tokenVersionCacheKey(userID)
+
+	cached, err := s.redis.Get(ctx, key).Int()
+	if err == nil {
+		return cached, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		slog.Warn("token version cache read failed", "error", err, "user_id", userID)
+	}
+
+	user, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if setErr := s.redis.Set(ctx, key, user.TokenVersion, tokenVersionCacheTTL).Err(); setErr != nil {
+		slog.Warn("token version cache write failed", "error", setErr, "user_id", userID)
+	}
+
+	return user.TokenVersion, nil
+}
+
 func (s *Service) GetCurrentUser(
 	ctx context.Context,
 	userID string,
@@ -341,35 +978,78 @@ func (s *Service) GetCurrentUser(
 	}
 
 	return &UserResponse{
-		ID:    user.ID,
-		Email: user.Email,
-		Name:  user.Name,
-		Role:  user.Role,
-		Tier:  user.Tier,
+		ID:            user.ID,
+		Email:         user.Email,
+		Name:          user.Name,
+		Role:          user.Role,
+		Tier:          user.Tier,
+		EmailVerified: user.EmailVerified,
 	}, nil
 }
 
+// CreateImpersonationToken mints a short-lived access token for target that
+// carries actorID in its "act" claim, so downstream handlers and audit logs
+// can see who is really behind the request.
+func (s *Service) CreateImpersonationToken(
+	ctx context.Context,
+	actorID string,
+	target *UserInfo,
+) (string, error) {
+	return s.jwt.CreateImpersonationToken(AccessTokenClaims{
+		UserID:       target.ID,
+		Role:         target.Role,
+		Tier:         target.Tier,
+		TokenVersion: target.TokenVersion,
+	}, actorID)
+}
+
 func (s *Service) createAuthResponse(
 	ctx context.Context,
 	user *UserInfo,
 	userAgent, ipAddress, familyID string,
 	oldTokenID *string,
+	authMethod string,
 ) (*AuthResponse, error) {
+	if familyID == "" && !s.jwt.config.EnableMultiLogin {
+		// A fresh Login/Register/etc. (as opposed to a Refresh or
+		// Reauthenticate continuing an existing family) starts a brand new
+		// session; with multi-login disabled that session should be the
+		// user's only one, so every prior family is revoked first. Best
+		// effort: a revoke failure here shouldn't block the user's own
+		// login.
+		if err := s.repo.RevokeAllForUser(ctx, user.ID); err != nil {
+			slog.Warn("revoke prior sessions for single-login mode failed",
+				"error", err,
+				"user_id", user.ID,
+			)
+		}
+	}
+
+	refreshData, err := s.jwt.CreateRefreshToken(user.ID, familyID)
+	if err != nil {
+		return nil, fmt.Errorf("create refresh token: %w", err)
+	}
+
+	var reauthAt time.Time
+	if familyID != "" {
+		// A fresh Login/Register/etc. always starts a brand new family, so
+		// a grant could only ever be found here when continuing one (a
+		// Refresh or a Reauthenticate) — skip the Redis round trip otherwise.
+		reauthAt = s.carryReauthGrant(ctx, refreshData.FamilyID)
+	}
+
 	accessToken, err := s.jwt.CreateAccessToken(AccessTokenClaims{
 		UserID:       user.ID,
 		Role:         user.Role,
 		Tier:         user.Tier,
 		TokenVersion: user.TokenVersion,
+		SessionID:    refreshData.FamilyID,
+		ReauthAt:     reauthAt,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("create access token: %w", err)
 	}
 
-	refreshData, err := s.jwt.CreateRefreshToken(user.ID, familyID)
-	if err != nil {
-		return nil, fmt.Errorf("create refresh token: %w", err)
-	}
-
 	newTokenID := uuid.New().String()
 
 	refreshTokenEntity := &RefreshToken{
@@ -391,14 +1071,21 @@ func (s *Service) createAuthResponse(
 		_ = s.repo.MarkAsUsed(ctx, *oldTokenID, newTokenID)
 	}
 
+	if s.jwt.config.IdleTimeout > 0 {
+		sessionKey := middleware.SessionActivityKey(refreshData.FamilyID)
+		//nolint:errcheck // best-effort session activity seeding
+		_ = s.redis.Set(ctx, sessionKey, time.Now().Unix(), s.jwt.config.IdleTimeout).Err()
+	}
+
 	return &AuthResponse{
 		User: UserResponse{
-			ID:        user.ID,
-			Email:     user.Email,
-			Name:      user.Name,
-			Role:      user.Role,
-			Tier:      user.Tier,
-			CreatedAt: time.Now(),
+			ID:            user.ID,
+			Email:         user.Email,
+			Name:          user.Name,
+			Role:          user.Role,
+			Tier:          user.Tier,
+			CreatedAt:     time.Now(),
+			EmailVerified: user.EmailVerified,
 		},
 		Tokens: TokenResponse{
 			AccessToken:  accessToken,
@@ -406,6 +1093,7 @@ func (s *Service) createAuthResponse(
 			TokenType:    "Bearer",
 			ExpiresIn:    int(15 * time.Minute / time.Second),
 			ExpiresAt:    time.Now().Add(15 * time.Minute),
+			AuthMethod:   authMethod,
 		},
 	}, nil
 }
@@ -5,6 +5,7 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"sync"
 	"time"
 
@@ -15,15 +16,29 @@ import (
 )
 
 type Config struct {
-	App       AppConfig       `koanf:"app"`
-	Server    ServerConfig    `koanf:"server"`
-	Database  DatabaseConfig  `koanf:"database"`
-	Redis     RedisConfig     `koanf:"redis"`
-	JWT       JWTConfig       `koanf:"jwt"`
-	RateLimit RateLimitConfig `koanf:"rate_limit"`
-	CORS      CORSConfig      `koanf:"cors"`
-	Log       LogConfig       `koanf:"log"`
-	Otel      OtelConfig      `koanf:"otel"`
+	App               AppConfig               `koanf:"app"`
+	Server            ServerConfig            `koanf:"server"`
+	GRPC              GRPCConfig              `koanf:"grpc"`
+	Database          DatabaseConfig          `koanf:"database"`
+	Redis             RedisConfig             `koanf:"redis"`
+	JWT               JWTConfig               `koanf:"jwt"`
+	RateLimit         RateLimitConfig         `koanf:"rate_limit"`
+	AuthRateLimit     AuthRateLimitConfig     `koanf:"auth_rate_limit"`
+	PasswordReset     PasswordResetConfig     `koanf:"password_reset"`
+	EmailVerification EmailVerificationConfig `koanf:"email_verification"`
+	Mailer            MailerConfig            `koanf:"mailer"`
+	DeviceAuth        DeviceAuthConfig        `koanf:"device_auth"`
+	APIToken          APITokenConfig          `koanf:"api_token"`
+	Janitor           JanitorConfig           `koanf:"janitor"`
+	SoftDelete        SoftDeleteConfig        `koanf:"soft_delete"`
+	ClientIP          ClientIPConfig          `koanf:"client_ip"`
+	CORS              CORSConfig              `koanf:"cors"`
+	Log               LogConfig               `koanf:"log"`
+	Otel              OtelConfig              `koanf:"otel"`
+	OAuth             OAuthConfig             `koanf:"oauth"`
+	OAuthProvider     OAuthProviderConfig     `koanf:"oauth_provider"`
+	Metrics           MetricsConfig           `koanf:"metrics"`
+	Policies          map[string]string       `koanf:"policies"`
 }
 
 type AppConfig struct {
@@ -41,6 +56,13 @@ type ServerConfig struct {
 	ShutdownTimeout time.Duration `koanf:"shutdown_timeout"`
 }
 
+// GRPCConfig configures the cmd/grpcserver binary, the gRPC mirror of
+// ServerConfig for the REST API.
+type GRPCConfig struct {
+	Host string `koanf:"host"`
+	Port int    `koanf:"port"`
+}
+
 type DatabaseConfig struct {
 	URL             string        `koanf:"url"`
 	MaxOpenConns    int           `koanf:"max_open_conns"`
@@ -62,12 +84,138 @@ type JWTConfig struct {
 	RefreshTokenExpire time.Duration `koanf:"refresh_token_expire"`
 	Issuer             string        `koanf:"issuer"`
 	Audience           string        `koanf:"audience"`
+	KeySetPath         string        `koanf:"keyset_path"`
+	RotationInterval   time.Duration `koanf:"rotation_interval"`
+	MaxKeyAge          time.Duration `koanf:"max_key_age"`
+	IdleTimeout        time.Duration `koanf:"idle_timeout"`
+	ReauthWindow       time.Duration `koanf:"reauth_window"`
+	TokenIdleTimeout   time.Duration `koanf:"token_idle_timeout"`
+	EnableMultiLogin   bool          `koanf:"enable_multi_login"`
 }
 
 type RateLimitConfig struct {
-	Requests int           `koanf:"requests"`
-	Window   time.Duration `koanf:"window"`
-	Burst    int           `koanf:"burst"`
+	Requests int                         `koanf:"requests"`
+	Window   time.Duration               `koanf:"window"`
+	Burst    int                         `koanf:"burst"`
+	Tiers    map[string]TierPolicyConfig `koanf:"tiers"`
+}
+
+// TierPolicyConfig declares one subscription tier's request budget for
+// core/quota.Limiter, e.g. "100 req/min for Free, 1000 for Pro, unlimited
+// for Enterprise". Unlimited tiers ignore RequestsPerMinute/Burst.
+type TierPolicyConfig struct {
+	RequestsPerMinute int  `koanf:"requests_per_minute"`
+	Burst             int  `koanf:"burst"`
+	Unlimited         bool `koanf:"unlimited"`
+}
+
+// AuthRateLimitConfig drives core/authlock.Store's progressive lockout on
+// top of the login and refresh endpoints: once Attempts failures land
+// within Window, a caller is locked out for BaseLockout, doubling per
+// attempt over the threshold and capped at MaxLockout.
+type AuthRateLimitConfig struct {
+	Attempts    int           `koanf:"attempts"`
+	Window      time.Duration `koanf:"window"`
+	BaseLockout time.Duration `koanf:"base_lockout"`
+	MaxLockout  time.Duration `koanf:"max_lockout"`
+	// TrackBy is one of "email", "ip", or "email+ip".
+	TrackBy string `koanf:"track_by"`
+}
+
+// RateWindowConfig is a plain requests-per-minute limit, reused by
+// subsystems that need a simple rate limit without RateLimitConfig's
+// per-tier Tiers map.
+type RateWindowConfig struct {
+	RequestsPerMinute int `koanf:"requests_per_minute"`
+	Burst             int `koanf:"burst"`
+}
+
+// PasswordResetConfig drives the forgot-password/reset-password endpoints:
+// TokenTTL bounds how long a reset token stays valid, and IPLimit/EmailLimit
+// throttle how often /forgot-password can be called for a given caller or
+// target email so it can't be used to enumerate accounts or spam a mailbox.
+type PasswordResetConfig struct {
+	TokenTTL   time.Duration    `koanf:"token_ttl"`
+	IPLimit    RateWindowConfig `koanf:"ip_limit"`
+	EmailLimit RateWindowConfig `koanf:"email_limit"`
+}
+
+// EmailVerificationConfig drives the verify-email endpoints: TokenTTL
+// bounds how long a verification token stays valid, and RateLimit throttles
+// how often /email/verify/send can be called for a given account so it
+// can't be used to flood the account's inbox.
+type EmailVerificationConfig struct {
+	TokenTTL  time.Duration    `koanf:"token_ttl"`
+	RateLimit RateWindowConfig `koanf:"rate_limit"`
+}
+
+// MailerConfig selects core/mailer's backend. Driver is one of "smtp",
+// "log" (print instead of sending, for local development), or "noop"
+// (discard every send, e.g. for tests).
+type MailerConfig struct {
+	Driver      string     `koanf:"driver"`
+	FromAddress string     `koanf:"from_address"`
+	SMTP        SMTPConfig `koanf:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string `koanf:"host"`
+	Port     int    `koanf:"port"`
+	Username string `koanf:"username"`
+	Password string `koanf:"password"`
+}
+
+// DeviceAuthConfig drives auth.DeviceService's RFC 8628 device authorization
+// grant: CodeTTL bounds how long a device/user code pair stays claimable,
+// and VerificationURI is the human-facing page the user types their code
+// into, returned verbatim in the device/code response.
+type DeviceAuthConfig struct {
+	CodeTTL         time.Duration `koanf:"code_ttl"`
+	VerificationURI string        `koanf:"verification_uri"`
+}
+
+// APITokenConfig drives auth.APITokenService's personal access tokens:
+// LastUsedFlushInterval bounds how often a batch of seen token IDs is
+// written back as last_used_at, trading write volume for staleness the
+// same way the refresh-token idle timeout trades precision for Redis
+// round trips.
+type APITokenConfig struct {
+	LastUsedFlushInterval time.Duration `koanf:"last_used_flush_interval"`
+}
+
+// JanitorConfig drives auth.Janitor's periodic cleanup of the
+// refresh_tokens table: rows whose expires_at or revoked_at is older than
+// Retention are purged every Interval. A non-positive Interval disables
+// the janitor entirely.
+type JanitorConfig struct {
+	Interval  time.Duration `koanf:"interval"`
+	Retention time.Duration `koanf:"retention"`
+}
+
+// SoftDeleteConfig drives user.Service's grace-period soft delete and
+// user.PurgeWorker's scheduled hard-deletion of what it leaves behind.
+// GracePeriod is how long a soft-deleted account stays restorable via
+// RestoreSoftDeleted; GracePeriods overrides it per tier, the same shape
+// RateLimitConfig.Tiers uses for its own per-tier overrides. A
+// non-positive PurgeInterval disables the worker entirely, the same
+// convention JanitorConfig.Interval uses.
+type SoftDeleteConfig struct {
+	GracePeriod   time.Duration            `koanf:"grace_period"`
+	GracePeriods  map[string]time.Duration `koanf:"grace_periods"`
+	PurgeInterval time.Duration            `koanf:"purge_interval"`
+	BatchSize     int                      `koanf:"batch_size"`
+}
+
+// ClientIPConfig drives core/clientip.Resolver: forwarded-for headers are
+// only trusted when the immediate peer's address falls within
+// TrustedProxies, so an ALB/NGINX/Cloudflare stack can be trusted to
+// report the real attacker IP without letting an arbitrary client spoof
+// it directly.
+type ClientIPConfig struct {
+	TrustedProxies []string `koanf:"trusted_proxies"`
+	// Headers is the preference order in which forwarding headers are
+	// consulted. Defaults to Forwarded, X-Forwarded-For, X-Real-IP.
+	Headers []string `koanf:"headers"`
 }
 
 type CORSConfig struct {
@@ -89,6 +237,64 @@ type OtelConfig struct {
 	Enabled     bool    `koanf:"enabled"`
 	Insecure    bool    `koanf:"insecure"`
 	SampleRate  float64 `koanf:"sample_rate"`
+	// Protocol selects the OTLP exporter transport: "grpc" or "http".
+	Protocol string `koanf:"protocol"`
+	// LogsEnabled gates the OTLP log exporter independently of Enabled,
+	// since most operators want traces/metrics long before they're ready
+	// to ship application logs to a collector too.
+	LogsEnabled bool `koanf:"logs_enabled"`
+}
+
+// MetricsConfig controls the Prometheus /metrics endpoint exposed by
+// admin.Handler. It is a no-op when Enabled is false, so deployments that
+// don't scrape metrics pay no cost for the subsystem existing.
+type MetricsConfig struct {
+	Enabled     bool     `koanf:"enabled"`
+	BearerToken string   `koanf:"bearer_token"`
+	AllowedIPs  []string `koanf:"allowed_ips"`
+}
+
+// OAuthConfig holds the social-login connectors enabled for this
+// deployment. Each provider is optional — a connector with no ClientID
+// configured is simply never registered at startup.
+type OAuthConfig struct {
+	BaseRedirectURL string               `koanf:"base_redirect_url"`
+	Google          OAuthProviderSection `koanf:"google"`
+	GitHub          OAuthProviderSection `koanf:"github"`
+	Connectors      []ConnectorConfig    `koanf:"connectors"`
+}
+
+// ConnectorConfig declares an additional OIDC identity provider beyond the
+// built-in Google/GitHub sections, resolved via discovery rather than a
+// hardcoded endpoint set — Okta, Auth0, a corporate IdP, anything that
+// publishes a standard `/.well-known/openid-configuration` document.
+type ConnectorConfig struct {
+	ID           string   `koanf:"id"`
+	Type         string   `koanf:"type"`
+	ClientID     string   `koanf:"client_id"`
+	ClientSecret string   `koanf:"client_secret"`
+	RedirectURL  string   `koanf:"redirect_url"`
+	Scopes       []string `koanf:"scopes"`
+	DiscoveryURL string   `koanf:"discovery_url"`
+}
+
+type OAuthProviderSection struct {
+	ClientID     string `koanf:"client_id"`
+	ClientSecret string `koanf:"client_secret"`
+}
+
+// OAuthProviderConfig drives auth.Service's OIDC authorization-server mode
+// (this deployment acting as an OAuth provider for its own registered
+// clients), as distinct from OAuthConfig above (this deployment acting as
+// an OAuth *client* of Google/GitHub/etc. for social login). BaseURL is
+// the externally-reachable origin used to build absolute endpoint URLs in
+// the discovery document. AuthCodeTTL bounds how long an issued
+// authorization code stays redeemable; IDTokenExpire bounds the lifetime
+// of a minted OIDC ID token.
+type OAuthProviderConfig struct {
+	BaseURL       string        `koanf:"base_url"`
+	AuthCodeTTL   time.Duration `koanf:"auth_code_ttl"`
+	IDTokenExpire time.Duration `koanf:"id_token_expire"`
 }
 
 var (
@@ -145,6 +351,26 @@ func Get() *Config {
 	return cfg
 }
 
+// LoadPolicies re-reads just the `policies` section of configPath. Unlike
+// Load, it isn't gated by sync.Once, so it can be called again on SIGHUP
+// to pick up edited policy expressions without restarting the process.
+func LoadPolicies(configPath string) (map[string]string, error) {
+	k := koanf.New(".")
+
+	if configPath != "" {
+		if err := k.Load(file.Provider(configPath), yaml.Parser()); err != nil {
+			return nil, fmt.Errorf("load config file: %w", err)
+		}
+	}
+
+	var policies map[string]string
+	if err := k.Unmarshal("policies", &policies); err != nil {
+		return nil, fmt.Errorf("unmarshal policies: %w", err)
+	}
+
+	return policies, nil
+}
+
 func loadDefaults(k *koanf.Koanf) error {
 	defaults := map[string]any{
 		"app.name":        "Go Backend",
@@ -158,6 +384,9 @@ func loadDefaults(k *koanf.Koanf) error {
 		"server.idle_timeout":     "120s",
 		"server.shutdown_timeout": "15s",
 
+		"grpc.host": "0.0.0.0",
+		"grpc.port": 9090,
+
 		"database.max_open_conns":     25,
 		"database.max_idle_conns":     5,
 		"database.conn_max_lifetime":  "1h",
@@ -172,11 +401,63 @@ func loadDefaults(k *koanf.Koanf) error {
 		"jwt.audience":             "go-backend-api",
 		"jwt.private_key_path":     "keys/private.pem",
 		"jwt.public_key_path":      "keys/public.pem",
+		"jwt.keyset_path":          "keys/keyset.json",
+		"jwt.rotation_interval":    "720h",
+		"jwt.max_key_age":          "2160h",
+		"jwt.idle_timeout":         "30m",
+		"jwt.reauth_window":        "5m",
+		"jwt.token_idle_timeout":   "168h",
+		"jwt.enable_multi_login":   true,
 
 		"rate_limit.requests": 100,
 		"rate_limit.window":   "1m",
 		"rate_limit.burst":    20,
 
+		"rate_limit.tiers.free.requests_per_minute": 100,
+		"rate_limit.tiers.free.burst":               20,
+		"rate_limit.tiers.pro.requests_per_minute":  1000,
+		"rate_limit.tiers.pro.burst":                200,
+		"rate_limit.tiers.enterprise.unlimited":     true,
+
+		"auth_rate_limit.attempts":     5,
+		"auth_rate_limit.window":       "15m",
+		"auth_rate_limit.base_lockout": "1m",
+		"auth_rate_limit.max_lockout":  "1h",
+		"auth_rate_limit.track_by":     "email+ip",
+
+		"password_reset.token_ttl":                       "1h",
+		"password_reset.ip_limit.requests_per_minute":    5,
+		"password_reset.ip_limit.burst":                  5,
+		"password_reset.email_limit.requests_per_minute": 3,
+		"password_reset.email_limit.burst":               3,
+
+		"email_verification.token_ttl":                      "24h",
+		"email_verification.rate_limit.requests_per_minute": 3,
+		"email_verification.rate_limit.burst":               3,
+
+		"mailer.driver":       "log",
+		"mailer.from_address": "no-reply@go-backend.local",
+
+		"device_auth.code_ttl":         "10m",
+		"device_auth.verification_uri": "http://localhost:3000/device",
+
+		"api_token.last_used_flush_interval": "1m",
+
+		"janitor.interval":  "1h",
+		"janitor.retention": "720h",
+
+		"soft_delete.grace_period":             "168h",
+		"soft_delete.grace_periods.enterprise": "720h",
+		"soft_delete.purge_interval":           "1h",
+		"soft_delete.batch_size":               500,
+
+		"client_ip.trusted_proxies": []string{},
+		"client_ip.headers": []string{
+			"Forwarded",
+			"X-Forwarded-For",
+			"X-Real-IP",
+		},
+
 		"cors.allowed_origins": []string{"http://localhost:3000"},
 		"cors.allowed_methods": []string{
 			"GET",
@@ -202,6 +483,16 @@ func loadDefaults(k *koanf.Koanf) error {
 		"otel.insecure":     true,
 		"otel.sample_rate":  0.1,
 		"otel.service_name": "go-backend",
+		"otel.protocol":     "grpc",
+		"otel.logs_enabled": false,
+
+		"oauth.base_redirect_url": "http://localhost:8080",
+
+		"oauth_provider.base_url":        "http://localhost:8080",
+		"oauth_provider.auth_code_ttl":   "60s",
+		"oauth_provider.id_token_expire": "15m",
+
+		"metrics.enabled": false,
 	}
 
 	for key, value := range defaults {
@@ -214,28 +505,70 @@ func loadDefaults(k *koanf.Koanf) error {
 }
 
 var envKeyMap = map[string]string{
-	"DATABASE_URL":                "database.url",
-	"REDIS_URL":                   "redis.url",
-	"ENVIRONMENT":                 "app.environment",
-	"HOST":                        "server.host",
-	"PORT":                        "server.port",
-	"LOG_LEVEL":                   "log.level",
-	"LOG_FORMAT":                  "log.format",
-	"JWT_PRIVATE_KEY_PATH":        "jwt.private_key_path",
-	"JWT_PUBLIC_KEY_PATH":         "jwt.public_key_path",
-	"JWT_ACCESS_TOKEN_EXPIRE":     "jwt.access_token_expire",
-	"JWT_REFRESH_TOKEN_EXPIRE":    "jwt.refresh_token_expire",
-	"JWT_ISSUER":                  "jwt.issuer",
-	"JWT_AUDIENCE":                "jwt.audience",
-	"RATE_LIMIT_REQUESTS":         "rate_limit.requests",
-	"RATE_LIMIT_WINDOW":           "rate_limit.window",
-	"RATE_LIMIT_BURST":            "rate_limit.burst",
-	"OTEL_ENDPOINT":               "otel.endpoint",
-	"OTEL_EXPORTER_OTLP_ENDPOINT": "otel.endpoint",
-	"OTEL_SERVICE_NAME":           "otel.service_name",
-	"OTEL_ENABLED":                "otel.enabled",
-	"OTEL_INSECURE":               "otel.insecure",
-	"OTEL_SAMPLE_RATE":            "otel.sample_rate",
+	"DATABASE_URL":                       "database.url",
+	"REDIS_URL":                          "redis.url",
+	"ENVIRONMENT":                        "app.environment",
+	"HOST":                               "server.host",
+	"PORT":                               "server.port",
+	"GRPC_HOST":                          "grpc.host",
+	"GRPC_PORT":                          "grpc.port",
+	"LOG_LEVEL":                          "log.level",
+	"LOG_FORMAT":                         "log.format",
+	"JWT_PRIVATE_KEY_PATH":               "jwt.private_key_path",
+	"JWT_PUBLIC_KEY_PATH":                "jwt.public_key_path",
+	"JWT_ACCESS_TOKEN_EXPIRE":            "jwt.access_token_expire",
+	"JWT_REFRESH_TOKEN_EXPIRE":           "jwt.refresh_token_expire",
+	"JWT_ISSUER":                         "jwt.issuer",
+	"JWT_AUDIENCE":                       "jwt.audience",
+	"JWT_KEYSET_PATH":                    "jwt.keyset_path",
+	"JWT_ROTATION_INTERVAL":              "jwt.rotation_interval",
+	"JWT_MAX_KEY_AGE":                    "jwt.max_key_age",
+	"JWT_IDLE_TIMEOUT":                   "jwt.idle_timeout",
+	"JWT_REAUTH_WINDOW":                  "jwt.reauth_window",
+	"JWT_TOKEN_IDLE_TIMEOUT":             "jwt.token_idle_timeout",
+	"JWT_ENABLE_MULTI_LOGIN":             "jwt.enable_multi_login",
+	"RATE_LIMIT_REQUESTS":                "rate_limit.requests",
+	"RATE_LIMIT_WINDOW":                  "rate_limit.window",
+	"RATE_LIMIT_BURST":                   "rate_limit.burst",
+	"AUTH_RATE_LIMIT_ATTEMPTS":           "auth_rate_limit.attempts",
+	"AUTH_RATE_LIMIT_WINDOW":             "auth_rate_limit.window",
+	"AUTH_RATE_LIMIT_BASE_LOCKOUT":       "auth_rate_limit.base_lockout",
+	"AUTH_RATE_LIMIT_MAX_LOCKOUT":        "auth_rate_limit.max_lockout",
+	"AUTH_RATE_LIMIT_TRACK_BY":           "auth_rate_limit.track_by",
+	"OTEL_ENDPOINT":                      "otel.endpoint",
+	"OTEL_EXPORTER_OTLP_ENDPOINT":        "otel.endpoint",
+	"OTEL_SERVICE_NAME":                  "otel.service_name",
+	"OTEL_ENABLED":                       "otel.enabled",
+	"OTEL_INSECURE":                      "otel.insecure",
+	"OTEL_SAMPLE_RATE":                   "otel.sample_rate",
+	"OAUTH_BASE_REDIRECT_URL":            "oauth.base_redirect_url",
+	"OAUTH_GOOGLE_CLIENT_ID":             "oauth.google.client_id",
+	"OAUTH_GOOGLE_CLIENT_SECRET":         "oauth.google.client_secret",
+	"OAUTH_GITHUB_CLIENT_ID":             "oauth.github.client_id",
+	"OAUTH_GITHUB_CLIENT_SECRET":         "oauth.github.client_secret",
+	"OAUTH_PROVIDER_BASE_URL":            "oauth_provider.base_url",
+	"OAUTH_PROVIDER_AUTH_CODE_TTL":       "oauth_provider.auth_code_ttl",
+	"OAUTH_PROVIDER_ID_TOKEN_EXPIRE":     "oauth_provider.id_token_expire",
+	"PASSWORD_RESET_TOKEN_TTL":           "password_reset.token_ttl",
+	"EMAIL_VERIFICATION_TOKEN_TTL":       "email_verification.token_ttl",
+	"MAILER_DRIVER":                      "mailer.driver",
+	"MAILER_FROM_ADDRESS":                "mailer.from_address",
+	"MAILER_SMTP_HOST":                   "mailer.smtp.host",
+	"MAILER_SMTP_PORT":                   "mailer.smtp.port",
+	"MAILER_SMTP_USERNAME":               "mailer.smtp.username",
+	"MAILER_SMTP_PASSWORD":               "mailer.smtp.password",
+	"DEVICE_AUTH_CODE_TTL":               "device_auth.code_ttl",
+	"DEVICE_AUTH_VERIFICATION_URI":       "device_auth.verification_uri",
+	"API_TOKEN_LAST_USED_FLUSH_INTERVAL": "api_token.last_used_flush_interval",
+	"JANITOR_INTERVAL":                   "janitor.interval",
+	"JANITOR_RETENTION":                  "janitor.retention",
+	"SOFT_DELETE_GRACE_PERIOD":           "soft_delete.grace_period",
+	"SOFT_DELETE_PURGE_INTERVAL":         "soft_delete.purge_interval",
+	"SOFT_DELETE_BATCH_SIZE":             "soft_delete.batch_size",
+	"OTEL_PROTOCOL":                      "otel.protocol",
+	"OTEL_LOGS_ENABLED":                  "otel.logs_enabled",
+	"METRICS_ENABLED":                    "metrics.enabled",
+	"METRICS_BEARER_TOKEN":               "metrics.bearer_token",
 }
 
 func envKeyReplacer(s string) string {
@@ -286,6 +619,80 @@ func validate(c *Config) error {
 		return fmt.Errorf("server.write_timeout must be positive")
 	}
 
+	seenConnectorIDs := make(map[string]bool, len(c.OAuth.Connectors))
+	for _, connector := range c.OAuth.Connectors {
+		if connector.ID == "" {
+			return fmt.Errorf("oauth.connectors: id is required")
+		}
+		if seenConnectorIDs[connector.ID] {
+			return fmt.Errorf("oauth.connectors: duplicate id %q", connector.ID)
+		}
+		seenConnectorIDs[connector.ID] = true
+
+		if connector.Type != "oidc" {
+			return fmt.Errorf(
+				"oauth.connectors[%s]: unsupported type %q (only \"oidc\" is supported)",
+				connector.ID, connector.Type,
+			)
+		}
+		if connector.ClientID == "" || connector.ClientSecret == "" {
+			return fmt.Errorf("oauth.connectors[%s]: client_id and client_secret are required", connector.ID)
+		}
+		if connector.DiscoveryURL == "" {
+			return fmt.Errorf("oauth.connectors[%s]: discovery_url is required", connector.ID)
+		}
+	}
+
+	if c.AuthRateLimit.Attempts <= 0 {
+		return fmt.Errorf("auth_rate_limit.attempts must be positive")
+	}
+
+	if c.AuthRateLimit.Window <= 0 {
+		return fmt.Errorf("auth_rate_limit.window must be positive")
+	}
+
+	if c.AuthRateLimit.BaseLockout <= 0 {
+		return fmt.Errorf("auth_rate_limit.base_lockout must be positive")
+	}
+
+	if c.AuthRateLimit.MaxLockout < c.AuthRateLimit.BaseLockout {
+		return fmt.Errorf("auth_rate_limit.max_lockout must be >= auth_rate_limit.base_lockout")
+	}
+
+	switch c.AuthRateLimit.TrackBy {
+	case "email", "ip", "email+ip":
+	default:
+		return fmt.Errorf("auth_rate_limit.track_by must be one of \"email\", \"ip\", \"email+ip\"")
+	}
+
+	if c.PasswordReset.TokenTTL <= 0 {
+		return fmt.Errorf("password_reset.token_ttl must be positive")
+	}
+
+	if c.JWT.ReauthWindow <= 0 {
+		return fmt.Errorf("jwt.reauth_window must be positive")
+	}
+
+	if c.EmailVerification.TokenTTL <= 0 {
+		return fmt.Errorf("email_verification.token_ttl must be positive")
+	}
+
+	switch c.Mailer.Driver {
+	case "smtp", "log", "noop":
+	default:
+		return fmt.Errorf("mailer.driver must be one of \"smtp\", \"log\", \"noop\"")
+	}
+
+	if c.DeviceAuth.CodeTTL <= 0 {
+		return fmt.Errorf("device_auth.code_ttl must be positive")
+	}
+
+	for _, cidr := range c.ClientIP.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("client_ip.trusted_proxies: invalid CIDR %q: %w", cidr, err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,65 @@
+// AngelaMos | 2026
+// logger.go
+
+// Package audit records administrative actions (role/tier changes, token
+// revocation, impersonation, deletion) to the audit_log table so they can
+// be reviewed after the fact. Before/after are stored as JSON snapshots of
+// whatever the caller passes — most callers pass the affected entity's
+// response DTO, not the raw domain model.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+type Logger struct {
+	db core.DBTX
+}
+
+func NewLogger(db core.DBTX) *Logger {
+	return &Logger{db: db}
+}
+
+// Log records one admin action. before/after may be nil (e.g. a create or
+// a delete only has one side); both are marshaled to JSON independently so
+// a failure to marshal one doesn't lose the other.
+func (l *Logger) Log(
+	ctx context.Context,
+	actorID, targetID, action string,
+	before, after any,
+) error {
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return fmt.Errorf("marshal audit before-state: %w", err)
+	}
+
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return fmt.Errorf("marshal audit after-state: %w", err)
+	}
+
+	query := `
+		INSERT INTO audit_log
+			(id, actor_id, target_id, action, before, after, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())`
+
+	_, err = l.db.ExecContext(ctx, query,
+		uuid.New().String(),
+		actorID,
+		targetID,
+		action,
+		beforeJSON,
+		afterJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("record audit log: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+// AngelaMos | 2026
+// store.go
+
+// Package authlock implements per-identifier progressive lockout for the
+// login and refresh endpoints: a sliding window of recent failures is
+// kept in a Redis sorted set (ZADD on failure, ZREMRANGEBYSCORE to age
+// entries out, ZCARD to count), so the count is correct across replicas
+// without any in-process state. Once the window's failure count exceeds
+// Config.Attempts, a separate lock key is set with a TTL that doubles
+// per attempt over the threshold, capped at Config.MaxLockout.
+package authlock
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+// TrackBy selects which parts of a login attempt compose the lockout key.
+type TrackBy string
+
+const (
+	TrackByEmail   TrackBy = "email"
+	TrackByIP      TrackBy = "ip"
+	TrackByEmailIP TrackBy = "email+ip"
+)
+
+const keyPrefix = "authlock:"
+
+// Config mirrors config.AuthRateLimitConfig; it's a separate type so this
+// package doesn't import internal/config.
+type Config struct {
+	Attempts    int
+	Window      time.Duration
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+	TrackBy     TrackBy
+}
+
+// Store is the Redis-backed progressive lockout tracker. One Store per
+// process is shared by every call to middleware.LoginThrottle.
+type Store struct {
+	rdb *redis.Client
+	cfg Config
+}
+
+func NewStore(rdb *redis.Client, cfg Config) *Store {
+	return &Store{rdb: rdb, cfg: cfg}
+}
+
+// Key builds the identifier for one login attempt according to
+// Config.TrackBy. Either identifier may be empty (e.g. a refresh request
+// has no email), in which case the remaining one is used alone. The
+// result is the suffix passed to Locked, RecordFailure and Clear — it
+// does not itself carry keyPrefix. email is hashed with core.HashToken
+// before it's folded into the key so a Redis key dump never exposes a
+// plaintext address.
+func (s *Store) Key(email, ip string) string {
+	switch s.cfg.TrackBy {
+	case TrackByIP:
+		return "ip:" + ip
+	case TrackByEmailIP:
+		if email == "" {
+			return "ip:" + ip
+		}
+		return "email+ip:" + core.HashToken(email) + ":" + ip
+	default: // TrackByEmail
+		if email == "" {
+			return "ip:" + ip
+		}
+		return "email:" + core.HashToken(email)
+	}
+}
+
+// Locked reports whether key is currently under a progressive lockout,
+// and if so, how long until it's eligible to try again.
+func (s *Store) Locked(ctx context.Context, key string) (bool, time.Duration, error) {
+	ttl, err := s.rdb.TTL(ctx, lockKey(key)).Result()
+	if err != nil {
+		return false, 0, fmt.Errorf("check lockout: %w", err)
+	}
+
+	if ttl <= 0 {
+		return false, 0, nil
+	}
+
+	return true, ttl, nil
+}
+
+// RecordFailure ages out failures older than Config.Window, records this
+// one, and — once the window's count exceeds Config.Attempts — sets a
+// lock key whose TTL doubles per attempt over the threshold, capped at
+// Config.MaxLockout.
+func (s *Store) RecordFailure(ctx context.Context, key string) error {
+	failuresKey := keyPrefix + "failures:" + key
+	now := time.Now()
+	cutoff := now.Add(-s.cfg.Window)
+
+	pipe := s.rdb.Pipeline()
+	pipe.ZRemRangeByScore(ctx, failuresKey, "-inf", fmt.Sprintf("%d", cutoff.UnixNano()))
+	pipe.ZAdd(ctx, failuresKey, redis.Z{Score: float64(now.UnixNano()), Member: uuid.New().String()})
+	pipe.Expire(ctx, failuresKey, s.cfg.Window)
+	card := pipe.ZCard(ctx, failuresKey)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("record auth failure: %w", err)
+	}
+
+	attempts := int(card.Val())
+	if attempts <= s.cfg.Attempts {
+		return nil
+	}
+
+	lockout := s.lockoutFor(attempts)
+	if err := s.rdb.Set(ctx, lockKey(key), "1", lockout).Err(); err != nil {
+		return fmt.Errorf("set lockout: %w", err)
+	}
+
+	return nil
+}
+
+// lockoutFor computes base * 2^(attempts-Attempts), capped at MaxLockout.
+func (s *Store) lockoutFor(attempts int) time.Duration {
+	excess := attempts - s.cfg.Attempts
+	lockout := time.Duration(float64(s.cfg.BaseLockout) * math.Pow(2, float64(excess)))
+	if lockout > s.cfg.MaxLockout {
+		return s.cfg.MaxLockout
+	}
+	return lockout
+}
+
+// Clear removes both the failure window and any active lock for key, on
+// a successful login/refresh or via the admin unlock endpoint.
+func (s *Store) Clear(ctx context.Context, key string) error {
+	if err := s.rdb.Del(ctx, keyPrefix+"failures:"+key, lockKey(key)).Err(); err != nil {
+		return fmt.Errorf("clear lockout: %w", err)
+	}
+	return nil
+}
+
+func lockKey(key string) string {
+	return keyPrefix + "lock:" + key
+}
@@ -0,0 +1,175 @@
+// AngelaMos | 2026
+// resolver.go
+
+// Package clientip extracts the real client address from a request that may
+// have passed through one or more reverse proxies (ALB, NGINX, Cloudflare,
+// ...). Forwarded-for headers are only trusted when the immediate peer
+// (RemoteAddr) is itself one of the configured trusted proxy CIDRs; a
+// direct, untrusted connection's headers are attacker-controlled and are
+// ignored entirely. Once the peer is trusted, the chain (RFC 7239
+// Forwarded, or X-Forwarded-For) is walked from the right, dropping
+// trusted-proxy hops, and the first untrusted address is returned.
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Config mirrors config.ClientIPConfig; it's a separate type so this
+// package doesn't import internal/config.
+type Config struct {
+	TrustedProxies []string
+	// Headers is the preference order in which forwarding headers are
+	// consulted, e.g. []string{"Forwarded", "X-Forwarded-For"}. Defaults
+	// to Forwarded, X-Forwarded-For, X-Real-IP if empty.
+	Headers []string
+}
+
+var defaultHeaders = []string{"Forwarded", "X-Forwarded-For", "X-Real-IP"}
+
+// Resolver extracts client addresses per Config.
+type Resolver struct {
+	trusted []*net.IPNet
+	headers []string
+}
+
+func NewResolver(cfg Config) (*Resolver, error) {
+	trusted := make([]*net.IPNet, 0, len(cfg.TrustedProxies))
+	for _, cidr := range cfg.TrustedProxies {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("parse trusted proxy %q: %w", cidr, err)
+		}
+		trusted = append(trusted, network)
+	}
+
+	headers := defaultHeaders
+	if len(cfg.Headers) > 0 {
+		headers = cfg.Headers
+	}
+
+	return &Resolver{trusted: trusted, headers: headers}, nil
+}
+
+// ClientIP returns the first untrusted address in r's forwarding chain,
+// falling back to RemoteAddr when the peer isn't a trusted proxy or
+// nothing in the chain can be parsed.
+func (res *Resolver) ClientIP(r *http.Request) string {
+	remoteIP := hostOnly(r.RemoteAddr)
+
+	if !res.isTrusted(remoteIP) {
+		return remoteIP
+	}
+
+	for _, header := range res.headers {
+		var hops []string
+		switch strings.ToLower(header) {
+		case "forwarded":
+			hops = parseForwarded(r.Header.Get(header))
+		case "x-forwarded-for":
+			hops = parseXFF(r.Header.Get(header))
+		default:
+			if v := hostOnly(r.Header.Get(header)); v != "" {
+				hops = []string{v}
+			}
+		}
+
+		if ip, ok := res.firstUntrusted(hops); ok {
+			return ip
+		}
+	}
+
+	return remoteIP
+}
+
+// firstUntrusted walks hops — client-to-proxy order, nearest proxy last —
+// from the right, dropping trusted-proxy addresses, and returns the first
+// one that isn't trusted.
+func (res *Resolver) firstUntrusted(hops []string) (string, bool) {
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := hops[i]
+		if ip == "" {
+			continue
+		}
+		if !res.isTrusted(ip) {
+			return ip, true
+		}
+	}
+	return "", false
+}
+
+func (res *Resolver) isTrusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range res.trusted {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseXFF(header string) []string {
+	if header == "" {
+		return nil
+	}
+
+	parts := strings.Split(header, ",")
+	hops := make([]string, 0, len(parts))
+	for _, p := range parts {
+		hops = append(hops, hostOnly(strings.TrimSpace(p)))
+	}
+	return hops
+}
+
+// parseForwarded extracts the for= parameter from each comma-separated
+// RFC 7239 element, in order. Obfuscated identifiers (e.g. "_hidden",
+// "unknown") don't parse as IPs and come back as empty hops, which
+// firstUntrusted skips over.
+func parseForwarded(header string) []string {
+	if header == "" {
+		return nil
+	}
+
+	elements := strings.Split(header, ",")
+	hops := make([]string, 0, len(elements))
+	for _, element := range elements {
+		for _, pair := range strings.Split(element, ";") {
+			name, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+			if !found || !strings.EqualFold(strings.TrimSpace(name), "for") {
+				continue
+			}
+			hops = append(hops, hostOnly(unquote(strings.TrimSpace(value))))
+			break
+		}
+	}
+	return hops
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// hostOnly strips an optional port from addr, the same way
+// net.SplitHostPort does for "host:port" and bracketed IPv6 like
+// "[::1]:1234", and unwraps brackets from a bare IPv6 literal.
+func hostOnly(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return ""
+	}
+
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return strings.Trim(addr, "[]")
+}
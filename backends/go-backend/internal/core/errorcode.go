@@ -0,0 +1,41 @@
+// AngelaMos | 2026
+// errorcode.go
+
+package core
+
+import "errors"
+
+// ErrorCode classifies a domain error independently of any particular
+// transport, so a single sentinel can be mapped to an HTTP status by the
+// REST handlers and to a gRPC status by internal/transport/grpc without
+// either transport knowing about the other.
+type ErrorCode int
+
+const (
+	ErrCodeUnknown ErrorCode = iota
+	ErrCodeNotFound
+	ErrCodeDuplicateKey
+	ErrCodeForbidden
+	ErrCodeUnauthorized
+	ErrCodeInvalidInput
+)
+
+// CodeOf resolves err to its ErrorCode by walking its wrapped chain against
+// the package's sentinel errors, returning ErrCodeUnknown for anything else
+// (callers should treat that as an internal error).
+func CodeOf(err error) ErrorCode {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return ErrCodeNotFound
+	case errors.Is(err, ErrDuplicateKey):
+		return ErrCodeDuplicateKey
+	case errors.Is(err, ErrForbidden):
+		return ErrCodeForbidden
+	case errors.Is(err, ErrUnauthorized):
+		return ErrCodeUnauthorized
+	case errors.Is(err, ErrInvalidInput):
+		return ErrCodeInvalidInput
+	default:
+		return ErrCodeUnknown
+	}
+}
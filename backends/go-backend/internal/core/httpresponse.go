@@ -0,0 +1,218 @@
+// AngelaMos | 2026
+// httpresponse.go
+
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Sentinel errors a service/repository wraps its own error with via
+// fmt.Errorf("...: %w", core.ErrXxx), so a handler can classify the
+// failure with errors.Is without depending on the originating package's
+// own error types.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrDuplicateKey = errors.New("duplicate key")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrInvalidInput = errors.New("invalid input")
+	ErrTokenExpired = errors.New("token expired")
+	ErrTokenRevoked = errors.New("token revoked")
+	ErrTokenInvalid = errors.New("token invalid")
+)
+
+// AppError is the uniform shape every handler error path converges on
+// before JSONError writes it: Cause is the underlying error (for logging
+// and errors.Is/errors.As chains, never serialized), Message is what the
+// client sees, Status is the HTTP status to respond with, and Code is a
+// machine-readable label a client can switch on without string-matching
+// Message.
+type AppError struct {
+	Cause   error
+	Message string
+	Status  int
+	Code    string
+}
+
+func (e *AppError) Error() string { return e.Message }
+func (e *AppError) Unwrap() error { return e.Cause }
+
+func NewAppError(cause error, message string, status int, code string) *AppError {
+	return &AppError{Cause: cause, Message: message, Status: status, Code: code}
+}
+
+// IsAppError reports whether err (or anything it wraps) already carries a
+// status/code via AppError, so a caller deciding how to respond to a
+// generic error knows whether one was already assigned upstream.
+func IsAppError(err error) bool {
+	var appErr *AppError
+	return errors.As(err, &appErr)
+}
+
+func UnauthorizedError(message string) *AppError {
+	if message == "" {
+		message = "unauthorized"
+	}
+	return NewAppError(ErrUnauthorized, message, http.StatusUnauthorized, "UNAUTHORIZED")
+}
+
+func ForbiddenError(message string) *AppError {
+	if message == "" {
+		message = "forbidden"
+	}
+	return NewAppError(ErrForbidden, message, http.StatusForbidden, "FORBIDDEN")
+}
+
+// DuplicateError reports that field already has a row claiming its value,
+// e.g. DuplicateError("email") for a unique-constraint violation on the
+// email column.
+func DuplicateError(field string) *AppError {
+	return NewAppError(
+		ErrDuplicateKey,
+		fmt.Sprintf("%s already exists", field),
+		http.StatusConflict,
+		"DUPLICATE_KEY",
+	)
+}
+
+func TokenExpiredError() *AppError {
+	return NewAppError(ErrTokenExpired, "token expired", http.StatusUnauthorized, "TOKEN_EXPIRED")
+}
+
+func TokenRevokedError() *AppError {
+	return NewAppError(ErrTokenRevoked, "token revoked", http.StatusUnauthorized, "TOKEN_REVOKED")
+}
+
+func TokenInvalidError() *AppError {
+	return NewAppError(ErrTokenInvalid, "token invalid", http.StatusUnauthorized, "TOKEN_INVALID")
+}
+
+func TooManyRequestsError(message string) *AppError {
+	return NewAppError(nil, message, http.StatusTooManyRequests, "TOO_MANY_REQUESTS")
+}
+
+// FormatValidationError turns a go-playground/validator error into a
+// human-readable message naming every failed field and tag, so a 400
+// response tells the caller exactly what to fix instead of a generic
+// "invalid request body".
+func FormatValidationError(err error) string {
+	var valErrs validator.ValidationErrors
+	if !errors.As(err, &valErrs) {
+		return err.Error()
+	}
+
+	parts := make([]string, 0, len(valErrs))
+	for _, fe := range valErrs {
+		parts = append(parts, fmt.Sprintf("%s failed validation: %s", fe.Field(), fe.Tag()))
+	}
+	return strings.Join(parts, "; ")
+}
+
+type errorEnvelope struct {
+	Success bool      `json:"success"`
+	Error   errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type successEnvelope struct {
+	Success bool `json:"success"`
+	Data    any  `json:"data"`
+}
+
+type paginatedEnvelope struct {
+	Success  bool `json:"success"`
+	Data     any  `json:"data"`
+	Page     int  `json:"page"`
+	PageSize int  `json:"page_size"`
+	Total    int  `json:"total"`
+}
+
+// JSONError writes err as the standard {"success":false,"error":{...}}
+// envelope. When err isn't already an *AppError, it's treated as an
+// unclassified internal error: its own message is never serialized, since
+// it may carry details (SQL text, file paths) not meant for a client.
+func JSONError(w http.ResponseWriter, err error) {
+	var appErr *AppError
+	if !errors.As(err, &appErr) {
+		appErr = NewAppError(err, "internal server error", http.StatusInternalServerError, "INTERNAL_ERROR")
+	}
+
+	writeJSON(w, appErr.Status, errorEnvelope{
+		Success: false,
+		Error: errorBody{
+			Code:    appErr.Code,
+			Message: appErr.Message,
+		},
+	})
+}
+
+// InternalServerError writes a generic 500 response without serializing
+// err itself — the caller is expected to have already logged err via its
+// own structured logger, same as every existing call site does.
+func InternalServerError(w http.ResponseWriter, err error) {
+	JSONError(w, NewAppError(err, "internal server error", http.StatusInternalServerError, "INTERNAL_ERROR"))
+}
+
+func BadRequest(w http.ResponseWriter, message string) {
+	JSONError(w, NewAppError(ErrInvalidInput, message, http.StatusBadRequest, "BAD_REQUEST"))
+}
+
+func Unauthorized(w http.ResponseWriter, message string) {
+	JSONError(w, UnauthorizedError(message))
+}
+
+func Forbidden(w http.ResponseWriter, message string) {
+	JSONError(w, ForbiddenError(message))
+}
+
+// NotFound writes a 404 naming resource, e.g. NotFound(w, "policy").
+func NotFound(w http.ResponseWriter, resource string) {
+	JSONError(w, NewAppError(
+		ErrNotFound,
+		fmt.Sprintf("%s not found", resource),
+		http.StatusNotFound,
+		"NOT_FOUND",
+	))
+}
+
+func OK(w http.ResponseWriter, data any) {
+	writeJSON(w, http.StatusOK, successEnvelope{Success: true, Data: data})
+}
+
+func Created(w http.ResponseWriter, data any) {
+	writeJSON(w, http.StatusCreated, successEnvelope{Success: true, Data: data})
+}
+
+func NoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Paginated writes a page of items alongside the page/pageSize/total a
+// caller needs to fetch the next one.
+func Paginated(w http.ResponseWriter, items any, page, pageSize, total int) {
+	writeJSON(w, http.StatusOK, paginatedEnvelope{
+		Success:  true,
+		Data:     items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	//nolint:errcheck // best-effort response write
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,461 @@
+// AngelaMos | 2026
+// kdf.go
+
+package core
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/scrypt"
+)
+
+// PasswordHasher implements a single password-hashing algorithm behind the
+// `$algo$...` encoded-hash format shared by every registered KDF. Algorithm
+// identifiers are stable once shipped — they're embedded in every stored
+// hash and must stay verifiable indefinitely.
+type PasswordHasher interface {
+	Algorithm() string
+	Hash(password string) (string, error)
+	Verify(password, encodedHash string) (bool, error)
+	// NeedsRehash reports whether an existing hash of this algorithm was
+	// produced with parameters weaker than the hasher's current ones.
+	NeedsRehash(encodedHash string) bool
+}
+
+var kdfRegistry = map[string]PasswordHasher{}
+
+// DefaultAlgorithm selects which registered hasher HashPassword uses for new
+// hashes. Changing it does not invalidate hashes produced under a previous
+// default — VerifyPassword dispatches on the `$algo$` prefix of whatever
+// hash it's given.
+var DefaultAlgorithm = "argon2id"
+
+func RegisterHasher(h PasswordHasher) {
+	kdfRegistry[h.Algorithm()] = h
+}
+
+func init() {
+	RegisterHasher(newArgon2idHasher(argonParams{
+		memory:  argonMemory,
+		time:    argonTime,
+		threads: argonThreads,
+		keyLen:  argonKeyLen,
+	}))
+	RegisterHasher(newScryptHasher(scryptParams{n: 1 << 15, r: 8, p: 1, keyLen: 32}))
+	RegisterHasher(newBcryptHasher(bcrypt.DefaultCost))
+	RegisterHasher(newBlake2bHasher())
+}
+
+func hasherFor(encodedHash string) (PasswordHasher, error) {
+	parts := strings.SplitN(strings.TrimPrefix(encodedHash, "$"), "$", 2)
+	if len(parts) == 0 || parts[0] == "" {
+		return nil, fmt.Errorf("invalid hash format")
+	}
+
+	hasher, ok := kdfRegistry[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf("unsupported algorithm: %s", parts[0])
+	}
+
+	return hasher, nil
+}
+
+// algorithmOf extracts the `$algo$` prefix without fully parsing the hash,
+// used to decide whether a rehash is needed because the default changed.
+func algorithmOf(encodedHash string) string {
+	parts := strings.SplitN(strings.TrimPrefix(encodedHash, "$"), "$", 2)
+	if len(parts) == 0 {
+		return ""
+	}
+	return parts[0]
+}
+
+// ---- argon2id ----
+
+type argonParams struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	keyLen  uint32
+}
+
+func (p argonParams) Memory() uint32 { return p.memory }
+func (p argonParams) Time() uint32   { return p.time }
+
+type argon2idHasher struct {
+	params argonParams
+}
+
+func newArgon2idHasher(params argonParams) *argon2idHasher {
+	return &argon2idHasher{params: params}
+}
+
+func (h *argon2idHasher) Algorithm() string { return "argon2id" }
+
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+
+	hash := argon2.IDKey(
+		[]byte(password),
+		salt,
+		h.params.time,
+		h.params.memory,
+		h.params.threads,
+		h.params.keyLen,
+	)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.params.memory,
+		h.params.time,
+		h.params.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+func (h *argon2idHasher) Verify(password, encodedHash string) (bool, error) {
+	params, salt, hash, err := decodeArgon2idHash(encodedHash)
+	if err != nil {
+		return false, err
+	}
+
+	otherHash := argon2.IDKey(
+		[]byte(password),
+		salt,
+		params.time,
+		params.memory,
+		params.threads,
+		params.keyLen,
+	)
+
+	return subtle.ConstantTimeCompare(hash, otherHash) == 1, nil
+}
+
+func (h *argon2idHasher) NeedsRehash(encodedHash string) bool {
+	params, _, _, err := decodeArgon2idHash(encodedHash)
+	if err != nil {
+		return true
+	}
+
+	return params.memory != h.params.memory ||
+		params.time != h.params.time ||
+		params.threads != h.params.threads ||
+		params.keyLen != h.params.keyLen
+}
+
+func decodeArgon2idHash(encodedHash string) (*argonParams, []byte, []byte, error) {
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 {
+		return nil, nil, nil, fmt.Errorf("invalid hash format")
+	}
+
+	if parts[1] != "argon2id" {
+		return nil, nil, nil, fmt.Errorf("unsupported algorithm: %s", parts[1])
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid version: %w", err)
+	}
+
+	if version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("incompatible version: %d", version)
+	}
+
+	params := &argonParams{}
+	if _, err := fmt.Sscanf(
+		parts[3],
+		"m=%d,t=%d,p=%d",
+		&params.memory,
+		&params.time,
+		&params.threads,
+	); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid params: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("decode salt: %w", err)
+	}
+
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("decode hash: %w", err)
+	}
+
+	//nolint:gosec // G115: hash length is always small (32 bytes for Argon2id)
+	params.keyLen = uint32(len(hash))
+
+	return params, salt, hash, nil
+}
+
+// CalibrateArgon2id runs trial hashes on the current host, doubling memory
+// until a single hash takes at least target, and installs the resulting
+// params as the argon2id hasher used for new hashes. It never reduces time
+// below 1 or memory below the package floor, since those bound the minimum
+// acceptable work factor regardless of host speed.
+func CalibrateArgon2id(target time.Duration) argonParams {
+	params := argonParams{
+		memory:  argonMemory,
+		time:    argonTime,
+		threads: argonThreads,
+		keyLen:  argonKeyLen,
+	}
+
+	const maxMemory = 1 << 20 // 1 GiB ceiling
+
+	for params.memory < maxMemory {
+		start := time.Now()
+		argon2.IDKey([]byte("calibration probe"), make([]byte, saltLength),
+			params.time, params.memory, params.threads, params.keyLen)
+		elapsed := time.Since(start)
+
+		if elapsed >= target {
+			break
+		}
+
+		params.memory *= 2
+	}
+
+	RegisterHasher(newArgon2idHasher(params))
+
+	return params
+}
+
+// ---- scrypt ----
+
+type scryptParams struct {
+	n, r, p, keyLen int
+}
+
+type scryptHasher struct {
+	params scryptParams
+}
+
+func newScryptHasher(params scryptParams) *scryptHasher {
+	return &scryptHasher{params: params}
+}
+
+func (h *scryptHasher) Algorithm() string { return "scrypt" }
+
+func (h *scryptHasher) Hash(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+
+	hash, err := scrypt.Key(
+		[]byte(password), salt,
+		h.params.n, h.params.r, h.params.p, h.params.keyLen,
+	)
+	if err != nil {
+		return "", fmt.Errorf("scrypt hash: %w", err)
+	}
+
+	return fmt.Sprintf(
+		"$scrypt$n=%d,r=%d,p=%d$%s$%s",
+		h.params.n, h.params.r, h.params.p,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+func (h *scryptHasher) Verify(password, encodedHash string) (bool, error) {
+	params, salt, hash, err := decodeScryptHash(encodedHash)
+	if err != nil {
+		return false, err
+	}
+
+	otherHash, err := scrypt.Key(
+		[]byte(password), salt,
+		params.n, params.r, params.p, len(hash),
+	)
+	if err != nil {
+		return false, fmt.Errorf("scrypt verify: %w", err)
+	}
+
+	return subtle.ConstantTimeCompare(hash, otherHash) == 1, nil
+}
+
+func (h *scryptHasher) NeedsRehash(encodedHash string) bool {
+	params, _, _, err := decodeScryptHash(encodedHash)
+	if err != nil {
+		return true
+	}
+	return params.n != h.params.n || params.r != h.params.r || params.p != h.params.p
+}
+
+func decodeScryptHash(encodedHash string) (*scryptParams, []byte, []byte, error) {
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 5 {
+		return nil, nil, nil, fmt.Errorf("invalid hash format")
+	}
+
+	if parts[1] != "scrypt" {
+		return nil, nil, nil, fmt.Errorf("unsupported algorithm: %s", parts[1])
+	}
+
+	params := &scryptParams{}
+	if _, err := fmt.Sscanf(
+		parts[2], "n=%d,r=%d,p=%d", &params.n, &params.r, &params.p,
+	); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid params: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("decode salt: %w", err)
+	}
+
+	hash, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("decode hash: %w", err)
+	}
+
+	params.keyLen = len(hash)
+
+	return params, salt, hash, nil
+}
+
+// ---- bcrypt ----
+
+type bcryptHasher struct {
+	cost int
+}
+
+func newBcryptHasher(cost int) *bcryptHasher {
+	return &bcryptHasher{cost: cost}
+}
+
+func (h *bcryptHasher) Algorithm() string { return "bcrypt" }
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("bcrypt hash: %w", err)
+	}
+	return "$bcrypt$" + string(hash), nil
+}
+
+func (h *bcryptHasher) Verify(password, encodedHash string) (bool, error) {
+	raw := strings.TrimPrefix(encodedHash, "$bcrypt$")
+	err := bcrypt.CompareHashAndPassword([]byte(raw), []byte(password))
+	if err != nil {
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, nil
+		}
+		return false, fmt.Errorf("bcrypt verify: %w", err)
+	}
+	return true, nil
+}
+
+func (h *bcryptHasher) NeedsRehash(encodedHash string) bool {
+	raw := strings.TrimPrefix(encodedHash, "$bcrypt$")
+	cost, err := bcrypt.Cost([]byte(raw))
+	if err != nil {
+		return true
+	}
+	return cost != h.cost
+}
+
+// ---- blake2b ----
+
+// blake2bHasher is a deliberately fast hasher, unlike argon2id/scrypt/
+// bcrypt above: those trade speed for offline brute-force resistance
+// against a low-entropy human password, but that trade is wrong for a
+// high-entropy random secret (e.g. an API token) that's verified on
+// every request and never meant to be memorized. The salt doubles as
+// the keyed-hash key, so there are no tunable cost parameters to drift
+// out of date, which is why NeedsRehash is unconditionally false.
+type blake2bHasher struct{}
+
+func newBlake2bHasher() *blake2bHasher {
+	return &blake2bHasher{}
+}
+
+func (h *blake2bHasher) Algorithm() string { return "blake2b" }
+
+func (h *blake2bHasher) Hash(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+
+	hash, err := blake2bSum(salt, password)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"$blake2b$%s$%s",
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+func (h *blake2bHasher) Verify(password, encodedHash string) (bool, error) {
+	salt, hash, err := decodeBlake2bHash(encodedHash)
+	if err != nil {
+		return false, err
+	}
+
+	otherHash, err := blake2bSum(salt, password)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare(hash, otherHash) == 1, nil
+}
+
+func (h *blake2bHasher) NeedsRehash(encodedHash string) bool {
+	return false
+}
+
+func blake2bSum(salt []byte, password string) ([]byte, error) {
+	mac, err := blake2b.New256(salt)
+	if err != nil {
+		return nil, fmt.Errorf("init blake2b: %w", err)
+	}
+
+	if _, err := mac.Write([]byte(password)); err != nil {
+		return nil, fmt.Errorf("write blake2b input: %w", err)
+	}
+
+	return mac.Sum(nil), nil
+}
+
+func decodeBlake2bHash(encodedHash string) ([]byte, []byte, error) {
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 4 {
+		return nil, nil, fmt.Errorf("invalid hash format")
+	}
+
+	if parts[1] != "blake2b" {
+		return nil, nil, fmt.Errorf("unsupported algorithm: %s", parts[1])
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode salt: %w", err)
+	}
+
+	hash, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode hash: %w", err)
+	}
+
+	return salt, hash, nil
+}
@@ -0,0 +1,84 @@
+// AngelaMos | 2026
+// logging.go
+
+// Package logging builds the application's structured logger and carries
+// a per-request *slog.Logger through context, pre-tagged with enough
+// identifying attributes (request_id, remote_ip, route, user_id once
+// authenticated) that call sites never assemble their own. trace_id is
+// looked up live from the active OTel span on every FromContext call
+// rather than baked in at injection time, since the request logging
+// middleware runs before the tracing middleware starts the span.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+type contextKey string
+
+const loggerKey contextKey = "logging.logger"
+
+// Config mirrors config.LogConfig; it's a separate type so this package
+// doesn't import internal/config.
+type Config struct {
+	Level  string
+	Format string
+}
+
+// New builds the application logger per cfg: slog.NewJSONHandler for
+// Format "json", slog.NewTextHandler otherwise, writing to stdout. The
+// handler is wrapped in a SpanEventHandler unconditionally, so every log
+// line also becomes a span event on whatever OTel span is active when it
+// runs — a no-op when OTel is disabled or no span has been started.
+func New(cfg Config) *slog.Logger {
+	level := slog.LevelInfo
+	switch cfg.Level {
+	case "debug":
+		level = slog.LevelDebug
+	case "warn":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if cfg.Format == "json" {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+
+	return slog.New(NewSpanEventHandler(handler))
+}
+
+// NewContext returns a copy of ctx carrying logger, retrievable with
+// FromContext. The request logging middleware calls this once per
+// request; everything downstream reads the same logger back out rather
+// than building its own.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// FromContext returns the logger stashed by the request logging
+// middleware, with trace_id attached if ctx has an active OTel span. It
+// falls back to slog.Default() when nothing stashed one, which keeps
+// background jobs and tests that never ran the middleware chain from
+// needing a nil check.
+func FromContext(ctx context.Context) *slog.Logger {
+	logger, ok := ctx.Value(loggerKey).(*slog.Logger)
+	if !ok || logger == nil {
+		logger = slog.Default()
+	}
+
+	if traceID := core.TraceIDFromContext(ctx); traceID != "" {
+		logger = logger.With("trace_id", traceID)
+	}
+
+	return logger
+}
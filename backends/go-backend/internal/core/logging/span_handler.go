@@ -0,0 +1,55 @@
+// AngelaMos | 2026
+// span_handler.go
+
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+// SpanEventHandler wraps a slog.Handler and records every handled record
+// as a span event (via core.AddSpanEvent) on ctx's active OTel span, in
+// addition to passing it through to the wrapped handler unchanged. This
+// gives unified structured logs and traces without double-instrumenting
+// call sites: log a line once with slog, and it shows up both in the log
+// sink and inline in the trace viewer next to the span it happened
+// during. When no span is active, AddSpanEvent is a no-op against
+// trace.SpanFromContext's default no-op span.
+type SpanEventHandler struct {
+	next slog.Handler
+}
+
+// NewSpanEventHandler wraps next.
+func NewSpanEventHandler(next slog.Handler) *SpanEventHandler {
+	return &SpanEventHandler{next: next}
+}
+
+func (h *SpanEventHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SpanEventHandler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := make([]attribute.KeyValue, 0, record.NumAttrs()+1)
+	attrs = append(attrs, attribute.String("log.level", record.Level.String()))
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, attribute.String(a.Key, a.Value.String()))
+		return true
+	})
+
+	core.AddSpanEvent(ctx, record.Message, attrs...)
+
+	return h.next.Handle(ctx, record)
+}
+
+func (h *SpanEventHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SpanEventHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *SpanEventHandler) WithGroup(name string) slog.Handler {
+	return &SpanEventHandler{next: h.next.WithGroup(name)}
+}
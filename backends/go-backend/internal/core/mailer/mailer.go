@@ -0,0 +1,100 @@
+// AngelaMos | 2026
+// mailer.go
+
+// Package mailer sends transactional email (password resets, and whatever
+// else needs a "tell this address something" side effect) through a
+// pluggable backend: NewSMTPMailer for real delivery, NewLogMailer to print
+// the message instead of sending it in local development, or NewNoopMailer
+// to discard sends entirely (e.g. in tests).
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/smtp"
+)
+
+// Message is a plain-text transactional email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPConfig mirrors config.SMTPConfig; it's a separate type so this
+// package doesn't import internal/config.
+type SMTPConfig struct {
+	Host        string
+	Port        int
+	Username    string
+	Password    string
+	FromAddress string
+}
+
+type smtpMailer struct {
+	cfg  SMTPConfig
+	addr string
+	auth smtp.Auth
+}
+
+func NewSMTPMailer(cfg SMTPConfig) Mailer {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &smtpMailer{
+		cfg:  cfg,
+		addr: fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		auth: auth,
+	}
+}
+
+func (m *smtpMailer) Send(_ context.Context, msg Message) error {
+	body := fmt.Sprintf(
+		"To: %s\r\nFrom: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		msg.To, m.cfg.FromAddress, msg.Subject, msg.Body,
+	)
+
+	err := smtp.SendMail(
+		m.addr, m.auth, m.cfg.FromAddress, []string{msg.To}, []byte(body),
+	)
+	if err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+
+	return nil
+}
+
+// logMailer writes the message to the application log instead of
+// delivering it, for environments with no SMTP relay configured.
+type logMailer struct{}
+
+func NewLogMailer() Mailer {
+	return &logMailer{}
+}
+
+func (m *logMailer) Send(_ context.Context, msg Message) error {
+	slog.Info("mailer: email not sent (log driver)",
+		"to", msg.To,
+		"subject", msg.Subject,
+		"body", msg.Body,
+	)
+	return nil
+}
+
+// noopMailer discards every send, e.g. for tests.
+type noopMailer struct{}
+
+func NewNoopMailer() Mailer {
+	return &noopMailer{}
+}
+
+func (m *noopMailer) Send(_ context.Context, _ Message) error {
+	return nil
+}
@@ -0,0 +1,209 @@
+// AngelaMos | 2026
+// metrics.go
+
+package core
+
+import (
+	"database/sql"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+)
+
+// Metrics wraps a dedicated Prometheus registry for this service. It is
+// constructed unconditionally at startup; callers that never mount its
+// Handler simply never pay for a scrape, so there's no separate "disabled"
+// code path to maintain here — that lives in admin.Handler instead.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	dbOpenConnections    prometheus.Gauge
+	dbInUseConnections   prometheus.Gauge
+	dbIdleConnections    prometheus.Gauge
+	dbWaitCount          prometheus.Gauge
+	dbWaitDuration       prometheus.Gauge
+	redisHits            prometheus.Gauge
+	redisMisses          prometheus.Gauge
+	redisTimeouts        prometheus.Gauge
+	redisTotalConns      prometheus.Gauge
+	redisIdleConns       prometheus.Gauge
+	runtimeGoroutines    prometheus.Gauge
+	runtimeMemAlloc      prometheus.Gauge
+	runtimeNumGC         prometheus.Gauge
+	httpRequestDuration  *prometheus.HistogramVec
+	httpRequestsTotal    *prometheus.CounterVec
+	httpRequestsInFlight prometheus.Gauge
+	ratelimitRequests    *prometheus.CounterVec
+	ratelimitDenied      *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	registry := prometheus.NewRegistry()
+
+	m := &Metrics{
+		registry: registry,
+
+		dbOpenConnections: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_db_open_connections",
+			Help: "Current number of open database connections.",
+		}),
+		dbInUseConnections: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_db_in_use_connections",
+			Help: "Database connections currently in use.",
+		}),
+		dbIdleConnections: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_db_idle_connections",
+			Help: "Database connections currently idle.",
+		}),
+		dbWaitCount: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_db_wait_count_total",
+			Help: "Cumulative number of connections waited for.",
+		}),
+		dbWaitDuration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_db_wait_duration_seconds_total",
+			Help: "Cumulative time spent waiting for a database connection.",
+		}),
+		redisHits: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_redis_pool_hits",
+			Help: "Redis connection pool hits.",
+		}),
+		redisMisses: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_redis_pool_misses",
+			Help: "Redis connection pool misses.",
+		}),
+		redisTimeouts: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_redis_pool_timeouts",
+			Help: "Redis connection pool timeouts.",
+		}),
+		redisTotalConns: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_redis_pool_total_conns",
+			Help: "Total Redis connections in the pool.",
+		}),
+		redisIdleConns: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_redis_pool_idle_conns",
+			Help: "Idle Redis connections in the pool.",
+		}),
+		runtimeGoroutines: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_runtime_goroutines",
+			Help: "Current number of goroutines.",
+		}),
+		runtimeMemAlloc: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_runtime_mem_alloc_bytes",
+			Help: "Bytes of allocated heap objects.",
+		}),
+		runtimeNumGC: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_runtime_num_gc",
+			Help: "Number of completed garbage collection cycles.",
+		}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "app_http_request_duration_seconds",
+			Help:    "HTTP request duration in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method", "status_class"}),
+		httpRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "app_http_requests_total",
+			Help: "Total HTTP requests processed.",
+		}, []string{"route", "method", "status_class"}),
+		httpRequestsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "app_http_requests_in_flight",
+			Help: "Number of HTTP requests currently being served.",
+		}),
+		ratelimitRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ratelimit_requests_total",
+			Help: "Total rate limiter decisions, by endpoint, caller tier and outcome (allowed/denied).",
+		}, []string{"endpoint", "tier", "outcome"}),
+		ratelimitDenied: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ratelimit_denied_total",
+			Help: "Total requests denied by the rate limiter, by endpoint and caller tier.",
+		}, []string{"endpoint", "tier"}),
+	}
+
+	registry.MustRegister(
+		m.dbOpenConnections,
+		m.dbInUseConnections,
+		m.dbIdleConnections,
+		m.dbWaitCount,
+		m.dbWaitDuration,
+		m.redisHits,
+		m.redisMisses,
+		m.redisTimeouts,
+		m.redisTotalConns,
+		m.redisIdleConns,
+		m.runtimeGoroutines,
+		m.runtimeMemAlloc,
+		m.runtimeNumGC,
+		m.httpRequestDuration,
+		m.httpRequestsTotal,
+		m.httpRequestsInFlight,
+		m.ratelimitRequests,
+		m.ratelimitDenied,
+	)
+
+	return m
+}
+
+// RecordRateLimit tallies one rate limiter decision for endpoint/tier/outcome
+// (outcome is "allowed" or "denied"), additionally bumping ratelimitDenied
+// when outcome is "denied" so an alert can watch a single counter without
+// matching on a label value.
+func (m *Metrics) RecordRateLimit(endpoint, tier, outcome string) {
+	m.ratelimitRequests.WithLabelValues(endpoint, tier, outcome).Inc()
+	if outcome == "denied" {
+		m.ratelimitDenied.WithLabelValues(endpoint, tier).Inc()
+	}
+}
+
+// Handler returns the promhttp scrape endpoint backed by this registry.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RecordDBStats snapshots the database pool into the registry's gauges.
+// WaitCount/WaitDuration are already cumulative on sql.DBStats, so they're
+// reported as gauges set to that running total rather than as Prometheus
+// counters the app would otherwise have to diff itself.
+func (m *Metrics) RecordDBStats(stats sql.DBStats) {
+	m.dbOpenConnections.Set(float64(stats.OpenConnections))
+	m.dbInUseConnections.Set(float64(stats.InUse))
+	m.dbIdleConnections.Set(float64(stats.Idle))
+	m.dbWaitCount.Set(float64(stats.WaitCount))
+	m.dbWaitDuration.Set(stats.WaitDuration.Seconds())
+}
+
+func (m *Metrics) RecordRedisStats(stats *redis.PoolStats) {
+	if stats == nil {
+		return
+	}
+	m.redisHits.Set(float64(stats.Hits))
+	m.redisMisses.Set(float64(stats.Misses))
+	m.redisTimeouts.Set(float64(stats.Timeouts))
+	m.redisTotalConns.Set(float64(stats.TotalConns))
+	m.redisIdleConns.Set(float64(stats.IdleConns))
+}
+
+func (m *Metrics) RecordRuntimeStats() {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	m.runtimeGoroutines.Set(float64(runtime.NumGoroutine()))
+	m.runtimeMemAlloc.Set(float64(memStats.Alloc))
+	m.runtimeNumGC.Set(float64(memStats.NumGC))
+}
+
+// ObserveHTTPRequest records one completed request against the per-route
+// duration histogram and status counter. statusClass is the "2xx"/"4xx"/
+// "5xx" bucket rather than the raw code, to keep label cardinality bounded.
+func (m *Metrics) ObserveHTTPRequest(
+	route, method, statusClass string,
+	duration time.Duration,
+) {
+	m.httpRequestDuration.WithLabelValues(route, method, statusClass).Observe(duration.Seconds())
+	m.httpRequestsTotal.WithLabelValues(route, method, statusClass).Inc()
+}
+
+func (m *Metrics) IncInFlight() { m.httpRequestsInFlight.Inc() }
+func (m *Metrics) DecInFlight() { m.httpRequestsInFlight.Dec() }
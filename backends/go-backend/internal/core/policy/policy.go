@@ -0,0 +1,120 @@
+// AngelaMos | 2026
+// policy.go
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+var ErrPolicyNotFound = errors.New("policy not found")
+
+// UserEnv exposes the authenticated caller's claims to a policy
+// expression as `user.*`. Field names are remapped via the `expr` tag so
+// expressions read `user.role`/`user.tier` rather than Go's exported
+// `Role`/`Tier`.
+type UserEnv struct {
+	ID   string `expr:"id"`
+	Role string `expr:"role"`
+	Tier string `expr:"tier"`
+}
+
+// RequestEnv exposes request metadata to a policy expression as
+// `request.*`.
+type RequestEnv struct {
+	Method  string            `expr:"method"`
+	Path    string            `expr:"path"`
+	Headers map[string]string `expr:"headers"`
+}
+
+// Env is the typed environment every policy expression is compiled and
+// run against. Resource carries whatever attributes the caller (usually
+// a route's URL params) supplies for resource-scoped rules, e.g.
+// `resource.owner_id == user.id`.
+type Env struct {
+	User     UserEnv        `expr:"user"`
+	Request  RequestEnv     `expr:"request"`
+	Resource map[string]any `expr:"resource"`
+}
+
+// Registry holds one compiled *vm.Program per named policy. Expressions
+// are compiled once at load time (or reload time) so a malformed policy
+// fails fast with a clear error instead of on the first matching request.
+type Registry struct {
+	mu       sync.RWMutex
+	programs map[string]*vm.Program
+	sources  map[string]string
+}
+
+// NewRegistry compiles policies (name -> expr-lang expression) into a
+// Registry. It returns an error naming the first policy that fails to
+// compile, so a typo in config is caught at startup rather than at
+// request time.
+func NewRegistry(policies map[string]string) (*Registry, error) {
+	reg := &Registry{}
+	if err := reg.Reload(policies); err != nil {
+		return nil, err
+	}
+	return reg, nil
+}
+
+// Reload recompiles the registry's entire policy set in one shot (e.g. on
+// SIGHUP), swapping it in only if every expression compiles cleanly —
+// a bad edit to one policy doesn't take down the others already serving
+// traffic.
+func (r *Registry) Reload(policies map[string]string) error {
+	compiled := make(map[string]*vm.Program, len(policies))
+
+	for name, source := range policies {
+		program, err := expr.Compile(source, expr.Env(Env{}), expr.AsBool())
+		if err != nil {
+			return fmt.Errorf("compile policy %q: %w", name, err)
+		}
+		compiled[name] = program
+	}
+
+	r.mu.Lock()
+	r.programs = compiled
+	r.sources = policies
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Evaluate runs the named policy against env and returns whether it
+// allows the request.
+func (r *Registry) Evaluate(name string, env Env) (bool, error) {
+	r.mu.RLock()
+	program, ok := r.programs[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return false, fmt.Errorf("evaluate policy %q: %w", name, ErrPolicyNotFound)
+	}
+
+	out, err := expr.Run(program, env)
+	if err != nil {
+		return false, fmt.Errorf("run policy %q: %w", name, err)
+	}
+
+	allowed, ok := out.(bool)
+	if !ok {
+		return false, fmt.Errorf("policy %q did not evaluate to a bool", name)
+	}
+
+	return allowed, nil
+}
+
+// Source returns the raw expression a policy was compiled from, for the
+// /admin/policies/test debugging endpoint.
+func (r *Registry) Source(name string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	source, ok := r.sources[name]
+	return source, ok
+}
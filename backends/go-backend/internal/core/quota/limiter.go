@@ -0,0 +1,266 @@
+// AngelaMos | 2026
+// limiter.go
+
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	redis_rate "github.com/go-redis/redis_rate/v10"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+)
+
+// invalidationChannel is the Redis pub/sub channel tier changes are
+// published on, so every process's local fallback limiter (see below)
+// drops its cached entry for the affected user instead of continuing to
+// enforce the user's previous tier until it expires on its own.
+const invalidationChannel = "quota:tier_invalidation"
+
+const (
+	statsTierKeyPrefix = "quota:stats:tier:"
+	statsTopUsersKey   = "quota:stats:top_limited_users"
+	fallbackEntryTTL   = 10 * time.Minute
+)
+
+// Limiter enforces per-tier request quotas on top of redis_rate, records
+// the hit-rate/top-offender counters admin.Handler surfaces at
+// /admin/stats, and falls back to an in-process limiter (stale on tier
+// change until invalidated) when Redis is unreachable.
+type Limiter struct {
+	rdb      *redis.Client
+	limiter  *redis_rate.Limiter
+	policies PolicySet
+	fallback sync.Map // userID -> *fallbackEntry
+}
+
+type fallbackEntry struct {
+	tier       string
+	limiter    *rate.Limiter
+	lastAccess int64
+}
+
+// NewLimiter builds a Limiter and starts its background subscription to
+// tier-change invalidations. Callers should keep one Limiter per process.
+func NewLimiter(rdb *redis.Client, policies PolicySet) *Limiter {
+	l := &Limiter{
+		rdb:      rdb,
+		limiter:  redis_rate.NewLimiter(rdb),
+		policies: policies,
+	}
+
+	go l.subscribeInvalidation(context.Background())
+	go l.cleanupFallback()
+
+	return l
+}
+
+func (l *Limiter) cleanupFallback() {
+	ticker := time.NewTicker(fallbackEntryTTL)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		cutoff := time.Now().Add(-fallbackEntryTTL).Unix()
+		l.fallback.Range(func(key, value any) bool {
+			entry, ok := value.(*fallbackEntry)
+			if ok && entry.lastAccess < cutoff {
+				l.fallback.Delete(key)
+			}
+			return true
+		})
+	}
+}
+
+// Allow checks whether userID on tier may make one more request this
+// window. A nil result with a nil error means tier is unlimited and the
+// caller should let the request through without inspecting headers.
+func (l *Limiter) Allow(
+	ctx context.Context,
+	userID, tier string,
+) (*redis_rate.Result, error) {
+	policy := l.policies.Lookup(tier)
+	if policy.Unlimited {
+		return nil, nil
+	}
+
+	limit := limitFor(policy)
+	key := "quota:user:" + userID
+
+	res, err := l.limiter.Allow(ctx, key, limit)
+	if err != nil {
+		res, err = l.allowFallback(userID, tier, limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	l.recordHit(ctx, tier, userID, res.Allowed == 0)
+
+	return res, nil
+}
+
+// LimitFor exposes the redis_rate.Limit backing tier's policy, so callers
+// writing rate-limit response headers don't need to re-derive it.
+func (l *Limiter) LimitFor(tier string) redis_rate.Limit {
+	return limitFor(l.policies.Lookup(tier))
+}
+
+func limitFor(policy Policy) redis_rate.Limit {
+	return redis_rate.Limit{
+		Rate:   policy.RequestsPerMinute,
+		Burst:  policy.Burst,
+		Period: time.Minute,
+	}
+}
+
+func (l *Limiter) allowFallback(
+	userID, tier string,
+	limit redis_rate.Limit,
+) (*redis_rate.Result, error) {
+	ratePerSec := float64(limit.Rate) / limit.Period.Seconds()
+	now := time.Now().Unix()
+
+	entryI, loaded := l.fallback.Load(userID)
+	entry, ok := entryI.(*fallbackEntry)
+	if !loaded || !ok || entry.tier != tier {
+		entry = &fallbackEntry{
+			tier:    tier,
+			limiter: rate.NewLimiter(rate.Limit(ratePerSec), limit.Burst),
+		}
+		l.fallback.Store(userID, entry)
+	}
+	entry.lastAccess = now
+
+	allowed := entry.limiter.Allow()
+
+	remaining := int(entry.limiter.Tokens())
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	retryAfter := time.Duration(-1)
+	if !allowed {
+		retryAfter = time.Duration(float64(time.Second) / ratePerSec)
+	}
+
+	allowedInt := 0
+	if allowed {
+		allowedInt = 1
+	}
+
+	return &redis_rate.Result{
+		Limit:      limit,
+		Allowed:    allowedInt,
+		Remaining:  remaining,
+		RetryAfter: retryAfter,
+		ResetAfter: time.Duration(float64(time.Second) / ratePerSec),
+	}, nil
+}
+
+func (l *Limiter) recordHit(ctx context.Context, tier, userID string, limited bool) {
+	pipe := l.rdb.Pipeline()
+	pipe.HIncrBy(ctx, statsTierKeyPrefix+tier, "total", 1)
+	if limited {
+		pipe.HIncrBy(ctx, statsTierKeyPrefix+tier, "limited", 1)
+		pipe.ZIncrBy(ctx, statsTopUsersKey, 1, userID)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Warn("quota: failed to record stats", "error", err, "tier", tier)
+	}
+}
+
+// TierStats is one tier's cumulative request/limited counts for
+// /admin/stats.
+type TierStats struct {
+	Tier    string  `json:"tier"`
+	Total   int64   `json:"total"`
+	Limited int64   `json:"limited"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// TopLimitedUser is one entry in the /admin/stats leaderboard of users
+// hitting their quota most often.
+type TopLimitedUser struct {
+	UserID string `json:"user_id"`
+	Count  int64  `json:"count"`
+}
+
+// Stats returns per-tier hit rates and the topN most-limited users, for
+// admin.Handler to fold into its system stats response.
+func (l *Limiter) Stats(ctx context.Context, topN int64) ([]TierStats, []TopLimitedUser, error) {
+	tiers := make([]TierStats, 0, len(l.policies))
+	for tier := range l.policies {
+		vals, err := l.rdb.HGetAll(ctx, statsTierKeyPrefix+tier).Result()
+		if err != nil {
+			return nil, nil, fmt.Errorf("get tier stats for %s: %w", tier, err)
+		}
+
+		total, _ := strconv.ParseInt(vals["total"], 10, 64)
+		limited, _ := strconv.ParseInt(vals["limited"], 10, 64)
+
+		var hitRate float64
+		if total > 0 {
+			hitRate = float64(limited) / float64(total)
+		}
+
+		tiers = append(tiers, TierStats{
+			Tier:    tier,
+			Total:   total,
+			Limited: limited,
+			HitRate: hitRate,
+		})
+	}
+
+	members, err := l.rdb.ZRevRangeWithScores(ctx, statsTopUsersKey, 0, topN-1).Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("get top limited users: %w", err)
+	}
+
+	top := make([]TopLimitedUser, 0, len(members))
+	for _, m := range members {
+		userID, _ := m.Member.(string)
+		top = append(top, TopLimitedUser{UserID: userID, Count: int64(m.Score)})
+	}
+
+	return tiers, top, nil
+}
+
+type tierChangeMessage struct {
+	UserID string `json:"user_id"`
+	Tier   string `json:"tier"`
+}
+
+// PublishTierChange notifies every Limiter instance that userID's tier
+// changed, so stale local fallback state doesn't outlive the change. It
+// satisfies user.TierChangeNotifier, wired in from user.Service's
+// UpdateUserTier.
+func (l *Limiter) PublishTierChange(ctx context.Context, userID, tier string) error {
+	payload, err := json.Marshal(tierChangeMessage{UserID: userID, Tier: tier})
+	if err != nil {
+		return fmt.Errorf("marshal tier change: %w", err)
+	}
+
+	return l.rdb.Publish(ctx, invalidationChannel, payload).Err()
+}
+
+func (l *Limiter) subscribeInvalidation(ctx context.Context) {
+	sub := l.rdb.Subscribe(ctx, invalidationChannel)
+	defer sub.Close() //nolint:errcheck // best-effort cleanup on process exit
+
+	for msg := range sub.Channel() {
+		var change tierChangeMessage
+		if err := json.Unmarshal([]byte(msg.Payload), &change); err != nil {
+			slog.Warn("quota: invalid tier invalidation payload", "error", err)
+			continue
+		}
+
+		l.fallback.Delete(change.UserID)
+	}
+}
@@ -0,0 +1,25 @@
+// AngelaMos | 2026
+// quota.go
+
+package quota
+
+// Policy is one subscription tier's request budget, e.g. "100 req/min for
+// Free". Unlimited tiers (typically Enterprise) skip the Redis round trip
+// entirely rather than being given an arbitrarily high limit.
+type Policy struct {
+	RequestsPerMinute int
+	Burst             int
+	Unlimited         bool
+}
+
+// PolicySet maps a tier name ("free", "pro", "enterprise") to its Policy.
+type PolicySet map[string]Policy
+
+// Lookup returns tier's Policy, falling back to "free" for an unknown or
+// empty tier the same way middleware.GetUserTier's callers already do.
+func (s PolicySet) Lookup(tier string) Policy {
+	if policy, ok := s[tier]; ok {
+		return policy
+	}
+	return s["free"]
+}
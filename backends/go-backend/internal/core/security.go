@@ -10,9 +10,6 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
-	"strings"
-
-	"golang.org/x/crypto/argon2"
 )
 
 const (
@@ -23,57 +20,43 @@ const (
 	saltLength   = 16
 )
 
+// HashPassword encodes the password with the registry's DefaultAlgorithm
+// hasher, producing a self-describing `$algo$...` hash.
 func HashPassword(password string) (string, error) {
-	salt := make([]byte, saltLength)
-	if _, err := rand.Read(salt); err != nil {
-		return "", fmt.Errorf("generate salt: %w", err)
+	hasher, ok := kdfRegistry[DefaultAlgorithm]
+	if !ok {
+		return "", fmt.Errorf("no hasher registered for default algorithm %q", DefaultAlgorithm)
+	}
+
+	return hasher.Hash(password)
+}
+
+// HashWithAlgorithm hashes secret with the registered hasher for algorithm
+// rather than DefaultAlgorithm, for credentials that pick their own
+// algorithm independent of the password default — an API token secret
+// hashed with the fast "blake2b" hasher, for instance, rather than
+// whatever DefaultAlgorithm happens to be set for passwords. Verification
+// still goes through the ordinary VerifyPassword, since it already
+// dispatches on the hash's own `$algo$` prefix.
+func HashWithAlgorithm(algorithm, secret string) (string, error) {
+	hasher, ok := kdfRegistry[algorithm]
+	if !ok {
+		return "", fmt.Errorf("no hasher registered for algorithm %q", algorithm)
 	}
 
-	hash := argon2.IDKey(
-		[]byte(password),
-		salt,
-		argonTime,
-		argonMemory,
-		argonThreads,
-		argonKeyLen,
-	)
-
-	b64Salt := base64.RawStdEncoding.EncodeToString(salt)
-	b64Hash := base64.RawStdEncoding.EncodeToString(hash)
-
-	encoded := fmt.Sprintf(
-		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
-		argon2.Version,
-		argonMemory,
-		argonTime,
-		argonThreads,
-		b64Salt,
-		b64Hash,
-	)
-
-	return encoded, nil
+	return hasher.Hash(secret)
 }
 
+// VerifyPassword dispatches to whichever hasher produced encodedHash (read
+// from its `$algo$` prefix), so hashes minted under a previous default
+// algorithm stay verifiable after DefaultAlgorithm changes.
 func VerifyPassword(password, encodedHash string) (bool, error) {
-	params, salt, hash, err := decodeHash(encodedHash)
+	hasher, err := hasherFor(encodedHash)
 	if err != nil {
 		return false, err
 	}
 
-	otherHash := argon2.IDKey(
-		[]byte(password),
-		salt,
-		params.time,
-		params.memory,
-		params.threads,
-		params.keyLen,
-	)
-
-	if subtle.ConstantTimeCompare(hash, otherHash) == 1 {
-		return true, nil
-	}
-
-	return false, nil
+	return hasher.Verify(password, encodedHash)
 }
 
 func VerifyPasswordWithRehash(
@@ -128,71 +111,21 @@ func VerifyPasswordTimingSafe(
 	return valid, newHash, err
 }
 
-type argonParams struct {
-	memory  uint32
-	time    uint32
-	threads uint8
-	keyLen  uint32
-}
-
-func decodeHash(encodedHash string) (*argonParams, []byte, []byte, error) {
-	parts := strings.Split(encodedHash, "$")
-	if len(parts) != 6 {
-		return nil, nil, nil, fmt.Errorf("invalid hash format")
-	}
-
-	if parts[1] != "argon2id" {
-		return nil, nil, nil, fmt.Errorf("unsupported algorithm: %s", parts[1])
-	}
-
-	var version int
-	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("invalid version: %w", err)
-	}
-
-	if version != argon2.Version {
-		return nil, nil, nil, fmt.Errorf("incompatible version: %d", version)
-	}
-
-	params := &argonParams{}
-	_, err = fmt.Sscanf(
-		parts[3],
-		"m=%d,t=%d,p=%d",
-		&params.memory,
-		&params.time,
-		&params.threads,
-	)
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("invalid params: %w", err)
-	}
-
-	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("decode salt: %w", err)
-	}
-
-	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("decode hash: %w", err)
+// needsRehash reports whether encodedHash should be replaced with one
+// produced by the current DefaultAlgorithm — either because it was hashed
+// with a different algorithm entirely, or because that algorithm's own
+// hasher considers its parameters stale.
+func needsRehash(encodedHash string) bool {
+	if algorithmOf(encodedHash) != DefaultAlgorithm {
+		return true
 	}
 
-	//nolint:gosec // G115: hash length is always small (32 bytes for Argon2id)
-	params.keyLen = uint32(len(hash))
-
-	return params, salt, hash, nil
-}
-
-func needsRehash(encodedHash string) bool {
-	params, _, _, err := decodeHash(encodedHash)
+	hasher, err := hasherFor(encodedHash)
 	if err != nil {
 		return true
 	}
 
-	return params.memory != argonMemory ||
-		params.time != argonTime ||
-		params.threads != argonThreads ||
-		params.keyLen != argonKeyLen
+	return hasher.NeedsRehash(encodedHash)
 }
 
 func GenerateSecureToken(length int) (string, error) {
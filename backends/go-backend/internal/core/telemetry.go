@@ -10,8 +10,17 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
@@ -22,9 +31,24 @@ import (
 	"github.com/carterperez-dev/templates/go-backend/internal/config"
 )
 
+// Telemetry bundles the tracer, meter, and (optionally) logger providers
+// that share one OTLP endpoint, TLS config, resource, and shutdown
+// lifecycle. LoggerProvider is nil unless otel.logs_enabled is set, since
+// most operators adopt traces and metrics well before they're ready to
+// ship application logs to a collector too.
 type Telemetry struct {
 	TracerProvider *sdktrace.TracerProvider
 	Tracer         trace.Tracer
+
+	MeterProvider *sdkmetric.MeterProvider
+	Meter         metric.Meter
+
+	LoggerProvider *sdklog.LoggerProvider
+
+	requestDuration metric.Float64Histogram
+	requestTotal    metric.Int64Counter
+	refreshReuse    metric.Int64Counter
+	dbOpenConns     metric.Int64ObservableGauge
 }
 
 func NewTelemetry(
@@ -33,32 +57,16 @@ func NewTelemetry(
 	appCfg config.AppConfig,
 ) (*Telemetry, error) {
 	if !otelCfg.Enabled || otelCfg.Endpoint == "" {
-		noopProvider := sdktrace.NewTracerProvider()
+		noopTracer := sdktrace.NewTracerProvider()
+		noopMeter := sdkmetric.NewMeterProvider()
 		return &Telemetry{
-			TracerProvider: noopProvider,
-			Tracer:         noopProvider.Tracer(otelCfg.ServiceName),
+			TracerProvider: noopTracer,
+			Tracer:         noopTracer.Tracer(otelCfg.ServiceName),
+			MeterProvider:  noopMeter,
+			Meter:          noopMeter.Meter(otelCfg.ServiceName),
 		}, nil
 	}
 
-	opts := []otlptracegrpc.Option{
-		otlptracegrpc.WithEndpoint(otelCfg.Endpoint),
-		otlptracegrpc.WithTimeout(5 * time.Second),
-	}
-
-	if otelCfg.Insecure {
-		opts = append(
-			opts,
-			otlptracegrpc.WithTLSCredentials(insecure.NewCredentials()),
-		)
-	} else {
-		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, "")))
-	}
-
-	exporter, err := otlptracegrpc.New(ctx, opts...)
-	if err != nil {
-		return nil, fmt.Errorf("create otlp exporter: %w", err)
-	}
-
 	res, err := resource.New(ctx,
 		resource.WithAttributes(
 			semconv.ServiceName(otelCfg.ServiceName),
@@ -72,13 +80,18 @@ func NewTelemetry(
 		return nil, fmt.Errorf("create resource: %w", err)
 	}
 
+	traceExporter, err := newTraceExporter(ctx, otelCfg)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp trace exporter: %w", err)
+	}
+
 	sampleRate := otelCfg.SampleRate
 	if sampleRate <= 0 || sampleRate > 1 {
 		sampleRate = 0.1
 	}
 
 	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter,
+		sdktrace.WithBatcher(traceExporter,
 			sdktrace.WithBatchTimeout(5*time.Second),
 			sdktrace.WithMaxExportBatchSize(512),
 		),
@@ -94,10 +107,272 @@ func NewTelemetry(
 		propagation.Baggage{},
 	))
 
-	return &Telemetry{
+	metricExporter, err := newMetricExporter(ctx, otelCfg)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp metric exporter: %w", err)
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter,
+			sdkmetric.WithInterval(15*time.Second),
+		)),
+		sdkmetric.WithResource(res),
+	)
+
+	otel.SetMeterProvider(mp)
+
+	t := &Telemetry{
 		TracerProvider: tp,
 		Tracer:         tp.Tracer(otelCfg.ServiceName),
-	}, nil
+		MeterProvider:  mp,
+		Meter:          mp.Meter(otelCfg.ServiceName),
+	}
+
+	if err := t.initInstruments(); err != nil {
+		return nil, fmt.Errorf("create metric instruments: %w", err)
+	}
+
+	if otelCfg.LogsEnabled {
+		lp, err := newLoggerProvider(ctx, otelCfg, res)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp logger provider: %w", err)
+		}
+		global.SetLoggerProvider(lp)
+		t.LoggerProvider = lp
+	}
+
+	return t, nil
+}
+
+// initInstruments pre-creates the handful of named instruments the RED
+// helpers (RecordAuthRequest, IncRefreshReuse) use on every call, so those
+// hot paths never pay for a Meter.Float64Histogram lookup per request.
+func (t *Telemetry) initInstruments() error {
+	var err error
+
+	t.requestDuration, err = t.Meter.Float64Histogram(
+		"auth.request.duration",
+		metric.WithDescription("Duration of auth service operations, by endpoint and outcome."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return err
+	}
+
+	t.requestTotal, err = t.Meter.Int64Counter(
+		"auth.request.count",
+		metric.WithDescription("Count of auth service operations, by endpoint and outcome."),
+	)
+	if err != nil {
+		return err
+	}
+
+	t.refreshReuse, err = t.Meter.Int64Counter(
+		"auth.refresh_token.reuse",
+		metric.WithDescription("Count of refresh tokens presented after already being used, indicating possible theft."),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// newTraceExporter builds the OTLP span exporter for the configured
+// transport. Operators can point at a collector's gRPC or HTTP receiver
+// without changing anything else in the telemetry wiring.
+func newTraceExporter(
+	ctx context.Context,
+	otelCfg config.OtelConfig,
+) (sdktrace.SpanExporter, error) {
+	if otelCfg.Protocol == "http" {
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(otelCfg.Endpoint),
+			otlptracehttp.WithTimeout(5 * time.Second),
+		}
+		if otelCfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(otelCfg.Endpoint),
+		otlptracegrpc.WithTimeout(5 * time.Second),
+	}
+
+	if otelCfg.Insecure {
+		opts = append(
+			opts,
+			otlptracegrpc.WithTLSCredentials(insecure.NewCredentials()),
+		)
+	} else {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, "")))
+	}
+
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+// newMetricExporter mirrors newTraceExporter's transport selection for the
+// OTLP metric pipeline.
+func newMetricExporter(
+	ctx context.Context,
+	otelCfg config.OtelConfig,
+) (sdkmetric.Exporter, error) {
+	if otelCfg.Protocol == "http" {
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithEndpoint(otelCfg.Endpoint),
+			otlpmetrichttp.WithTimeout(5 * time.Second),
+		}
+		if otelCfg.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(otelCfg.Endpoint),
+		otlpmetricgrpc.WithTimeout(5 * time.Second),
+	}
+
+	if otelCfg.Insecure {
+		opts = append(
+			opts,
+			otlpmetricgrpc.WithTLSCredentials(insecure.NewCredentials()),
+		)
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, "")))
+	}
+
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+// newLoggerProvider mirrors newTraceExporter's transport selection for the
+// OTLP log pipeline. It's only called when otel.logs_enabled is set, since
+// the OTel Go logs SDK is newer than traces/metrics and operators tend to
+// opt in separately.
+func newLoggerProvider(
+	ctx context.Context,
+	otelCfg config.OtelConfig,
+	res *resource.Resource,
+) (*sdklog.LoggerProvider, error) {
+	var (
+		exporter sdklog.Exporter
+		err      error
+	)
+
+	if otelCfg.Protocol == "http" {
+		opts := []otlploghttp.Option{
+			otlploghttp.WithEndpoint(otelCfg.Endpoint),
+			otlploghttp.WithTimeout(5 * time.Second),
+		}
+		if otelCfg.Insecure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		exporter, err = otlploghttp.New(ctx, opts...)
+	} else {
+		opts := []otlploggrpc.Option{
+			otlploggrpc.WithEndpoint(otelCfg.Endpoint),
+			otlploggrpc.WithTimeout(5 * time.Second),
+		}
+		if otelCfg.Insecure {
+			opts = append(opts, otlploggrpc.WithInsecure())
+		} else {
+			opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, "")))
+		}
+		exporter, err = otlploggrpc.New(ctx, opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+		sdklog.WithResource(res),
+	), nil
+}
+
+// RecordAuthRequest records one RED (rate/errors/duration) sample for an
+// auth service operation, keyed by endpoint (e.g. "login", "refresh") and
+// outcome ("success" or an error reason).
+func (t *Telemetry) RecordAuthRequest(ctx context.Context, endpoint, outcome string, duration time.Duration) {
+	if t.requestDuration == nil || t.requestTotal == nil {
+		return
+	}
+
+	attrs := metric.WithAttributes(
+		attribute.String("endpoint", endpoint),
+		attribute.String("outcome", outcome),
+	)
+	t.requestDuration.Record(ctx, duration.Seconds(), attrs)
+	t.requestTotal.Add(ctx, 1, attrs)
+}
+
+// IncRefreshReuse increments the refresh-token reuse counter, recorded
+// whenever Service.Refresh sees an already-used token presented again.
+func (t *Telemetry) IncRefreshReuse(ctx context.Context) {
+	if t.refreshReuse == nil {
+		return
+	}
+	t.refreshReuse.Add(ctx, 1)
+}
+
+// RecordHistogram records value against name, lazily creating a
+// Float64Histogram instrument the first time name is seen. It exists
+// alongside the typed RED helpers above for ad hoc instrumentation that
+// doesn't warrant its own dedicated method.
+func (t *Telemetry) RecordHistogram(ctx context.Context, name string, value float64, attrs ...attribute.KeyValue) {
+	if t.Meter == nil {
+		return
+	}
+	h, err := t.Meter.Float64Histogram(name)
+	if err != nil {
+		return
+	}
+	h.Record(ctx, value, metric.WithAttributes(attrs...))
+}
+
+// IncCounter increments name by one, lazily creating an Int64Counter
+// instrument the first time name is seen.
+func (t *Telemetry) IncCounter(ctx context.Context, name string, attrs ...attribute.KeyValue) {
+	if t.Meter == nil {
+		return
+	}
+	c, err := t.Meter.Int64Counter(name)
+	if err != nil {
+		return
+	}
+	c.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+// RegisterDBPoolGauges wires an observable gauge that reports db's pool
+// stats on every metric collection, rather than requiring callers to poll
+// db.Stats() and push the values themselves.
+func (t *Telemetry) RegisterDBPoolGauges(db *Database) error {
+	if t.Meter == nil || db == nil {
+		return nil
+	}
+
+	openConns, err := t.Meter.Int64ObservableGauge(
+		"db.client.connections.usage",
+		metric.WithDescription("Database connections by pool state (open, in_use, idle)."),
+	)
+	if err != nil {
+		return err
+	}
+	t.dbOpenConns = openConns
+
+	_, err = t.Meter.RegisterCallback(
+		func(_ context.Context, o metric.Observer) error {
+			stats := db.Stats()
+			o.ObserveInt64(openConns, int64(stats.OpenConnections), metric.WithAttributes(attribute.String("state", "open")))
+			o.ObserveInt64(openConns, int64(stats.InUse), metric.WithAttributes(attribute.String("state", "in_use")))
+			o.ObserveInt64(openConns, int64(stats.Idle), metric.WithAttributes(attribute.String("state", "idle")))
+			return nil
+		},
+		openConns,
+	)
+	return err
 }
 
 func (t *Telemetry) Shutdown(ctx context.Context) error {
@@ -112,6 +387,18 @@ func (t *Telemetry) Shutdown(ctx context.Context) error {
 		return fmt.Errorf("shutdown tracer provider: %w", err)
 	}
 
+	if t.MeterProvider != nil {
+		if err := t.MeterProvider.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("shutdown meter provider: %w", err)
+		}
+	}
+
+	if t.LoggerProvider != nil {
+		if err := t.LoggerProvider.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("shutdown logger provider: %w", err)
+		}
+	}
+
 	return nil
 }
 
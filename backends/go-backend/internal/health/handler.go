@@ -4,42 +4,51 @@
 package health
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
-	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
-type Checker interface {
-	Ping(ctx context.Context) error
-}
-
+// Handler serves the kubelet-facing liveness/readiness probes and an
+// authenticated detail endpoint, all backed by a Registry of checkers
+// that refresh in the background rather than on every request.
 type Handler struct {
-	db       Checker
-	redis    Checker
+	registry *Registry
 	ready    atomic.Bool
 	shutdown atomic.Bool
 }
 
-func NewHandler(db, redis Checker) *Handler {
-	h := &Handler{
-		db:    db,
-		redis: redis,
-	}
+// NewHandler wraps registry, which should already have its checkers
+// registered (and Start called) before requests start arriving.
+func NewHandler(registry *Registry) *Handler {
+	h := &Handler{registry: registry}
 	h.ready.Store(true)
 	return h
 }
 
+// RegisterRoutes mounts the unauthenticated probe endpoints used by
+// orchestrators. It does not mount /health/detail — call
+// RegisterDetailRoute for that, behind whatever auth the caller wants.
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Get("/healthz", h.Liveness)
 	r.Get("/livez", h.Liveness)
 	r.Get("/readyz", h.Readiness)
 }
 
+// RegisterDetailRoute mounts /health/detail behind authenticator and
+// gate, mirroring how admin routes are gated in cmd/api/main.go. The
+// detail view exposes per-checker history and error messages that
+// shouldn't be visible to an unauthenticated prober.
+func (h *Handler) RegisterDetailRoute(r chi.Router, authenticator, gate func(http.Handler) http.Handler) {
+	r.Route("/health", func(r chi.Router) {
+		r.Use(authenticator)
+		r.Use(gate)
+		r.Get("/detail", h.Detail)
+	})
+}
+
 func (h *Handler) Liveness(w http.ResponseWriter, r *http.Request) {
 	if h.shutdown.Load() {
 		h.writeStatus(w, http.StatusServiceUnavailable, StatusResponse{
@@ -53,6 +62,12 @@ func (h *Handler) Liveness(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Readiness serves the registry's cached results — it never probes a
+// checker live, so a burst of readiness polls can't turn into a load
+// amplifier against the database or redis. A critical checker's failure
+// fails readiness with 503; a non-critical checker's failure only
+// degrades it, with 200, so e.g. a flaky third-party OIDC issuer doesn't
+// take the whole service out of rotation.
 func (h *Handler) Readiness(w http.ResponseWriter, r *http.Request) {
 	if h.shutdown.Load() {
 		h.writeStatus(w, http.StatusServiceUnavailable, StatusResponse{
@@ -68,98 +83,44 @@ func (h *Handler) Readiness(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
-
-	checks := h.runHealthChecks(ctx)
+	results := h.registry.Results()
 
-	allHealthy := true
-	for _, check := range checks {
-		if !check.Healthy {
-			allHealthy = false
-			break
+	criticalFailure := false
+	degraded := false
+	for _, result := range results {
+		if result.Healthy {
+			continue
+		}
+		if result.Critical {
+			criticalFailure = true
+		} else {
+			degraded = true
 		}
 	}
 
 	status := "ok"
 	statusCode := http.StatusOK
-	if !allHealthy {
-		status = "degraded"
+	switch {
+	case criticalFailure:
+		status = "unhealthy"
 		statusCode = http.StatusServiceUnavailable
+	case degraded:
+		status = "degraded"
 	}
 
 	h.writeStatus(w, statusCode, ReadinessResponse{
 		Status: status,
-		Checks: checks,
+		Checks: results,
 	})
 }
 
-func (h *Handler) runHealthChecks(ctx context.Context) []HealthCheck {
-	var wg sync.WaitGroup
-	checks := make([]HealthCheck, 2)
-
-	wg.Add(2)
-
-	go func() {
-		defer wg.Done()
-		checks[0] = h.checkDatabase(ctx)
-	}()
-
-	go func() {
-		defer wg.Done()
-		checks[1] = h.checkRedis(ctx)
-	}()
-
-	wg.Wait()
-	return checks
-}
-
-func (h *Handler) checkDatabase(ctx context.Context) HealthCheck {
-	check := HealthCheck{
-		Name:    "database",
-		Healthy: true,
-	}
-
-	if h.db == nil {
-		check.Healthy = false
-		check.Message = "database checker not configured"
-		return check
-	}
-
-	start := time.Now()
-	err := h.db.Ping(ctx)
-	check.Latency = time.Since(start).String()
-
-	if err != nil {
-		check.Healthy = false
-		check.Message = "ping failed"
-	}
-
-	return check
-}
-
-func (h *Handler) checkRedis(ctx context.Context) HealthCheck {
-	check := HealthCheck{
-		Name:    "redis",
-		Healthy: true,
-	}
-
-	if h.redis == nil {
-		check.Healthy = false
-		check.Message = "redis checker not configured"
-		return check
-	}
-
-	start := time.Now()
-	err := h.redis.Ping(ctx)
-	check.Latency = time.Since(start).String()
-
-	if err != nil {
-		check.Healthy = false
-		check.Message = "ping failed"
-	}
-
-	return check
+// Detail exposes every registered checker's full cached result,
+// including history, last error, and consecutive-failure count, for
+// operators debugging a degraded or flapping dependency.
+func (h *Handler) Detail(w http.ResponseWriter, r *http.Request) {
+	h.writeStatus(w, http.StatusOK, DetailResponse{
+		Checks: h.registry.Results(),
+	})
 }
 
 func (h *Handler) SetReady(ready bool) {
@@ -184,12 +145,9 @@ type StatusResponse struct {
 
 type ReadinessResponse struct {
 	Status string        `json:"status"`
-	Checks []HealthCheck `json:"checks"`
+	Checks []CheckResult `json:"checks"`
 }
 
-type HealthCheck struct {
-	Name    string `json:"name"`
-	Healthy bool   `json:"healthy"`
-	Latency string `json:"latency,omitempty"`
-	Message string `json:"message,omitempty"`
+type DetailResponse struct {
+	Checks []CheckResult `json:"checks"`
 }
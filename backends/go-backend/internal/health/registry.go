@@ -0,0 +1,209 @@
+// AngelaMos | 2026
+// registry.go
+
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core/logging"
+)
+
+const (
+	defaultTimeout  = 2 * time.Second
+	defaultCacheTTL = 5 * time.Second
+	historyLimit    = 10
+)
+
+// Checker is anything a registry entry can probe for liveness.
+type Checker interface {
+	Ping(ctx context.Context) error
+}
+
+// Option configures a registered checker. The zero-value checker is
+// critical with a 2s timeout and a 5s cache TTL.
+type Option func(*checkEntry)
+
+// WithTimeout bounds how long a single probe of this checker may run
+// before it's treated as a failure.
+func WithTimeout(timeout time.Duration) Option {
+	return func(e *checkEntry) { e.timeout = timeout }
+}
+
+// WithCritical marks whether this checker's failure should fail readiness
+// outright (503) versus merely degrade it (200, status "degraded").
+// Checkers are critical by default.
+func WithCritical(critical bool) Option {
+	return func(e *checkEntry) { e.critical = critical }
+}
+
+// WithCacheTTL sets how often the background refresher re-probes this
+// checker. Readiness and detail reads always serve the cached result
+// rather than probing live, so kubelet's 1 Hz polling never reaches the
+// checker directly.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(e *checkEntry) { e.cacheTTL = ttl }
+}
+
+// HistoryEntry is one past probe outcome, oldest first, capped at
+// historyLimit entries per checker.
+type HistoryEntry struct {
+	Healthy bool      `json:"healthy"`
+	At      time.Time `json:"at"`
+}
+
+// CheckResult is the cached, point-in-time outcome of one registered
+// checker, as served to both /readyz and /health/detail.
+type CheckResult struct {
+	Name                string         `json:"name"`
+	Critical            bool           `json:"critical"`
+	Healthy             bool           `json:"healthy"`
+	Latency             string         `json:"latency,omitempty"`
+	Message             string         `json:"message,omitempty"`
+	LastError           string         `json:"last_error,omitempty"`
+	LastCheckedAt       time.Time      `json:"last_checked_at"`
+	ConsecutiveFailures int            `json:"consecutive_failures"`
+	History             []HistoryEntry `json:"history,omitempty"`
+}
+
+type checkEntry struct {
+	name    string
+	checker Checker
+
+	timeout  time.Duration
+	critical bool
+	cacheTTL time.Duration
+
+	mu      sync.RWMutex
+	result  CheckResult
+	history []HistoryEntry
+}
+
+// Registry holds a set of named health checkers and refreshes their
+// results in the background so probe endpoints never block on a live
+// downstream call. Callers register checkers at startup with Register,
+// then call Start once the registry is fully populated.
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[string]*checkEntry
+	order   []string
+}
+
+// NewRegistry returns an empty Registry. Register checkers before calling
+// Start.
+func NewRegistry() *Registry {
+	return &Registry{
+		entries: make(map[string]*checkEntry),
+	}
+}
+
+// Register adds a named checker to the registry. It probes once
+// synchronously so the first /readyz or /health/detail call after
+// startup already has a result, rather than reporting unhealthy until
+// the first background refresh.
+func (reg *Registry) Register(name string, checker Checker, opts ...Option) {
+	entry := &checkEntry{
+		name:     name,
+		checker:  checker,
+		timeout:  defaultTimeout,
+		critical: true,
+		cacheTTL: defaultCacheTTL,
+	}
+	for _, opt := range opts {
+		opt(entry)
+	}
+	entry.result = CheckResult{Name: name, Critical: entry.critical, Healthy: true}
+
+	reg.mu.Lock()
+	reg.entries[name] = entry
+	reg.order = append(reg.order, name)
+	reg.mu.Unlock()
+
+	reg.runCheck(context.Background(), entry)
+}
+
+// Start launches one background refresher goroutine per registered
+// checker, each probing on its own cacheTTL interval until ctx is
+// canceled.
+func (reg *Registry) Start(ctx context.Context) {
+	reg.mu.RLock()
+	entries := make([]*checkEntry, 0, len(reg.entries))
+	for _, entry := range reg.entries {
+		entries = append(entries, entry)
+	}
+	reg.mu.RUnlock()
+
+	for _, entry := range entries {
+		go reg.refreshLoop(ctx, entry)
+	}
+}
+
+func (reg *Registry) refreshLoop(ctx context.Context, entry *checkEntry) {
+	ticker := time.NewTicker(entry.cacheTTL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reg.runCheck(ctx, entry)
+		}
+	}
+}
+
+func (reg *Registry) runCheck(ctx context.Context, entry *checkEntry) {
+	checkCtx, cancel := context.WithTimeout(ctx, entry.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := entry.checker.Ping(checkCtx)
+	latency := time.Since(start)
+	now := time.Now()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	entry.result.Latency = latency.String()
+	entry.result.LastCheckedAt = now
+	if err != nil {
+		entry.result.Healthy = false
+		entry.result.Message = "ping failed"
+		entry.result.LastError = err.Error()
+		entry.result.ConsecutiveFailures++
+		logging.FromContext(ctx).Warn("health check failed",
+			"checker", entry.name,
+			"critical", entry.critical,
+			"consecutive_failures", entry.result.ConsecutiveFailures,
+			"error", err,
+		)
+	} else {
+		entry.result.Healthy = true
+		entry.result.Message = ""
+		entry.result.ConsecutiveFailures = 0
+	}
+
+	entry.history = append(entry.history, HistoryEntry{Healthy: entry.result.Healthy, At: now})
+	if len(entry.history) > historyLimit {
+		entry.history = entry.history[len(entry.history)-historyLimit:]
+	}
+	entry.result.History = append([]HistoryEntry(nil), entry.history...)
+}
+
+// Results returns the cached result of every registered checker, in
+// registration order.
+func (reg *Registry) Results() []CheckResult {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	results := make([]CheckResult, 0, len(reg.order))
+	for _, name := range reg.order {
+		entry := reg.entries[name]
+		entry.mu.RLock()
+		results = append(results, entry.result)
+		entry.mu.RUnlock()
+	}
+	return results
+}
@@ -6,10 +6,16 @@ package middleware
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
 
 	"github.com/carterperez-dev/templates/go-backend/internal/core"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/logging"
 )
 
 const (
@@ -17,6 +23,10 @@ const (
 	UserRoleKey contextKey = "user_role"
 	UserTierKey contextKey = "user_tier"
 	ClaimsKey   contextKey = "jwt_claims"
+	ScopesKey   contextKey = "api_token_scopes"
+
+	sessionKeyFmt = "session:%s:last_seen"
+	reauthKeyFmt  = "session:%s:reauth_at"
 )
 
 type TokenVerifier interface {
@@ -26,14 +36,87 @@ type TokenVerifier interface {
 	) (*AccessTokenClaims, error)
 }
 
+// APITokenVerifier is implemented by auth.APITokenService. Authenticator
+// calls it instead of TokenVerifier for any bearer value recognized as a
+// personal access token, so a PAT never has to pass through the JWT
+// signature-check path at all.
+type APITokenVerifier interface {
+	IsAPIToken(bearer string) bool
+	VerifyAPIToken(ctx context.Context, bearer string) (*APITokenClaims, error)
+}
+
+// APITokenClaims is what an APITokenVerifier resolves a valid bearer
+// value to: the owning user and the scopes it was issued with. Unlike
+// AccessTokenClaims, there's no session or token-version concept — a PAT
+// is revoked by deleting/marking its own row, not by bumping a shared
+// counter.
+type APITokenClaims struct {
+	UserID string
+	Scopes []string
+}
+
+// TokenVersionValidator is implemented by auth.Service. Authenticator
+// calls it after verifying the token's signature to catch a token minted
+// before the user's most recent revoke-tokens action — something the
+// signature check alone can't see, since the claim was already correct
+// when the token was signed.
+type TokenVersionValidator interface {
+	ValidateTokenVersion(
+		ctx context.Context,
+		userID string,
+		tokenVersion int,
+	) error
+}
+
 type AccessTokenClaims struct {
 	UserID       string
 	Role         string
 	Tier         string
 	TokenVersion int
+	SessionID    string
+	ActorID      string
+	ReauthAt     time.Time
+}
+
+// SessionActivityKey is the Redis key CreateAccessToken's session (the
+// refresh-token family id) is tracked under, shared between the auth
+// service (which seeds it at login/refresh) and Authenticator (which
+// slides its TTL forward on every authenticated request).
+func SessionActivityKey(sessionID string) string {
+	return fmt.Sprintf(sessionKeyFmt, sessionID)
+}
+
+// ReauthGrantKey is the Redis key a step-up reauthentication grant is
+// recorded under, keyed by the same refresh-token family id as
+// SessionActivityKey so the grant survives token refresh chains within
+// the family and is dropped alongside the session on logout.
+func ReauthGrantKey(sessionID string) string {
+	return fmt.Sprintf(reauthKeyFmt, sessionID)
 }
 
-func Authenticator(verifier TokenVerifier) func(http.Handler) http.Handler {
+// Authenticator verifies the bearer access token and, when rdb and
+// idleTimeout are both set, enforces a sliding idle timeout on top of it:
+// each authenticated request slides the session's Redis TTL forward, and
+// a session whose key has expired (no request within idleTimeout) is
+// rejected even though the access token itself hasn't expired yet. This
+// composes with the refresh-token subsystem's own family-level idle
+// check, which catches the case where the access token has already
+// expired and the client tries to use a stale refresh token.
+//
+// When apiTokens is non-nil, a bearer value it recognizes as a personal
+// access token (via IsAPIToken) is verified through it instead of
+// verifier, bypassing the JWT path entirely — a PAT has no session or
+// token-version concept, so the idle-timeout and versions checks below
+// don't apply to it. Likewise, a client_credentials token (role "service")
+// skips the token-version check: its UserID names an OAuth client, not a
+// users row, so there's no version counter to validate against.
+func Authenticator(
+	verifier TokenVerifier,
+	rdb *redis.Client,
+	idleTimeout time.Duration,
+	versions TokenVersionValidator,
+	apiTokens APITokenVerifier,
+) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			token := ExtractToken(r)
@@ -46,23 +129,91 @@ func Authenticator(verifier TokenVerifier) func(http.Handler) http.Handler {
 				return
 			}
 
+			if apiTokens != nil && apiTokens.IsAPIToken(token) {
+				authenticateAPIToken(w, r, next, apiTokens, token)
+				return
+			}
+
 			claims, err := verifier.VerifyAccessToken(r.Context(), token)
 			if err != nil {
 				handleAuthError(w, err)
 				return
 			}
 
+			// A client_credentials token names an OAuth client (role
+			// "service"), not a row in the users table, so there's no
+			// token-version counter to check against — skip it rather
+			// than failing every request a registered OAuth client makes.
+			if versions != nil && claims.Role != "service" {
+				if err := versions.ValidateTokenVersion(r.Context(), claims.UserID, claims.TokenVersion); err != nil {
+					handleAuthError(w, err)
+					return
+				}
+			}
+
+			if rdb != nil && idleTimeout > 0 && claims.SessionID != "" {
+				active, activeErr := touchSessionActivity(r.Context(), rdb, claims.SessionID, idleTimeout)
+				if activeErr != nil {
+					slog.Warn("session activity check failed", "error", activeErr)
+				} else if !active {
+					core.JSONError(w, core.TokenExpiredError())
+					return
+				}
+			}
+
 			ctx := r.Context()
 			ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
 			ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
 			ctx = context.WithValue(ctx, UserTierKey, claims.Tier)
 			ctx = context.WithValue(ctx, ClaimsKey, claims)
+			ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("user_id", claims.UserID))
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// authenticateAPIToken resolves token through apiTokens and, on success,
+// continues the chain with UserIDKey and ScopesKey set on the request
+// context. RequireScope reads ScopesKey to enforce the token's grant;
+// role/tier aren't set since a PAT doesn't carry either.
+func authenticateAPIToken(
+	w http.ResponseWriter,
+	r *http.Request,
+	next http.Handler,
+	apiTokens APITokenVerifier,
+	token string,
+) {
+	claims, err := apiTokens.VerifyAPIToken(r.Context(), token)
+	if err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
+	ctx = context.WithValue(ctx, ScopesKey, claims.Scopes)
+	ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("user_id", claims.UserID))
+
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// touchSessionActivity slides the session's idle-timeout TTL forward and
+// reports whether the key still existed beforehand. EXPIRE both refreshes
+// the TTL and answers the existence question in a single round trip.
+func touchSessionActivity(
+	ctx context.Context,
+	rdb *redis.Client,
+	sessionID string,
+	idleTimeout time.Duration,
+) (bool, error) {
+	renewed, err := rdb.Expire(ctx, SessionActivityKey(sessionID), idleTimeout).Result()
+	if err != nil {
+		return false, fmt.Errorf("touch session activity: %w", err)
+	}
+	return renewed, nil
+}
+
 func OptionalAuth(verifier TokenVerifier) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -76,6 +227,7 @@ func OptionalAuth(verifier TokenVerifier) func(http.Handler) http.Handler {
 					ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
 					ctx = context.WithValue(ctx, UserTierKey, claims.Tier)
 					ctx = context.WithValue(ctx, ClaimsKey, claims)
+					ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("user_id", claims.UserID))
 					r = r.WithContext(ctx)
 				}
 			}
@@ -120,6 +272,51 @@ func RequireAdmin(next http.Handler) http.Handler {
 	return RequireRole("admin")(next)
 }
 
+// RequireReauth rejects a request whose access token has no "reauth_at"
+// claim, or one older than window, with a 401 naming the reauthentication
+// requirement explicitly — distinct from a plain missing-auth 401 — so a
+// client can tell "log in" apart from "re-enter your password" and prompt
+// accordingly. It's meant to sit in front of sensitive operations like
+// ChangePassword, RevokeSession, and DisableTOTP, after authenticator.
+//
+// A request authenticated by personal access token rather than JWT has no
+// claims in context at all (see authenticateAPIToken), so it can never
+// carry a "reauth_at" claim to begin with — a PAT has no session or
+// reauth concept (APITokenClaims). allowAPIToken controls what happens
+// then: true lets it through unchecked, appropriate only where the
+// handler itself re-verifies an equivalent secret (ChangePassword's
+// current_password field); false rejects it the same as a stale or
+// missing reauth claim, for routes — RevokeSession, DisableTOTP — that
+// have no other check a step-up requirement could be standing in for.
+func RequireReauth(window time.Duration, allowAPIToken bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := GetClaims(r.Context())
+			if claims == nil {
+				if allowAPIToken && IsAuthenticated(r.Context()) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				core.JSONError(
+					w,
+					core.UnauthorizedError("authentication required"),
+				)
+				return
+			}
+
+			if claims.ReauthAt.IsZero() || time.Since(claims.ReauthAt) > window {
+				core.JSONError(
+					w,
+					core.UnauthorizedError("this action requires reauthentication"),
+				)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 func ExtractToken(r *http.Request) string {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
@@ -173,6 +370,16 @@ func GetUserTier(ctx context.Context) string {
 	return ""
 }
 
+// GetScopes returns the calling PAT's granted scopes, or nil for a
+// session authenticated by JWT (which carries no scopes restriction at
+// all) or for an unauthenticated context.
+func GetScopes(ctx context.Context) []string {
+	if scopes, ok := ctx.Value(ScopesKey).([]string); ok {
+		return scopes
+	}
+	return nil
+}
+
 func GetClaims(ctx context.Context) *AccessTokenClaims {
 	if claims, ok := ctx.Value(ClaimsKey).(*AccessTokenClaims); ok {
 		return claims
@@ -187,3 +394,18 @@ func IsAuthenticated(ctx context.Context) bool {
 func IsAdmin(ctx context.Context) bool {
 	return GetUserRole(ctx) == "admin"
 }
+
+// GetImpersonatorID returns the admin ID from an impersonation token's
+// "act" claim, or "" for a user's own session.
+func GetImpersonatorID(ctx context.Context) string {
+	if claims := GetClaims(ctx); claims != nil {
+		return claims.ActorID
+	}
+	return ""
+}
+
+// IsImpersonating reports whether the current request is running under an
+// admin's impersonation token rather than the target user's own session.
+func IsImpersonating(ctx context.Context) bool {
+	return GetImpersonatorID(ctx) != ""
+}
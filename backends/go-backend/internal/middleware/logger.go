@@ -0,0 +1,61 @@
+// AngelaMos | 2026
+// logger.go
+
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core/logging"
+)
+
+// Logger attaches a per-request *slog.Logger to the request context via
+// logging.NewContext, pre-tagged with request_id, remote_ip and route, so
+// every downstream handler and repository pulls a consistently-tagged
+// logger with logging.FromContext instead of assembling its own
+// attributes. user_id isn't known yet at this point in the chain —
+// Authenticator layers it onto the context logger once it's resolved the
+// caller's identity. It also emits one access-log line per request,
+// levelled by the response status.
+//
+// base should already be slog.SetDefault'd by the caller; Logger just
+// adds the per-request tags on top of it.
+func Logger(base *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := routePattern(r)
+
+			logger := base.With(
+				"request_id", chimiddleware.GetReqID(r.Context()),
+				"remote_ip", r.RemoteAddr,
+				"route", route,
+			)
+			ctx := logging.NewContext(r.Context(), logger)
+
+			start := time.Now()
+			wrapped := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(wrapped, r.WithContext(ctx))
+
+			status := wrapped.Status()
+			args := []any{
+				"method", r.Method,
+				"status", status,
+				"duration", time.Since(start),
+			}
+
+			switch {
+			case status >= http.StatusInternalServerError:
+				logger.Error("request completed", args...)
+			case status >= http.StatusBadRequest:
+				logger.Warn("request completed", args...)
+			default:
+				logger.Info("request completed", args...)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// AngelaMos | 2026
+// metrics.go
+
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// MetricsRecorder is implemented by core.Metrics. Defined here rather than
+// imported directly so this middleware doesn't force every caller of the
+// middleware package to pull in the Prometheus client.
+type MetricsRecorder interface {
+	ObserveHTTPRequest(route, method, statusClass string, duration time.Duration)
+	IncInFlight()
+	DecInFlight()
+}
+
+// Metrics records per-route request duration, status class and in-flight
+// count against recorder. It is a no-op wrapper when recorder is nil, so
+// deployments with metrics disabled pay nothing beyond the nil check.
+func Metrics(recorder MetricsRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if recorder == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorder.IncInFlight()
+			defer recorder.DecInFlight()
+
+			start := time.Now()
+			wrapped := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(wrapped, r)
+
+			route := routePattern(r)
+			statusClass := strconv.Itoa(wrapped.Status()/100) + "xx"
+
+			recorder.ObserveHTTPRequest(route, r.Method, statusClass, time.Since(start))
+		})
+	}
+}
+
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return r.URL.Path
+}
@@ -0,0 +1,53 @@
+// AngelaMos | 2026
+// otel_metrics.go
+
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// OtelMetrics records the `http.server.request.duration` histogram per the
+// current OTel HTTP semantic conventions, tagged with the chi route
+// pattern (e.g. "/v1/users/{id}") rather than the raw request path, so a
+// user ID or other path parameter never becomes an unbounded label value.
+// meter is nil-safe — a nil meter disables the middleware, matching how
+// Metrics and Tracing handle their nil recorders.
+func OtelMetrics(meter metric.Meter) func(http.Handler) http.Handler {
+	if meter == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	duration, err := meter.Float64Histogram(
+		"http.server.request.duration",
+		metric.WithDescription("Duration of HTTP server requests."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			wrapped := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(wrapped, r)
+
+			route := routePattern(r)
+			duration.Record(r.Context(), time.Since(start).Seconds(),
+				metric.WithAttributes(
+					attribute.String("http.route", route),
+					attribute.String("http.request.method", r.Method),
+					attribute.Int("http.response.status_code", wrapped.Status()),
+				),
+			)
+		})
+	}
+}
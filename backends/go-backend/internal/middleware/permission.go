@@ -0,0 +1,57 @@
+// AngelaMos | 2026
+// permission.go
+
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+// PermissionChecker is implemented by user.Service. It's defined here,
+// the same way RequirePolicy takes a *policy.Registry, so this package
+// doesn't need to import internal/user (which already imports
+// internal/middleware for GetUserID) just to call RequirePermission.
+type PermissionChecker interface {
+	CheckPermission(ctx context.Context, userID, resource, action string) (bool, error)
+}
+
+// RequirePermission restricts a route to callers whose ACL grants allow
+// action on resource, per checker's most-specific-pattern-wins resolution.
+// Unlike RequireRole's fixed whitelist, this defers every decision to the
+// checker, so access can be tuned per user without touching route code.
+func RequirePermission(
+	checker PermissionChecker,
+	resource, action string,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := GetUserID(r.Context())
+			if userID == "" {
+				core.JSONError(w, core.UnauthorizedError("authentication required"))
+				return
+			}
+
+			allowed, err := checker.CheckPermission(r.Context(), userID, resource, action)
+			if err != nil {
+				slog.Error("permission check failed",
+					"error", err,
+					"resource", resource,
+					"action", action,
+				)
+				core.JSONError(w, core.ForbiddenError("permission check failed"))
+				return
+			}
+
+			if !allowed {
+				core.JSONError(w, core.ForbiddenError("insufficient permissions"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// AngelaMos | 2026
+// policy.go
+
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/policy"
+)
+
+// RequirePolicy replaces a RequireRole whitelist with a named expression
+// evaluated from registry, e.g. `user.role == "admin" || user.tier in
+// ["pro","enterprise"]`. Chi URL params (the route's {id} etc.) are
+// exposed to the expression as `resource.*`, so a rule can reference the
+// resource being accessed without the handler wiring anything up itself.
+func RequirePolicy(registry *policy.Registry, name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			env := policy.Env{
+				User: policy.UserEnv{
+					ID:   GetUserID(r.Context()),
+					Role: GetUserRole(r.Context()),
+					Tier: GetUserTier(r.Context()),
+				},
+				Request: policy.RequestEnv{
+					Method:  r.Method,
+					Path:    r.URL.Path,
+					Headers: flattenHeaders(r.Header),
+				},
+				Resource: resourceParams(r),
+			}
+
+			allowed, err := registry.Evaluate(name, env)
+			if err != nil {
+				slog.Error("policy evaluation failed", "policy", name, "error", err)
+				core.JSONError(w, core.ForbiddenError("policy evaluation failed"))
+				return
+			}
+
+			if !allowed {
+				core.JSONError(w, core.ForbiddenError("insufficient permissions"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func flattenHeaders(h http.Header) map[string]string {
+	flat := make(map[string]string, len(h))
+	for key := range h {
+		flat[key] = h.Get(key)
+	}
+	return flat
+}
+
+func resourceParams(r *http.Request) map[string]any {
+	routeCtx := chi.RouteContext(r.Context())
+	if routeCtx == nil {
+		return nil
+	}
+
+	params := make(map[string]any, len(routeCtx.URLParams.Keys))
+	for i, key := range routeCtx.URLParams.Keys {
+		params[key] = routeCtx.URLParams.Values[i]
+	}
+	return params
+}
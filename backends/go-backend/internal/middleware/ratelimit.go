@@ -10,6 +10,7 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,14 +19,44 @@ import (
 	redis_rate "github.com/go-redis/redis_rate/v10"
 	"github.com/redis/go-redis/v9"
 	"golang.org/x/time/rate"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core/clientip"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/quota"
 )
 
 type RateLimitConfig struct {
-	Limit      redis_rate.Limit
-	KeyFunc    func(*http.Request) string
+	Limit   redis_rate.Limit
+	KeyFunc func(*http.Request) string
+	// CostFunc computes how many tokens r consumes, letting a heavy route
+	// (e.g. POST /search) cost more than a cheap one (e.g. GET /healthz).
+	// Nil means every request costs 1, same as before CostFunc existed. A
+	// request whose cost is <= 0 bypasses the limiter entirely, so a route
+	// can be made free without a separate BypassFunc entry.
+	CostFunc   func(*http.Request) int
 	FailOpen   bool
 	BypassFunc func(*http.Request) bool
-	OnLimited  func(http.ResponseWriter, *http.Request, *redis_rate.Result)
+	// OnLimited handles a denied request. Nil (the default) installs
+	// defaultOnLimited, which logs a structured event naming the
+	// normalized endpoint and caller tier, then writes the standard 429
+	// response, so a deployment gets log-based visibility into who's
+	// getting limited without configuring anything itself.
+	OnLimited func(http.ResponseWriter, *http.Request, *redis_rate.Result)
+	// Stats optionally samples every allow outcome into Redis rolling
+	// buckets and a Count-Min sketch of denied keys, for
+	// RateLimitStats.Summary. Nil (the default) disables sampling.
+	Stats *RateLimitStats
+	// Recorder optionally records ratelimit_requests_total/
+	// ratelimit_denied_total for every allow outcome. Nil (the default)
+	// disables recording.
+	Recorder RateLimitRecorder
+}
+
+// RateLimitRecorder is implemented by core.Metrics. Defined here, the same
+// way Metrics defines MetricsRecorder, so attaching a Recorder to
+// RateLimitConfig doesn't force a caller that doesn't want Prometheus
+// recording to pull in the client.
+type RateLimitRecorder interface {
+	RecordRateLimit(endpoint, tier, outcome string)
 }
 
 type RateLimiter struct {
@@ -38,6 +69,9 @@ func NewRateLimiter(rdb *redis.Client, cfg RateLimitConfig) *RateLimiter {
 	if cfg.KeyFunc == nil {
 		cfg.KeyFunc = KeyByIP
 	}
+	if cfg.OnLimited == nil {
+		cfg.OnLimited = defaultOnLimited
+	}
 
 	return &RateLimiter{
 		limiter:  redis_rate.NewLimiter(rdb),
@@ -53,8 +87,17 @@ func (rl *RateLimiter) Handler(next http.Handler) http.Handler {
 			return
 		}
 
+		cost := 1
+		if rl.config.CostFunc != nil {
+			cost = rl.config.CostFunc(r)
+			if cost <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
 		key := rl.config.KeyFunc(r)
-		res, err := rl.allow(r.Context(), key)
+		res, err := rl.allow(r.Context(), key, cost)
 		if err != nil {
 			if rl.config.FailOpen {
 				slog.Warn("rate limiter error, failing open",
@@ -70,12 +113,19 @@ func (rl *RateLimiter) Handler(next http.Handler) http.Handler {
 
 		setRateLimitHeaders(w, res, rl.config.Limit)
 
+		endpoint := normalizeEndpoint(r.URL.Path)
+		tier := tierOrAnonymous(r.Context())
+		outcome := outcomeAllowed
 		if res.Allowed == 0 {
-			if rl.config.OnLimited != nil {
-				rl.config.OnLimited(w, r, res)
-				return
-			}
-			writeRateLimitExceeded(w, res)
+			outcome = outcomeDenied
+		}
+		rl.config.Stats.observe(r.Context(), endpoint, tier, outcome, key)
+		if rl.config.Recorder != nil {
+			rl.config.Recorder.RecordRateLimit(endpoint, tier, outcome)
+		}
+
+		if res.Allowed == 0 {
+			rl.config.OnLimited(w, r, res)
 			return
 		}
 
@@ -83,36 +133,140 @@ func (rl *RateLimiter) Handler(next http.Handler) http.Handler {
 	})
 }
 
+// defaultOnLimited is RateLimitConfig.OnLimited's default: log a structured
+// event identifying what got limited, then write the standard 429 response.
+func defaultOnLimited(w http.ResponseWriter, r *http.Request, res *redis_rate.Result) {
+	slog.Warn("rate limit exceeded",
+		"endpoint", normalizeEndpoint(r.URL.Path),
+		"tier", tierOrAnonymous(r.Context()),
+		"retry_after", res.RetryAfter,
+	)
+	writeRateLimitExceeded(w, res)
+}
+
+// tierOrAnonymous is GetUserTier, defaulting to "anonymous" so stats and
+// logs never carry an empty tier label for an unauthenticated caller.
+func tierOrAnonymous(ctx context.Context) string {
+	if tier := GetUserTier(ctx); tier != "" {
+		return tier
+	}
+	return "anonymous"
+}
+
 func (rl *RateLimiter) allow(
 	ctx context.Context,
 	key string,
+	cost int,
 ) (*redis_rate.Result, error) {
-	res, err := rl.limiter.Allow(ctx, key, rl.config.Limit)
+	res, err := rl.limiter.AllowN(ctx, key, rl.config.Limit, cost)
 	if err != nil {
-		return rl.fallback.allow(key, rl.config.Limit)
+		return rl.fallback.allowN(key, rl.config.Limit, cost)
 	}
 	return res, nil
 }
 
+// AllowExplicit charges cost tokens against key directly, for work that
+// wants to share a caller's bucket outside the HTTP request path (e.g. a
+// queued job billed against the user who enqueued it).
+func (rl *RateLimiter) AllowExplicit(
+	ctx context.Context,
+	key string,
+	cost int,
+) (*redis_rate.Result, error) {
+	return rl.allow(ctx, key, cost)
+}
+
+// CostByMethod returns a CostFunc that charges cost[r.Method], falling back
+// to 1 for any method not listed.
+func CostByMethod(cost map[string]int) func(*http.Request) int {
+	return func(r *http.Request) int {
+		if c, ok := cost[r.Method]; ok {
+			return c
+		}
+		return 1
+	}
+}
+
+// RouteCost pairs a regex matched against the request path with the cost
+// charged when it matches, for CostByRouteRegex.
+type RouteCost struct {
+	Pattern string
+	Cost    int
+}
+
+// CostByRouteRegex returns a CostFunc that charges the cost of the first
+// rule in rules whose Pattern matches r.URL.Path, falling back to 1 when
+// none match. rules is a slice rather than a map because match order
+// matters: list more specific routes before catch-alls.
+func CostByRouteRegex(rules ...RouteCost) func(*http.Request) int {
+	compiled := make([]struct {
+		pattern *regexp.Regexp
+		cost    int
+	}, len(rules))
+	for i, rule := range rules {
+		compiled[i].pattern = regexp.MustCompile(rule.Pattern)
+		compiled[i].cost = rule.Cost
+	}
+
+	return func(r *http.Request) int {
+		for _, rule := range compiled {
+			if rule.pattern.MatchString(r.URL.Path) {
+				return rule.cost
+			}
+		}
+		return 1
+	}
+}
+
+// defaultIPResolver trusts no proxies at all, so KeyByIP always keys on
+// RemoteAddr: a deployment that forwards through a reverse proxy and wants
+// its forwarding headers honored must opt in with KeyByIPWith instead.
+// Building with an empty clientip.Config can't fail (there are no CIDRs to
+// parse), so the error is safe to discard.
+var defaultIPResolver = func() *clientip.Resolver {
+	resolver, _ := clientip.NewResolver(clientip.Config{})
+	return resolver
+}()
+
+// KeyByIP keys on the caller's address per defaultIPResolver: since it
+// trusts no proxies, that's always RemoteAddr, never an attacker-supplied
+// X-Forwarded-For/X-Real-IP. Use KeyByIPWith to key on the real client
+// address behind a deployment's actual trusted proxies.
 func KeyByIP(r *http.Request) string {
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		ips := strings.Split(xff, ",")
-		ip := strings.TrimSpace(ips[len(ips)-1])
-		return "ratelimit:ip:" + ip
+	return KeyByIPWith(defaultIPResolver)(r)
+}
+
+// KeyByIPWith returns a KeyFunc that keys on the address resolver resolves
+// for r, bucketing IPv6 addresses to their /64 so rotating within a single
+// delegated prefix doesn't reset a client's bucket. Pass the same resolver
+// wired into cmd/api/main.go (internal/core/clientip) to honor the
+// deployment's configured trusted proxies.
+func KeyByIPWith(resolver *clientip.Resolver) func(*http.Request) string {
+	return func(r *http.Request) string {
+		return "ratelimit:ip:" + bucketIP(resolver.ClientIP(r))
 	}
+}
 
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return "ratelimit:ip:" + xri
+// bucketIP returns ip unchanged for IPv4 (and anything unparsable); for
+// IPv6 it truncates to the address's /64, the smallest block an ISP
+// typically delegates to one customer.
+func bucketIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
 	}
 
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		ip = r.RemoteAddr
+	if parsed.To4() != nil {
+		return parsed.String()
 	}
 
-	return "ratelimit:ip:" + ip
+	return parsed.Mask(net.CIDRMask(64, 128)).String() + "/64"
 }
 
+// KeyByUser keys on the authenticated caller's user ID, falling back to
+// KeyByIP (no trusted proxies) for an anonymous request. Behind a reverse
+// proxy, use KeyByUserWith so anonymous callers still key on their real
+// address instead of all collapsing onto the proxy's RemoteAddr.
 func KeyByUser(r *http.Request) string {
 	if userID := GetUserID(r.Context()); userID != "" {
 		return "ratelimit:user:" + userID
@@ -120,12 +274,34 @@ func KeyByUser(r *http.Request) string {
 	return KeyByIP(r)
 }
 
+// KeyByUserWith is KeyByUser, but its anonymous-caller fallback uses
+// resolver instead of defaultIPResolver, so it still tells apart anonymous
+// callers behind a deployment's configured trusted proxies.
+func KeyByUserWith(resolver *clientip.Resolver) func(*http.Request) string {
+	return func(r *http.Request) string {
+		if userID := GetUserID(r.Context()); userID != "" {
+			return "ratelimit:user:" + userID
+		}
+		return KeyByIPWith(resolver)(r)
+	}
+}
+
 func KeyByUserAndEndpoint(r *http.Request) string {
 	userKey := KeyByUser(r)
 	endpoint := normalizeEndpoint(r.URL.Path)
 	return fmt.Sprintf("%s:endpoint:%s", userKey, endpoint)
 }
 
+// KeyByUserAndEndpointWith is KeyByUserAndEndpoint, but keys anonymous
+// callers via resolver the same way KeyByUserWith does.
+func KeyByUserAndEndpointWith(resolver *clientip.Resolver) func(*http.Request) string {
+	return func(r *http.Request) string {
+		userKey := KeyByUserWith(resolver)(r)
+		endpoint := normalizeEndpoint(r.URL.Path)
+		return fmt.Sprintf("%s:endpoint:%s", userKey, endpoint)
+	}
+}
+
 func normalizeEndpoint(path string) string {
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 	normalized := make([]string, 0, len(parts))
@@ -157,6 +333,10 @@ func isNumeric(s string) bool {
 	return len(s) > 0
 }
 
+// setRateLimitHeaders sets the standard rate-limit headers plus
+// X-RateLimit-Cost, reporting res.Allowed — the number of tokens actually
+// consumed by this request (0 when it was denied, cost when allowed) —
+// rather than the cost that was requested.
 func setRateLimitHeaders(
 	w http.ResponseWriter,
 	res *redis_rate.Result,
@@ -168,6 +348,7 @@ func setRateLimitHeaders(
 	h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
 	h.Set("X-RateLimit-Reset", strconv.FormatInt(
 		time.Now().Add(res.ResetAfter).Unix(), 10))
+	h.Set("X-RateLimit-Cost", strconv.Itoa(res.Allowed))
 
 	windowSecs := int(limit.Period.Seconds())
 	h.Set("RateLimit-Policy", fmt.Sprintf(`%d;w=%d`, limit.Rate, windowSecs))
@@ -241,9 +422,23 @@ func (l *localLimiter) cleanup() {
 func (l *localLimiter) allow(
 	key string,
 	limit redis_rate.Limit,
+) (*redis_rate.Result, error) {
+	return l.allowN(key, limit, 1)
+}
+
+// allowN mirrors redis_rate.Limiter.AllowN for the local, single-replica
+// fallback, spending n tokens from key's rate.Limiter instead of 1.
+// rate.Limiter has no AllowN that reports how long a denial must wait, so
+// this reserves n tokens with ReserveN and cancels the reservation on
+// denial, leaving the bucket exactly as AllowN would have: untouched
+// unless the cost was actually granted.
+func (l *localLimiter) allowN(
+	key string,
+	limit redis_rate.Limit,
+	n int,
 ) (*redis_rate.Result, error) {
 	ratePerSec := float64(limit.Rate) / limit.Period.Seconds()
-	now := time.Now().Unix()
+	now := time.Now()
 
 	entryI, loaded := l.limiters.Load(key)
 	if !loaded {
@@ -252,7 +447,7 @@ func (l *localLimiter) allow(
 				rate.Limit(ratePerSec),
 				limit.Burst,
 			),
-			lastAccess: now,
+			lastAccess: now.Unix(),
 		}
 		entryI, _ = l.limiters.LoadOrStore(key, newEntry)
 	}
@@ -261,25 +456,29 @@ func (l *localLimiter) allow(
 	if !ok {
 		return nil, fmt.Errorf("invalid limiter entry type")
 	}
-	entry.lastAccess = now
+	entry.lastAccess = now.Unix()
 
-	allowed := entry.limiter.Allow()
+	reservation := entry.limiter.ReserveN(now, n)
+	delay := reservation.DelayFrom(now)
+	allowed := reservation.OK() && delay == 0
+	if !allowed {
+		reservation.Cancel()
+	}
 
-	remaining := int(entry.limiter.Tokens())
+	remaining := int(entry.limiter.TokensAt(now))
 	if remaining < 0 {
 		remaining = 0
 	}
 
-	var retryAfter time.Duration
-	if !allowed {
-		retryAfter = time.Duration(float64(time.Second) / ratePerSec)
-	} else {
-		retryAfter = -1
-	}
-
+	// delay is the actual wait until n tokens are available given
+	// whatever's already in the bucket, not a flat n/rate estimate that
+	// would overstate the wait whenever some tokens are already present.
+	retryAfter := time.Duration(-1)
 	allowedInt := 0
 	if allowed {
-		allowedInt = 1
+		allowedInt = n
+	} else {
+		retryAfter = delay
 	}
 
 	return &redis_rate.Result{
@@ -350,6 +549,49 @@ func TieredRateLimiter(
 	}
 }
 
+// RateLimit builds a per-tier quota middleware backed by limiter: it reads
+// the authenticated caller's tier from context the same way
+// TieredRateLimiter does, but delegates accounting to core/quota.Limiter
+// so tier changes invalidate cleanly and hit-rate/top-offender stats are
+// available to admin.Handler. Unlike TieredRateLimiter, tiers configured
+// as Unlimited skip the Redis round trip entirely.
+func RateLimit(limiter *quota.Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := GetUserID(r.Context())
+			tier := GetUserTier(r.Context())
+			if tier == "" {
+				tier = "free"
+			}
+
+			res, err := limiter.Allow(r.Context(), userID, tier)
+			if err != nil {
+				slog.Warn("quota limiter error, failing open",
+					"error", err,
+					"user_id", userID,
+				)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if res == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Tier", tier)
+			setRateLimitHeaders(w, res, limiter.LimitFor(tier))
+
+			if res.Allowed == 0 {
+				writeRateLimitExceeded(w, res)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 func PerMinute(rate, burst int) redis_rate.Limit {
 	return redis_rate.Limit{
 		Rate:   rate,
@@ -0,0 +1,347 @@
+// AngelaMos | 2026
+// ratelimit_stats.go
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimitStats samples every RateLimiter.allow outcome into Redis rolling
+// per-minute buckets keyed by (endpoint, tier, outcome), plus a Count-Min
+// sketch of the limiter keys that got denied, so an operator can see which
+// endpoints/tiers are hot and which specific callers are driving it without
+// grepping logs. This is deliberately separate from core/quota.Limiter's
+// existing per-tier hit-rate/top-user stats: that one is an all-time
+// cumulative counter keyed only by tier with an exact, unbounded ZSET of
+// user IDs, good for "who's over quota lifetime"; this one is windowed to
+// the last few minutes and keyed by endpoint too, with a fixed-memory
+// sketch standing in for the top-offender list so an attacker rotating
+// keys every request can't grow it without bound.
+//
+// Attach via RateLimitConfig.Stats; nil (the default) disables sampling.
+type RateLimitStats struct {
+	rdb *redis.Client
+}
+
+func NewRateLimitStats(rdb *redis.Client) *RateLimitStats {
+	return &RateLimitStats{rdb: rdb}
+}
+
+const (
+	// statsBucketTTL must outlive the largest window_minutes a Summary
+	// caller is expected to pass (internal/admin's GetRateLimitStats
+	// defaults to 5) plus margin, or the oldest minutes in the window
+	// will have already expired by the time they're read.
+	statsBucketTTL     = 15 * time.Minute
+	statsSampleTimeout = 2 * time.Second
+	sketchWidth        = 2048
+	sketchDepth        = 4
+
+	// candidatesFetchLimit bounds how many denied keys summarizeOffenders
+	// considers per minute, taken by ZSET score (highest-denied-count
+	// first), so a caller rotating many distinct keys can't force an
+	// unbounded number of sketch lookups.
+	candidatesFetchLimit = 200
+)
+
+// observe records one allow() outcome: a counter bump for this minute's
+// (endpoint, tier, outcome) bucket, and, for a denial, a Count-Min sketch
+// bump for key plus a bounded candidate-key entry, so Summary can name the
+// sketch's top offenders instead of only estimating counts for keys it's
+// told about separately. It fires the Redis pipeline in its own goroutine
+// on a detached context, since it runs on every request through the rate
+// limiter and must never add a network round trip to the request path.
+// Sampling failures are logged and otherwise ignored — stats are
+// best-effort.
+func (s *RateLimitStats) observe(ctx context.Context, endpoint, tier, outcome, key string) {
+	if s == nil {
+		return
+	}
+
+	go s.record(endpoint, tier, outcome, key)
+}
+
+func (s *RateLimitStats) record(endpoint, tier, outcome, key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), statsSampleTimeout)
+	defer cancel()
+
+	minute := currentMinuteBucket()
+	pipe := s.rdb.Pipeline()
+
+	counterKey := statsCounterKey(minute, endpoint, tier, outcome)
+	pipe.Incr(ctx, counterKey)
+	pipe.Expire(ctx, counterKey, statsBucketTTL)
+
+	endpointSetKey := statsEndpointSetKey(minute)
+	pipe.SAdd(ctx, endpointSetKey, endpoint+"|"+tier)
+	pipe.Expire(ctx, endpointSetKey, statsBucketTTL)
+
+	if outcome == outcomeDenied {
+		sketchKey := statsSketchKey(minute)
+		for d := 0; d < sketchDepth; d++ {
+			field := strconv.Itoa(d) + ":" + strconv.Itoa(sketchPosition(key, d))
+			pipe.HIncrBy(ctx, sketchKey, field, 1)
+		}
+		pipe.Expire(ctx, sketchKey, statsBucketTTL)
+
+		candidatesKey := statsCandidatesKey(minute)
+		pipe.ZIncrBy(ctx, candidatesKey, 1, key)
+		pipe.Expire(ctx, candidatesKey, statsBucketTTL)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Warn("rate limit stats sample failed", "error", err)
+	}
+}
+
+const (
+	outcomeAllowed = "allowed"
+	outcomeDenied  = "denied"
+)
+
+func currentMinuteBucket() int64 {
+	return time.Now().Unix() / 60
+}
+
+func statsCounterKey(minute int64, endpoint, tier, outcome string) string {
+	return fmt.Sprintf("ratelimit:stats:count:%d:%s:%s:%s", minute, endpoint, tier, outcome)
+}
+
+func statsEndpointSetKey(minute int64) string {
+	return fmt.Sprintf("ratelimit:stats:endpoints:%d", minute)
+}
+
+func statsSketchKey(minute int64) string {
+	return fmt.Sprintf("ratelimit:stats:sketch:%d", minute)
+}
+
+func statsCandidatesKey(minute int64) string {
+	return fmt.Sprintf("ratelimit:stats:candidates:%d", minute)
+}
+
+// sketchPosition hashes key into one of sketchWidth columns for the given
+// sketch row, combining two independent hash functions as h1+i*h2 (the
+// standard way to derive sketchDepth pairwise-independent hash functions
+// from two real ones without computing sketchDepth separate hashes).
+func sketchPosition(key string, row int) int {
+	h1 := fnv.New32a()
+	h1.Write([]byte(key)) //nolint:errcheck // hash.Hash.Write never errors
+	h2 := fnv.New32()
+	h2.Write([]byte(key)) //nolint:errcheck // hash.Hash.Write never errors
+
+	combined := h1.Sum32() + uint32(row)*h2.Sum32()
+	return int(combined % sketchWidth)
+}
+
+// RateLimitEndpointStats summarizes one (endpoint, tier) pair's recent
+// traffic: requests/min over the summarized window and what fraction of
+// those requests were denied.
+type RateLimitEndpointStats struct {
+	Endpoint          string  `json:"endpoint"`
+	Tier              string  `json:"tier"`
+	RequestsPerMinute float64 `json:"requests_per_minute"`
+	DenialRatio       float64 `json:"denial_ratio"`
+}
+
+// RateLimitOffender is one entry in Summary's top-N leaderboard of limiter
+// keys denied most often in the window. EstimatedDenied is the sum of each
+// minute's Count-Min sketch estimate for key; each per-minute estimate only
+// ever over-counts on a hash collision, never under-counts, so the sum is
+// reported as an estimate rather than an exact count.
+type RateLimitOffender struct {
+	Key             string `json:"key"`
+	EstimatedDenied int64  `json:"estimated_denied"`
+}
+
+// RateLimitSummary is Summary's return value: a snapshot of recent rate
+// limiter activity for an operator to tune tiers/routes from, without
+// grepping logs.
+type RateLimitSummary struct {
+	WindowMinutes int                      `json:"window_minutes"`
+	Endpoints     []RateLimitEndpointStats `json:"endpoints"`
+	Offenders     []RateLimitOffender      `json:"offenders"`
+}
+
+// Summary reads the last windowMinutes buckets (including the current,
+// still-filling one) and returns per-endpoint request/denial rates plus
+// the topN keys the Count-Min sketch estimates were denied most often.
+func (s *RateLimitStats) Summary(
+	ctx context.Context,
+	windowMinutes, topN int,
+) (RateLimitSummary, error) {
+	if windowMinutes < 1 {
+		windowMinutes = 1
+	}
+
+	minutes := make([]int64, windowMinutes)
+	now := currentMinuteBucket()
+	for i := range minutes {
+		minutes[i] = now - int64(i)
+	}
+
+	endpoints, err := s.summarizeEndpoints(ctx, minutes, windowMinutes)
+	if err != nil {
+		return RateLimitSummary{}, fmt.Errorf("summarize rate limit endpoints: %w", err)
+	}
+
+	offenders, err := s.summarizeOffenders(ctx, minutes, topN)
+	if err != nil {
+		return RateLimitSummary{}, fmt.Errorf("summarize rate limit offenders: %w", err)
+	}
+
+	return RateLimitSummary{
+		WindowMinutes: windowMinutes,
+		Endpoints:     endpoints,
+		Offenders:     offenders,
+	}, nil
+}
+
+func (s *RateLimitStats) summarizeEndpoints(
+	ctx context.Context,
+	minutes []int64,
+	windowMinutes int,
+) ([]RateLimitEndpointStats, error) {
+	pairs := make(map[string]bool)
+	for _, minute := range minutes {
+		members, err := s.rdb.SMembers(ctx, statsEndpointSetKey(minute)).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			pairs[m] = true
+		}
+	}
+
+	stats := make([]RateLimitEndpointStats, 0, len(pairs))
+	for pair := range pairs {
+		endpoint, tier, ok := strings.Cut(pair, "|")
+		if !ok {
+			continue
+		}
+
+		var allowed, denied int64
+		for _, minute := range minutes {
+			allowed += s.getCounter(ctx, minute, endpoint, tier, outcomeAllowed)
+			denied += s.getCounter(ctx, minute, endpoint, tier, outcomeDenied)
+		}
+
+		total := allowed + denied
+		var denialRatio float64
+		if total > 0 {
+			denialRatio = float64(denied) / float64(total)
+		}
+
+		stats = append(stats, RateLimitEndpointStats{
+			Endpoint:          endpoint,
+			Tier:              tier,
+			RequestsPerMinute: float64(total) / float64(windowMinutes),
+			DenialRatio:       denialRatio,
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].RequestsPerMinute > stats[j].RequestsPerMinute
+	})
+
+	return stats, nil
+}
+
+func (s *RateLimitStats) getCounter(ctx context.Context, minute int64, endpoint, tier, outcome string) int64 {
+	val, err := s.rdb.Get(ctx, statsCounterKey(minute, endpoint, tier, outcome)).Int64()
+	if err != nil {
+		return 0
+	}
+	return val
+}
+
+func (s *RateLimitStats) summarizeOffenders(
+	ctx context.Context,
+	minutes []int64,
+	topN int,
+) ([]RateLimitOffender, error) {
+	candidates := make(map[string]bool)
+	for _, minute := range minutes {
+		members, err := s.rdb.ZRevRangeWithScores(ctx, statsCandidatesKey(minute), 0, candidatesFetchLimit-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			if key, ok := m.Member.(string); ok {
+				candidates[key] = true
+			}
+		}
+	}
+
+	offenders := make([]RateLimitOffender, 0, len(candidates))
+	for key := range candidates {
+		var total int64
+		for _, minute := range minutes {
+			count, err := s.estimateDenied(ctx, minute, key)
+			if err != nil {
+				return nil, err
+			}
+			total += count
+		}
+		offenders = append(offenders, RateLimitOffender{Key: key, EstimatedDenied: total})
+	}
+
+	sort.Slice(offenders, func(i, j int) bool {
+		return offenders[i].EstimatedDenied > offenders[j].EstimatedDenied
+	})
+
+	if topN > 0 && len(offenders) > topN {
+		offenders = offenders[:topN]
+	}
+
+	return offenders, nil
+}
+
+// estimateDenied is a Count-Min sketch query for key in minute's sketch:
+// the minimum count across its sketchDepth hashed positions, since any
+// position could be inflated by an unrelated key colliding into it, but
+// every position key actually hashed to is at least its true count. The
+// sketchDepth lookups are pipelined into one round trip rather than issued
+// one at a time.
+func (s *RateLimitStats) estimateDenied(ctx context.Context, minute int64, key string) (int64, error) {
+	sketchKey := statsSketchKey(minute)
+
+	pipe := s.rdb.Pipeline()
+	cmds := make([]*redis.StringCmd, sketchDepth)
+	for d := 0; d < sketchDepth; d++ {
+		field := strconv.Itoa(d) + ":" + strconv.Itoa(sketchPosition(key, d))
+		cmds[d] = pipe.HGet(ctx, sketchKey, field)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return 0, err
+	}
+
+	var min int64 = -1
+	for _, cmd := range cmds {
+		val, err := cmd.Int64()
+		if err != nil {
+			if err == redis.Nil {
+				val = 0
+			} else {
+				return 0, err
+			}
+		}
+		if min == -1 || val < min {
+			min = val
+		}
+	}
+
+	if min == -1 {
+		return 0, nil
+	}
+	return min, nil
+}
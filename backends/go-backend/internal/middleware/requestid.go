@@ -0,0 +1,16 @@
+// AngelaMos | 2026
+// requestid.go
+
+package middleware
+
+import (
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestID tags each request with a short, process-unique id, exposed on
+// the request context (read back with chimiddleware.GetReqID) and echoed
+// on the X-Request-Id response header, so client and server logs can be
+// correlated by that single value. It's chi's own implementation — there's
+// nothing about request-id generation specific to this service — re-
+// exported here so cmd/api only has to import the middleware package.
+var RequestID = chimiddleware.RequestID
@@ -0,0 +1,43 @@
+// AngelaMos | 2026
+// scopes.go
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+// RequireScope restricts a route to callers holding scope. A request
+// authenticated by JWT has no scopes set at all (GetScopes returns nil)
+// and passes unconditionally, since a user's own session already carries
+// their full account authority; a request authenticated by personal
+// access token must either carry scope explicitly or have been issued
+// with no scopes at all (APIToken.HasScope's same "empty means
+// unrestricted" rule, re-derived here from the claims the context
+// actually holds).
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes := GetScopes(r.Context())
+
+			if len(scopes) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			for _, s := range scopes {
+				if s == scope {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			core.JSONError(
+				w,
+				core.ForbiddenError("missing required scope: "+scope),
+			)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// AngelaMos | 2026
+// throttle.go
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/authlock"
+	"github.com/carterperez-dev/templates/go-backend/internal/core/clientip"
+)
+
+// LoginThrottle enforces store's progressive lockout in front of the login
+// and refresh endpoints: a caller already locked out is rejected before the
+// handler runs; otherwise the handler runs and a failing response (4xx or
+// 5xx) is recorded as a failure while a successful one clears the window.
+// The request body is peeked for an "email" field and restored so the
+// downstream handler's own json.Decode still sees the full body — refresh
+// requests have no email, in which case store.Key falls back to IP alone.
+// ipResolver is the same resolver used by auth.Handler, so a throttled
+// caller behind a trusted proxy is locked out by its real address rather
+// than the proxy's.
+func LoginThrottle(
+	store *authlock.Store,
+	ipResolver *clientip.Resolver,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := store.Key(peekEmail(r), ipResolver.ClientIP(r))
+
+			locked, retryAfter, err := store.Locked(r.Context(), key)
+			if err != nil {
+				slog.Warn("auth lockout check failed, failing open", "error", err)
+			} else if locked {
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+				core.JSONError(w, core.TooManyRequestsError(
+					"too many failed attempts, try again later",
+				))
+				return
+			}
+
+			wrapped := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(wrapped, r)
+
+			if wrapped.Status() >= http.StatusBadRequest {
+				if err := store.RecordFailure(r.Context(), key); err != nil {
+					slog.Warn("auth lockout record failed", "error", err)
+				}
+				return
+			}
+
+			if err := store.Clear(r.Context(), key); err != nil {
+				slog.Warn("auth lockout clear failed", "error", err)
+			}
+		})
+	}
+}
+
+// peekEmail best-effort extracts the "email" field from a JSON body
+// without consuming it for the downstream handler.
+func peekEmail(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return ""
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var payload struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSpace(payload.Email))
+}
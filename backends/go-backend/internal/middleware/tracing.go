@@ -0,0 +1,57 @@
+// AngelaMos | 2026
+// tracing.go
+
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts a server span for every incoming request, extracting any
+// propagated `traceparent`/`tracestate` headers so this request's span
+// joins an upstream trace instead of starting a new one. tracer is nil-safe
+// — a nil tracer disables the middleware entirely, matching how Metrics
+// handles a nil recorder.
+func Tracing(tracer trace.Tracer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if tracer == nil {
+			return next
+		}
+
+		propagator := otel.GetTextMapPropagator()
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			route := routePattern(r)
+
+			ctx, span := tracer.Start(ctx, r.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.method", r.Method),
+					attribute.String("http.route", route),
+					attribute.String("http.target", r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			wrapped := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(wrapped, r.WithContext(ctx))
+
+			status := wrapped.Status()
+			span.SetAttributes(attribute.Int("http.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, strconv.Itoa(status))
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// AngelaMos | 2026
+// admin_service.go
+
+package grpc
+
+import (
+	"context"
+
+	appv1 "github.com/carterperez-dev/templates/go-backend/gen/app/v1"
+	"github.com/carterperez-dev/templates/go-backend/internal/admin"
+)
+
+// AdminServer implements appv1.AdminServiceServer by delegating to
+// admin.Handler.SystemStats, the same snapshot assembly the REST
+// GetSystemStats endpoint serves.
+type AdminServer struct {
+	appv1.UnimplementedAdminServiceServer
+
+	handler *admin.Handler
+}
+
+func NewAdminServer(handler *admin.Handler) *AdminServer {
+	return &AdminServer{handler: handler}
+}
+
+func (s *AdminServer) GetSystemStats(
+	ctx context.Context,
+	_ *appv1.GetSystemStatsRequest,
+) (*appv1.SystemStats, error) {
+	if err := RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	stats := s.handler.SystemStats(ctx)
+
+	out := &appv1.SystemStats{
+		Runtime: &appv1.RuntimeStats{
+			Goroutines:    int32(stats.Runtime.NumGoroutine),
+			MemAllocBytes: stats.Runtime.MemAlloc,
+			NumGc:         stats.Runtime.NumGC,
+		},
+	}
+
+	if stats.Database.Stats != nil {
+		out.Database = &appv1.DatabaseStats{
+			OpenConnections: int32(stats.Database.Stats.OpenConnections),
+			InUse:           int32(stats.Database.Stats.InUse),
+			Idle:            int32(stats.Database.Stats.Idle),
+			WaitCount:       stats.Database.Stats.WaitCount,
+		}
+	}
+
+	if stats.Redis.Stats != nil {
+		out.Redis = &appv1.RedisStats{
+			Hits:       stats.Redis.Stats.Hits,
+			Misses:     stats.Redis.Stats.Misses,
+			Timeouts:   stats.Redis.Stats.Timeouts,
+			TotalConns: stats.Redis.Stats.TotalConns,
+			IdleConns:  stats.Redis.Stats.IdleConns,
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,104 @@
+// AngelaMos | 2026
+// auth_service.go
+
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	appv1 "github.com/carterperez-dev/templates/go-backend/gen/app/v1"
+	"github.com/carterperez-dev/templates/go-backend/internal/auth"
+)
+
+// AuthServer implements appv1.AuthServiceServer by delegating to
+// auth.Service, the same business logic the REST auth.Handler calls into.
+type AuthServer struct {
+	appv1.UnimplementedAuthServiceServer
+
+	service *auth.Service
+}
+
+func NewAuthServer(service *auth.Service) *AuthServer {
+	return &AuthServer{service: service}
+}
+
+// Login does not yet expose the REST transport's mfa_required challenge
+// step over this proto; an account with TOTP enabled gets an Unauthenticated
+// status here rather than a session, since appv1.LoginResponse has nowhere
+// to carry a challenge token.
+func (s *AuthServer) Login(ctx context.Context, req *appv1.LoginRequest) (*appv1.LoginResponse, error) {
+	result, err := s.service.Login(ctx, auth.LoginRequest{
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+	}, userAgentFrom(ctx), peerAddrFrom(ctx))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	if result.MFARequired {
+		return nil, status.Error(codes.Unauthenticated, "mfa required: complete login via the REST API")
+	}
+
+	return toLoginResponse(result.AuthResponse), nil
+}
+
+func (s *AuthServer) Refresh(ctx context.Context, req *appv1.RefreshRequest) (*appv1.LoginResponse, error) {
+	resp, err := s.service.Refresh(ctx, req.GetRefreshToken(), userAgentFrom(ctx), peerAddrFrom(ctx))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toLoginResponse(resp), nil
+}
+
+func (s *AuthServer) Logout(ctx context.Context, req *appv1.LogoutRequest) (*appv1.LogoutResponse, error) {
+	if err := s.service.Logout(ctx, req.GetRefreshToken(), GetUserID(ctx)); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &appv1.LogoutResponse{}, nil
+}
+
+func toLoginResponse(resp *auth.AuthResponse) *appv1.LoginResponse {
+	return &appv1.LoginResponse{
+		User: &appv1.UserInfo{
+			Id:    resp.User.ID,
+			Email: resp.User.Email,
+			Name:  resp.User.Name,
+			Role:  resp.User.Role,
+			Tier:  resp.User.Tier,
+		},
+		AccessToken:  resp.Tokens.AccessToken,
+		RefreshToken: resp.Tokens.RefreshToken,
+		TokenType:    resp.Tokens.TokenType,
+		ExpiresIn:    int64(resp.Tokens.ExpiresIn),
+	}
+}
+
+// userAgentFrom reads the "grpc-user-agent"/"user-agent" metadata clients
+// send by default, mirroring http.Request.UserAgent() for the REST path.
+func userAgentFrom(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get("user-agent"); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// peerAddrFrom reads the connecting peer's address, the gRPC equivalent of
+// the REST transport's extractIPAddress(r).
+func peerAddrFrom(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	return p.Addr.String()
+}
@@ -0,0 +1,39 @@
+// AngelaMos | 2026
+// codes.go
+
+package grpc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
+)
+
+// errorCodeToGRPC is the shared core.ErrorCode -> codes.Code table every
+// service implementation in this package funnels its errors through, so
+// adding a new domain error only means teaching core.CodeOf about it.
+var errorCodeToGRPC = map[core.ErrorCode]codes.Code{
+	core.ErrCodeNotFound:     codes.NotFound,
+	core.ErrCodeDuplicateKey: codes.AlreadyExists,
+	core.ErrCodeForbidden:    codes.PermissionDenied,
+	core.ErrCodeUnauthorized: codes.Unauthenticated,
+	core.ErrCodeInvalidInput: codes.InvalidArgument,
+	core.ErrCodeUnknown:      codes.Internal,
+}
+
+// toStatus converts a service-layer error into the gRPC status error its
+// code maps to. A nil err yields a nil error, so callers can pass a
+// service call's error straight through.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	code, ok := errorCodeToGRPC[core.CodeOf(err)]
+	if !ok {
+		code = codes.Internal
+	}
+
+	return status.Error(code, err.Error())
+}
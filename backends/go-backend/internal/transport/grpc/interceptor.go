@@ -0,0 +1,116 @@
+// AngelaMos | 2026
+// interceptor.go
+
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/middleware"
+)
+
+type contextKey string
+
+const (
+	userIDKey   contextKey = "user_id"
+	userRoleKey contextKey = "user_role"
+	userTierKey contextKey = "user_tier"
+)
+
+// TokenVerifier is satisfied by auth.JWTManager, matching the interface
+// middleware.Authenticator accepts for the REST transport — both transports
+// validate the same access tokens, so they share one verifier signature.
+type TokenVerifier interface {
+	VerifyAccessToken(
+		ctx context.Context,
+		token string,
+	) (*middleware.AccessTokenClaims, error)
+}
+
+// publicMethods lists the fully-qualified RPCs that don't require a bearer
+// token, e.g. login/refresh where the caller doesn't have a session yet.
+var publicMethods = map[string]bool{
+	"/app.v1.AuthService/Login":   true,
+	"/app.v1.AuthService/Refresh": true,
+}
+
+// AuthInterceptor validates the bearer token carried in the "authorization"
+// gRPC metadata key the same way middleware.Authenticator validates it for
+// REST, then populates the per-RPC context with the resolved identity so
+// service implementations can call GetUserID/GetUserRole instead of
+// re-parsing metadata themselves.
+func AuthInterceptor(verifier TokenVerifier) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		token := extractToken(ctx)
+		if token == "" {
+			return nil, status.Error(codes.Unauthenticated, "missing authorization token")
+		}
+
+		claims, err := verifier.VerifyAccessToken(ctx, token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+		}
+
+		ctx = context.WithValue(ctx, userIDKey, claims.UserID)
+		ctx = context.WithValue(ctx, userRoleKey, claims.Role)
+		ctx = context.WithValue(ctx, userTierKey, claims.Tier)
+
+		return handler(ctx, req)
+	}
+}
+
+func extractToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+
+	parts := strings.SplitN(values[0], " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+		return ""
+	}
+
+	return strings.TrimSpace(parts[1])
+}
+
+// GetUserID returns the authenticated caller's user ID, or "" if the RPC
+// wasn't authenticated (e.g. a public method).
+func GetUserID(ctx context.Context) string {
+	id, _ := ctx.Value(userIDKey).(string)
+	return id
+}
+
+// GetUserRole returns the authenticated caller's role, or "" if absent.
+func GetUserRole(ctx context.Context) string {
+	role, _ := ctx.Value(userRoleKey).(string)
+	return role
+}
+
+// RequireAdmin returns a PermissionDenied status unless the context's
+// resolved role is "admin", mirroring middleware.RequireAdmin for REST.
+func RequireAdmin(ctx context.Context) error {
+	if GetUserRole(ctx) != "admin" {
+		return status.Error(codes.PermissionDenied, "insufficient permissions")
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// AngelaMos | 2026
+// server.go
+
+package grpc
+
+import (
+	"google.golang.org/grpc"
+
+	appv1 "github.com/carterperez-dev/templates/go-backend/gen/app/v1"
+	"github.com/carterperez-dev/templates/go-backend/internal/admin"
+	"github.com/carterperez-dev/templates/go-backend/internal/auth"
+	"github.com/carterperez-dev/templates/go-backend/internal/user"
+)
+
+type Config struct {
+	AuthSvc      *auth.Service
+	UserSvc      *user.Service
+	AdminHandler *admin.Handler
+	Verifier     TokenVerifier
+}
+
+// NewServer builds a *grpc.Server with the auth interceptor installed and
+// every service registered against its REST-equivalent business logic, the
+// gRPC mirror of how cmd/api/main.go wires chi routes onto the same
+// services.
+func NewServer(cfg Config) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(AuthInterceptor(cfg.Verifier)),
+	)
+
+	appv1.RegisterAuthServiceServer(srv, NewAuthServer(cfg.AuthSvc))
+	appv1.RegisterUserServiceServer(srv, NewUserServer(cfg.UserSvc))
+	appv1.RegisterAdminServiceServer(srv, NewAdminServer(cfg.AdminHandler))
+
+	return srv
+}
@@ -0,0 +1,140 @@
+// AngelaMos | 2026
+// user_service.go
+
+package grpc
+
+import (
+	"context"
+
+	appv1 "github.com/carterperez-dev/templates/go-backend/gen/app/v1"
+	"github.com/carterperez-dev/templates/go-backend/internal/user"
+)
+
+// UserServer implements appv1.UserServiceServer by delegating to
+// user.Service, the same business logic the REST user.Handler calls into.
+// Admin-only RPCs re-check the role themselves since an interceptor only
+// knows the route is authenticated, not which RPCs require "admin".
+type UserServer struct {
+	appv1.UnimplementedUserServiceServer
+
+	service *user.Service
+}
+
+func NewUserServer(service *user.Service) *UserServer {
+	return &UserServer{service: service}
+}
+
+func (s *UserServer) GetMe(ctx context.Context, _ *appv1.GetMeRequest) (*appv1.UserInfo, error) {
+	u, err := s.service.GetMe(ctx, GetUserID(ctx))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toUserInfo(u), nil
+}
+
+func (s *UserServer) UpdateMe(ctx context.Context, req *appv1.UpdateMeRequest) (*appv1.UserInfo, error) {
+	name := req.GetName()
+
+	u, err := s.service.UpdateMe(ctx, GetUserID(ctx), user.UpdateUserRequest{Name: &name})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toUserInfo(u), nil
+}
+
+func (s *UserServer) DeleteMe(ctx context.Context, _ *appv1.DeleteMeRequest) (*appv1.DeleteMeResponse, error) {
+	if err := s.service.DeleteMe(ctx, GetUserID(ctx)); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &appv1.DeleteMeResponse{}, nil
+}
+
+func (s *UserServer) ListUsers(ctx context.Context, req *appv1.ListUsersRequest) (*appv1.ListUsersResponse, error) {
+	if err := RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	params := user.ListUsersParams{
+		Page:         int(req.GetPage()),
+		PageSize:     int(req.GetPageSize()),
+		Search:       req.GetSearch(),
+		Role:         req.GetRole(),
+		Tier:         req.GetTier(),
+		IncludeTotal: true,
+	}
+	params.Normalize()
+
+	result, err := s.service.ListUsers(ctx, params)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	infos := make([]*appv1.UserInfo, len(result.Users))
+	for i := range result.Users {
+		infos[i] = toUserInfo(&result.Users[i])
+	}
+
+	return &appv1.ListUsersResponse{
+		Users:    infos,
+		Total:    int64(result.Total),
+		Page:     int32(params.Page),
+		PageSize: int32(params.PageSize),
+	}, nil
+}
+
+func (s *UserServer) GetUser(ctx context.Context, req *appv1.GetUserRequest) (*appv1.UserInfo, error) {
+	if err := RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	u, err := s.service.GetUser(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toUserInfo(u), nil
+}
+
+func (s *UserServer) UpdateUser(ctx context.Context, req *appv1.UpdateUserRequest) (*appv1.UserInfo, error) {
+	if err := RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	name := req.GetName()
+
+	u, err := s.service.UpdateUser(ctx, req.GetUserId(), user.UpdateUserRequest{Name: &name})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toUserInfo(u), nil
+}
+
+func (s *UserServer) DeleteUser(ctx context.Context, req *appv1.DeleteUserRequest) (*appv1.DeleteUserResponse, error) {
+	if err := RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.service.CanDeleteUser(ctx, GetUserID(ctx), req.GetUserId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	if err := s.service.DeleteUser(ctx, req.GetUserId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &appv1.DeleteUserResponse{}, nil
+}
+
+func toUserInfo(u *user.User) *appv1.UserInfo {
+	return &appv1.UserInfo{
+		Id:    u.ID,
+		Email: u.Email,
+		Name:  u.Name,
+		Role:  u.Role,
+		Tier:  u.Tier,
+	}
+}
@@ -4,7 +4,14 @@
 package user
 
 import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/auth"
+	"github.com/carterperez-dev/templates/go-backend/internal/core"
 )
 
 type CreateUserRequest struct {
@@ -26,17 +33,70 @@ type UpdateUserTierRequest struct {
 }
 
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	Role      string    `json:"role"`
-	Tier      string    `json:"tier"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            string     `json:"id"`
+	Email         string     `json:"email"`
+	Name          string     `json:"name"`
+	Role          string     `json:"role"`
+	Tier          string     `json:"tier"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	EmailVerified bool       `json:"email_verified"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	PurgeAfter    *time.Time `json:"purge_after,omitempty"`
 }
 
 type UserListResponse struct {
 	Users []UserResponse `json:"users"`
+	// Total and TotalEstimated are only set when the request had
+	// include_total=true; NextCursor is only set in keyset mode (see
+	// ListUsersParams.IsKeyset) and only when a further page exists.
+	Total          int    `json:"total,omitempty"`
+	TotalEstimated bool   `json:"total_estimated,omitempty"`
+	NextCursor     string `json:"next_cursor,omitempty"`
+}
+
+type ImpersonateResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+type GrantPermissionRequest struct {
+	ResourcePattern string `json:"resource_pattern" validate:"required,max=255"`
+	Permission      string `json:"permission"        validate:"required,oneof=read write deny"`
+	Priority        int    `json:"priority"`
+}
+
+type RevokePermissionRequest struct {
+	ResourcePattern string `json:"resource_pattern" validate:"required,max=255"`
+}
+
+type PermissionResponse struct {
+	ID              string    `json:"id"`
+	ResourcePattern string    `json:"resource_pattern"`
+	Permission      string    `json:"permission"`
+	Priority        int       `json:"priority"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type PermissionListResponse struct {
+	Permissions []PermissionResponse `json:"permissions"`
+}
+
+func ToPermissionResponse(p *Permission) PermissionResponse {
+	return PermissionResponse{
+		ID:              p.ID,
+		ResourcePattern: p.ResourcePattern,
+		Permission:      p.Permission,
+		Priority:        p.Priority,
+		CreatedAt:       p.CreatedAt,
+	}
+}
+
+func ToPermissionResponseList(permissions []Permission) []PermissionResponse {
+	out := make([]PermissionResponse, len(permissions))
+	for i := range permissions {
+		out[i] = ToPermissionResponse(&permissions[i])
+	}
+	return out
 }
 
 type ListUsersParams struct {
@@ -45,6 +105,23 @@ type ListUsersParams struct {
 	Search   string `json:"search"`
 	Role     string `json:"role"`
 	Tier     string `json:"tier"`
+	// Deleted filters on deleted_at: "only" lists soft-deleted users,
+	// "include" lists both, and "" (the default) excludes them.
+	Deleted string `json:"deleted"`
+
+	// AfterCreatedAt and AfterID are the keyset cursor, decoded from a
+	// client-supplied NextCursor by DecodeUserCursor. When AfterID is set,
+	// List switches from offset to keyset pagination: it ignores Page and
+	// returns rows strictly after this (created_at, id) pair in the
+	// created_at DESC, id DESC order, which stays index-backed no matter how
+	// deep the caller pages, unlike LIMIT/OFFSET.
+	AfterCreatedAt *time.Time `json:"-"`
+	AfterID        string     `json:"-"`
+
+	// IncludeTotal requests a row count alongside the page. Defaults to
+	// false so a caller that only wants the next page — the common case in
+	// keyset mode — never pays for the COUNT.
+	IncludeTotal bool `json:"include_total"`
 }
 
 func (p *ListUsersParams) Normalize() {
@@ -63,15 +140,64 @@ func (p *ListUsersParams) Offset() int {
 	return (p.Page - 1) * p.PageSize
 }
 
+// IsKeyset reports whether params carries a keyset cursor, in which case
+// List pages by (created_at, id) instead of by Page/Offset.
+func (p *ListUsersParams) IsKeyset() bool {
+	return p.AfterID != ""
+}
+
+// ListResult is List's return value. Total and TotalEstimated are only
+// meaningful when the request set IncludeTotal; TotalEstimated marks a
+// Total that came from a pg_class.reltuples estimate rather than an exact
+// COUNT. NextCursor is the opaque token for the following page in keyset
+// mode, and is empty once there are no more rows.
+type ListResult struct {
+	Users          []User
+	Total          int
+	TotalEstimated bool
+	NextCursor     string
+}
+
+// EncodeUserCursor builds the opaque cursor List's keyset mode expects back
+// as NextCursor, from the (created_at, id) of the last row on a page.
+func EncodeUserCursor(createdAt time.Time, id string) string {
+	raw := fmt.Sprintf("%d|%s", createdAt.UnixNano(), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeUserCursor parses a cursor produced by EncodeUserCursor back into
+// the (created_at, id) pair List's keyset mode filters on.
+func DecodeUserCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("decode cursor: %w", core.ErrInvalidInput)
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", fmt.Errorf("decode cursor: %w", core.ErrInvalidInput)
+	}
+
+	ns, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("decode cursor: %w", core.ErrInvalidInput)
+	}
+
+	return time.Unix(0, ns), id, nil
+}
+
 func ToUserResponse(u *User) UserResponse {
 	return UserResponse{
-		ID:        u.ID,
-		Email:     u.Email,
-		Name:      u.Name,
-		Role:      u.Role,
-		Tier:      u.Tier,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:            u.ID,
+		Email:         u.Email,
+		Name:          u.Name,
+		Role:          u.Role,
+		Tier:          u.Tier,
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
+		EmailVerified: u.EmailVerified,
+		DeletedAt:     u.DeletedAt,
+		PurgeAfter:    u.PurgeAfter,
 	}
 }
 
@@ -82,3 +208,31 @@ func ToUserResponseList(users []User) []UserResponse {
 	}
 	return responses
 }
+
+type PasskeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	AAGUID     string     `json:"aaguid"`
+	Transports []string   `json:"transports"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+type PasskeyListResponse struct {
+	Passkeys []PasskeyResponse `json:"passkeys"`
+}
+
+func ToPasskeyResponseList(creds []auth.PasskeyCredential) []PasskeyResponse {
+	responses := make([]PasskeyResponse, 0, len(creds))
+	for _, c := range creds {
+		responses = append(responses, PasskeyResponse{
+			ID:         c.ID,
+			Name:       c.Name,
+			AAGUID:     c.AAGUID,
+			Transports: c.Transports,
+			CreatedAt:  c.CreatedAt,
+			LastUsedAt: c.LastUsedAt,
+		})
+	}
+	return responses
+}
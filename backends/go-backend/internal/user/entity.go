@@ -8,16 +8,21 @@ import (
 )
 
 type User struct {
-	ID           string     `db:"id"`
-	Email        string     `db:"email"`
-	PasswordHash string     `db:"password_hash"`
-	Name         string     `db:"name"`
-	Role         string     `db:"role"`
-	Tier         string     `db:"tier"`
-	TokenVersion int        `db:"token_version"`
-	CreatedAt    time.Time  `db:"created_at"`
-	UpdatedAt    time.Time  `db:"updated_at"`
-	DeletedAt    *time.Time `db:"deleted_at"`
+	ID                 string     `db:"id"`
+	Email              string     `db:"email"`
+	PasswordHash       string     `db:"password_hash"`
+	Name               string     `db:"name"`
+	Role               string     `db:"role"`
+	Tier               string     `db:"tier"`
+	TokenVersion       int        `db:"token_version"`
+	TOTPSecret         string     `db:"totp_secret"`
+	TOTPEnabled        bool       `db:"totp_enabled"`
+	RecoveryCodeHashes []string   `db:"recovery_code_hashes"`
+	EmailVerified      bool       `db:"email_verified"`
+	CreatedAt          time.Time  `db:"created_at"`
+	UpdatedAt          time.Time  `db:"updated_at"`
+	DeletedAt          *time.Time `db:"deleted_at"`
+	PurgeAfter         *time.Time `db:"purge_after"`
 }
 
 func (u *User) IsDeleted() bool {
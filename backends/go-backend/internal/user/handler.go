@@ -12,6 +12,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 
+	"github.com/carterperez-dev/templates/go-backend/internal/auth"
 	"github.com/carterperez-dev/templates/go-backend/internal/core"
 	"github.com/carterperez-dev/templates/go-backend/internal/middleware"
 )
@@ -38,9 +39,41 @@ func (h *Handler) RegisterRoutes(
 		r.Get("/me", h.GetMe)
 		r.Put("/me", h.UpdateMe)
 		r.Delete("/me", h.DeleteMe)
+		r.Get("/me/passkeys", h.ListMyPasskeys)
+		r.Delete("/me/passkeys/{credentialID}", h.RevokeMyPasskey)
 	})
 }
 
+// ListMyPasskeys returns the caller's registered WebAuthn credentials.
+func (h *Handler) ListMyPasskeys(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	creds, err := h.service.ListPasskeys(r.Context(), userID)
+	if err != nil {
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, PasskeyListResponse{Passkeys: ToPasskeyResponseList(creds)})
+}
+
+// RevokeMyPasskey deletes one of the caller's registered WebAuthn credentials.
+func (h *Handler) RevokeMyPasskey(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	credentialID := chi.URLParam(r, "credentialID")
+
+	if err := h.service.RevokePasskey(r.Context(), userID, credentialID); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			core.NotFound(w, "passkey")
+			return
+		}
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
 func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 
@@ -100,9 +133,16 @@ func (h *Handler) DeleteMe(w http.ResponseWriter, r *http.Request) {
 }
 
 // RegisterAdminRoutes registers admin-only user management endpoints.
+// reauth guards privilege-granting changes specifically — role/tier
+// changes, ACL permission grants, and impersonation — requiring the
+// calling admin to have recently re-entered their password, on top of
+// authenticator and adminOnly which already gate the whole group.
+// Callers should pass a middleware.RequireReauth built with
+// allowAPIToken false: none of those routes has any other check a
+// step-up requirement could stand in for.
 func (h *Handler) RegisterAdminRoutes(
 	r chi.Router,
-	authenticator, adminOnly func(http.Handler) http.Handler,
+	authenticator, adminOnly, reauth func(http.Handler) http.Handler,
 ) {
 	r.Route("/admin/users", func(r chi.Router) {
 		r.Use(authenticator)
@@ -111,34 +151,76 @@ func (h *Handler) RegisterAdminRoutes(
 		r.Get("/", h.ListUsers)
 		r.Get("/{userID}", h.GetUser)
 		r.Put("/{userID}", h.UpdateUser)
-		r.Put("/{userID}/role", h.UpdateUserRole)
-		r.Put("/{userID}/tier", h.UpdateUserTier)
+		r.With(reauth).Put("/{userID}/role", h.UpdateUserRole)
+		r.With(reauth).Put("/{userID}/tier", h.UpdateUserTier)
 		r.Delete("/{userID}", h.DeleteUser)
+		r.Post("/{userID}/restore", h.RestoreUser)
+		r.Post("/{userID}/revoke-tokens", h.RevokeTokens)
+		r.With(reauth).Post("/{userID}/impersonate", h.Impersonate)
+		r.Get("/{userID}/identities", h.ListUserIdentityLinks)
+		r.Delete("/{userID}/identities/{provider}", h.RevokeUserIdentityLink)
+		r.Get("/{userID}/permissions", h.ListPermissions)
+		r.With(reauth).Post("/{userID}/permissions", h.GrantPermission)
+		r.With(reauth).Delete("/{userID}/permissions", h.RevokePermission)
+		r.With(reauth).Post("/{userID}/permissions/reset", h.ResetPermissions)
 	})
 }
 
-// ListUsers returns a paginated list of users with optional filtering.
+// ListUsers returns a list of users with optional filtering. By default it
+// pages by page/page_size and returns a total via core.Paginated. Passing a
+// cursor query param (from a prior response's NextCursor) switches to
+// keyset mode instead, which stays index-backed no matter how deep the
+// caller pages; keyset mode skips the count unless include_total=true is
+// passed explicitly, since the common keyset caller (a deep export) is
+// exactly the one the COUNT is expensive for. Offset mode keeps counting by
+// default, matching its existing behavior, unless include_total=false.
 func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	cursor := r.URL.Query().Get("cursor")
+
 	params := ListUsersParams{
 		Page:     parseIntQuery(r, "page", 1),
 		PageSize: parseIntQuery(r, "page_size", 20),
 		Search:   r.URL.Query().Get("search"),
 		Role:     r.URL.Query().Get("role"),
 		Tier:     r.URL.Query().Get("tier"),
+		Deleted:  r.URL.Query().Get("deleted"),
+	}
+
+	if cursor != "" {
+		afterCreatedAt, afterID, err := DecodeUserCursor(cursor)
+		if err != nil {
+			core.BadRequest(w, "invalid cursor")
+			return
+		}
+		params.AfterCreatedAt = &afterCreatedAt
+		params.AfterID = afterID
+		params.IncludeTotal = r.URL.Query().Get("include_total") == "true"
+	} else {
+		params.IncludeTotal = r.URL.Query().Get("include_total") != "false"
 	}
 
-	users, total, err := h.service.ListUsers(r.Context(), params)
+	result, err := h.service.ListUsers(r.Context(), params)
 	if err != nil {
 		core.InternalServerError(w, err)
 		return
 	}
 
+	if params.IsKeyset() {
+		core.OK(w, UserListResponse{
+			Users:          ToUserResponseList(result.Users),
+			Total:          result.Total,
+			TotalEstimated: result.TotalEstimated,
+			NextCursor:     result.NextCursor,
+		})
+		return
+	}
+
 	core.Paginated(
 		w,
-		ToUserResponseList(users),
+		ToUserResponseList(result.Users),
 		params.Page,
 		params.PageSize,
-		total,
+		result.Total,
 	)
 }
 
@@ -161,6 +243,7 @@ func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
 
 // UpdateUser updates a specific user's profile (admin only).
 func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	actorID := middleware.GetUserID(r.Context())
 	userID := chi.URLParam(r, "userID")
 
 	var req UpdateUserRequest
@@ -174,7 +257,7 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.service.UpdateUser(r.Context(), userID, req)
+	user, err := h.service.UpdateUser(r.Context(), actorID, userID, req)
 	if err != nil {
 		if errors.Is(err, core.ErrNotFound) {
 			core.NotFound(w, "user")
@@ -189,6 +272,7 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 // UpdateUserRole changes a user's role (admin only).
 func (h *Handler) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	actorID := middleware.GetUserID(r.Context())
 	userID := chi.URLParam(r, "userID")
 
 	var req UpdateUserRoleRequest
@@ -202,7 +286,7 @@ func (h *Handler) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.service.UpdateUserRole(r.Context(), userID, req.Role)
+	user, err := h.service.UpdateUserRole(r.Context(), actorID, userID, req.Role)
 	if err != nil {
 		if errors.Is(err, core.ErrNotFound) {
 			core.NotFound(w, "user")
@@ -217,6 +301,7 @@ func (h *Handler) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
 
 // UpdateUserTier changes a user's subscription tier (admin only).
 func (h *Handler) UpdateUserTier(w http.ResponseWriter, r *http.Request) {
+	actorID := middleware.GetUserID(r.Context())
 	userID := chi.URLParam(r, "userID")
 
 	var req UpdateUserTierRequest
@@ -230,7 +315,7 @@ func (h *Handler) UpdateUserTier(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.service.UpdateUserTier(r.Context(), userID, req.Tier)
+	user, err := h.service.UpdateUserTier(r.Context(), actorID, userID, req.Tier)
 	if err != nil {
 		if errors.Is(err, core.ErrNotFound) {
 			core.NotFound(w, "user")
@@ -243,6 +328,38 @@ func (h *Handler) UpdateUserTier(w http.ResponseWriter, r *http.Request) {
 	core.OK(w, ToUserResponse(user))
 }
 
+// ListUserIdentityLinks returns a user's linked social-login providers
+// (admin only).
+func (h *Handler) ListUserIdentityLinks(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	links, err := h.service.ListIdentityLinksForUser(r.Context(), userID)
+	if err != nil {
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, auth.IdentityLinkListResponse{Links: auth.ToIdentityLinkResponseList(links)})
+}
+
+// RevokeUserIdentityLink unlinks a social-login provider from a user's
+// account (admin only).
+func (h *Handler) RevokeUserIdentityLink(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	provider := chi.URLParam(r, "provider")
+
+	if err := h.service.RevokeIdentityLink(r.Context(), userID, provider); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			core.NotFound(w, "identity link")
+			return
+		}
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
 // DeleteUser soft deletes a user account (admin only).
 func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	requesterID := middleware.GetUserID(r.Context())
@@ -261,7 +378,136 @@ func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.DeleteUser(r.Context(), targetID); err != nil {
+	if err := h.service.DeleteUser(r.Context(), requesterID, targetID); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			core.NotFound(w, "user")
+			return
+		}
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+// RestoreUser undoes a soft delete while the target is still within its
+// grace period (admin only). Past that window it's ErrNotFound: the row
+// may already be mid-purge, and the account must be recreated instead.
+func (h *Handler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	actorID := middleware.GetUserID(r.Context())
+	targetID := chi.URLParam(r, "userID")
+
+	if err := h.service.RestoreUser(r.Context(), actorID, targetID); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			core.NotFound(w, "user")
+			return
+		}
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+// ListPermissions returns a user's ACL grants (admin only).
+func (h *Handler) ListPermissions(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	permissions, err := h.service.ListPermissions(r.Context(), userID)
+	if err != nil {
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, PermissionListResponse{Permissions: ToPermissionResponseList(permissions)})
+}
+
+// GrantPermission grants (or denies) a permission on a resource pattern for
+// a user (admin only).
+func (h *Handler) GrantPermission(w http.ResponseWriter, r *http.Request) {
+	actorID := middleware.GetUserID(r.Context())
+	userID := chi.URLParam(r, "userID")
+
+	var req GrantPermissionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	err := h.service.GrantPermission(
+		r.Context(), actorID, userID, req.ResourcePattern, req.Permission, req.Priority,
+	)
+	if err != nil {
+		if errors.Is(err, core.ErrInvalidInput) {
+			core.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Is(err, core.ErrNotFound) {
+			core.NotFound(w, "user")
+			return
+		}
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+// RevokePermission removes a user's grant for a resource pattern (admin
+// only).
+func (h *Handler) RevokePermission(w http.ResponseWriter, r *http.Request) {
+	actorID := middleware.GetUserID(r.Context())
+	userID := chi.URLParam(r, "userID")
+
+	var req RevokePermissionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		core.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		core.BadRequest(w, core.FormatValidationError(err))
+		return
+	}
+
+	if err := h.service.RevokePermission(r.Context(), actorID, userID, req.ResourcePattern); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			core.NotFound(w, "permission")
+			return
+		}
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+// ResetPermissions clears every ACL grant for a user, reverting them to
+// their role's default access (admin only).
+func (h *Handler) ResetPermissions(w http.ResponseWriter, r *http.Request) {
+	actorID := middleware.GetUserID(r.Context())
+	userID := chi.URLParam(r, "userID")
+
+	if err := h.service.ResetPermissions(r.Context(), actorID, userID); err != nil {
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.NoContent(w)
+}
+
+// RevokeTokens invalidates all of a user's outstanding sessions and refresh
+// tokens (admin only).
+func (h *Handler) RevokeTokens(w http.ResponseWriter, r *http.Request) {
+	actorID := middleware.GetUserID(r.Context())
+	userID := chi.URLParam(r, "userID")
+
+	if err := h.service.RevokeTokens(r.Context(), actorID, userID); err != nil {
 		if errors.Is(err, core.ErrNotFound) {
 			core.NotFound(w, "user")
 			return
@@ -273,6 +519,25 @@ func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	core.NoContent(w)
 }
 
+// Impersonate issues a short-lived access token that lets the calling admin
+// act as the target user, for support and debugging purposes.
+func (h *Handler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	actorID := middleware.GetUserID(r.Context())
+	userID := chi.URLParam(r, "userID")
+
+	token, err := h.service.Impersonate(r.Context(), actorID, userID)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			core.NotFound(w, "user")
+			return
+		}
+		core.InternalServerError(w, err)
+		return
+	}
+
+	core.OK(w, ImpersonateResponse{AccessToken: token})
+}
+
 func parseIntQuery(r *http.Request, key string, defaultVal int) int {
 	val := r.URL.Query().Get(key)
 	if val == "" {
@@ -0,0 +1,30 @@
+// AngelaMos | 2026
+// permission.go
+
+package user
+
+import "time"
+
+// Permission values a Permission row may grant or deny for a resource
+// pattern. PermissionDeny always wins over PermissionRead/PermissionWrite
+// at equal specificity, so an operator can carve out an exception inside
+// a broader grant (e.g. allow "project:*" but deny "project:123").
+const (
+	PermissionRead  = "read"
+	PermissionWrite = "write"
+	PermissionDeny  = "deny"
+)
+
+// Permission is one ACL grant: userID may (or, if Permission is
+// PermissionDeny, may not) perform actions up to Permission against any
+// resource matching ResourcePattern. A trailing "/*" makes ResourcePattern
+// a prefix match (e.g. "topic:foo/*" covers "topic:foo/bar"); anything
+// else must match the resource exactly (e.g. "project:123").
+type Permission struct {
+	ID              string    `db:"id"`
+	UserID          string    `db:"user_id"`
+	ResourcePattern string    `db:"resource_pattern"`
+	Permission      string    `db:"permission"`
+	Priority        int       `db:"priority"`
+	CreatedAt       time.Time `db:"created_at"`
+}
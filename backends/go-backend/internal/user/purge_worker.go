@@ -0,0 +1,102 @@
+// AngelaMos | 2026
+// purge_worker.go
+
+package user
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/carterperez-dev/templates/go-backend/internal/config"
+)
+
+// purgeWorkerLockKey guards a single purge run the same way
+// auth.Janitor's janitorLockKey does: with multiple API replicas running
+// PurgeWorker.Run on the same config, only one of them does the work for
+// a given tick. The lock's TTL is PurgeInterval, the same tradeoff
+// Janitor makes: a run that takes longer than one interval lets the lock
+// expire before it finishes, allowing an overlapping run to start on the
+// next tick rather than staying locked out indefinitely if a replica dies
+// mid-run without releasing it.
+const purgeWorkerLockKey = "purge_worker:users:lock"
+
+// PurgeWorker periodically hard-deletes users whose soft-delete grace
+// period has elapsed, closing out what DeleteUser/DeleteMe started.
+type PurgeWorker struct {
+	repo  Repository
+	redis *redis.Client
+	cfg   config.SoftDeleteConfig
+}
+
+// NewPurgeWorker builds a PurgeWorker; call Run in its own goroutine to
+// start it.
+func NewPurgeWorker(repo Repository, redisClient *redis.Client, cfg config.SoftDeleteConfig) *PurgeWorker {
+	return &PurgeWorker{repo: repo, redis: redisClient, cfg: cfg}
+}
+
+// Run sweeps on cfg.PurgeInterval until ctx is canceled. A non-positive
+// PurgeInterval disables the worker entirely, the same convention
+// auth.Janitor.Run uses for its own interval.
+func (w *PurgeWorker) Run(ctx context.Context) {
+	if w.cfg.PurgeInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+func (w *PurgeWorker) runOnce(ctx context.Context) {
+	acquired, err := w.redis.SetNX(ctx, purgeWorkerLockKey, "1", w.cfg.PurgeInterval).Result()
+	if err != nil {
+		slog.Error("purge worker lock acquisition failed", "error", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	start := time.Now()
+
+	candidates, err := w.repo.ListPendingPurge(ctx, start, w.cfg.BatchSize)
+	if err != nil {
+		slog.Error("purge worker list pending purge failed", "error", err)
+		return
+	}
+
+	// Each candidate is purged in its own transaction (see
+	// repository.HardDelete) rather than the whole batch in one, so a
+	// single bad row can't roll back every other user this tick already
+	// cleared.
+	var purged, failed int
+	for _, candidate := range candidates {
+		if err := w.repo.HardDelete(ctx, candidate.ID); err != nil {
+			slog.Error("purge worker hard delete failed",
+				"error", err,
+				"user_id", candidate.ID,
+			)
+			failed++
+			continue
+		}
+		purged++
+	}
+
+	slog.Info("user purge worker run complete",
+		"candidates", len(candidates),
+		"purged", purged,
+		"failed", failed,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+}
@@ -9,8 +9,11 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
 
 	"github.com/carterperez-dev/templates/go-backend/internal/core"
 )
@@ -22,16 +25,35 @@ type Repository interface {
 	Update(ctx context.Context, user *User) error
 	UpdatePassword(ctx context.Context, id, passwordHash string) error
 	IncrementTokenVersion(ctx context.Context, id string) error
-	SoftDelete(ctx context.Context, id string) error
-	List(ctx context.Context, params ListUsersParams) ([]User, int, error)
+	SoftDelete(ctx context.Context, id string, gracePeriod time.Duration) error
+	ExtendGracePeriod(ctx context.Context, id string, gracePeriod time.Duration) error
+	RestoreSoftDeleted(ctx context.Context, id string) error
+	ListPendingPurge(ctx context.Context, before time.Time, limit int) ([]User, error)
+	HardDelete(ctx context.Context, id string) error
+	List(ctx context.Context, params ListUsersParams) (ListResult, error)
+	Stream(ctx context.Context, params ListUsersParams, fn func(User) error) error
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	SetTOTPSecret(ctx context.Context, id, secret string, recoveryCodeHashes []string) error
+	EnableTOTP(ctx context.Context, id string) error
+	DisableTOTP(ctx context.Context, id string) error
+	UpdateRecoveryCodeHashes(ctx context.Context, id string, hashes []string) error
+	SetEmailVerified(ctx context.Context, id string) error
+	GrantPermission(ctx context.Context, userID, resourcePattern, permission string, priority int) error
+	RevokePermission(ctx context.Context, userID, resourcePattern string) error
+	ResetPermissions(ctx context.Context, userID string) error
+	ListPermissions(ctx context.Context, userID string) ([]Permission, error)
+	CheckPermission(ctx context.Context, userID, resource, action string) (bool, error)
 }
 
+// repository holds the concrete *sqlx.DB, rather than the narrower
+// core.DBTX most other repositories in this codebase use, because
+// HardDelete needs core.InTx to cascade a delete across several tables
+// atomically.
 type repository struct {
-	db core.DBTX
+	db *sqlx.DB
 }
 
-func NewRepository(db core.DBTX) Repository {
+func NewRepository(db *sqlx.DB) Repository {
 	return &repository{db: db}
 }
 
@@ -62,6 +84,7 @@ func (r *repository) Create(ctx context.Context, user *User) error {
 func (r *repository) GetByID(ctx context.Context, id string) (*User, error) {
 	query := `
 		SELECT id, email, password_hash, name, role, tier, token_version,
+		       totp_secret, totp_enabled, recovery_code_hashes, email_verified,
 		       created_at, updated_at, deleted_at
 		FROM users
 		WHERE id = $1 AND deleted_at IS NULL`
@@ -84,6 +107,7 @@ func (r *repository) GetByEmail(
 ) (*User, error) {
 	query := `
 		SELECT id, email, password_hash, name, role, tier, token_version,
+		       totp_secret, totp_enabled, recovery_code_hashes, email_verified,
 		       created_at, updated_at, deleted_at
 		FROM users
 		WHERE email = $1 AND deleted_at IS NULL`
@@ -175,13 +199,143 @@ func (r *repository) IncrementTokenVersion(
 	return nil
 }
 
-func (r *repository) SoftDelete(ctx context.Context, id string) error {
+// SetTOTPSecret stages a freshly-generated TOTP secret and recovery code
+// hashes without touching totp_enabled — the auth service only flips that
+// on once EnableTOTP confirms the secret against a real code.
+func (r *repository) SetTOTPSecret(
+	ctx context.Context,
+	id, secret string,
+	recoveryCodeHashes []string,
+) error {
+	query := `
+		UPDATE users
+		SET totp_secret = $2, recovery_code_hashes = $3, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, secret, recoveryCodeHashes)
+	if err != nil {
+		return fmt.Errorf("set totp secret: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("set totp secret: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("set totp secret: %w", core.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *repository) EnableTOTP(ctx context.Context, id string) error {
+	query := `
+		UPDATE users
+		SET totp_enabled = true, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("enable totp: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("enable totp: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("enable totp: %w", core.ErrNotFound)
+	}
+
+	return nil
+}
+
+// DisableTOTP clears the secret and recovery codes along with the flag, so
+// a subsequent EnrollTOTP starts from a clean slate rather than reusing a
+// previously-confirmed secret.
+func (r *repository) DisableTOTP(ctx context.Context, id string) error {
 	query := `
 		UPDATE users
-		SET deleted_at = NOW(), updated_at = NOW()
+		SET totp_secret = '', totp_enabled = false, recovery_code_hashes = '{}', updated_at = NOW()
 		WHERE id = $1 AND deleted_at IS NULL`
 
 	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("disable totp: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("disable totp: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("disable totp: %w", core.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *repository) UpdateRecoveryCodeHashes(ctx context.Context, id string, hashes []string) error {
+	query := `
+		UPDATE users
+		SET recovery_code_hashes = $2, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, hashes)
+	if err != nil {
+		return fmt.Errorf("update recovery code hashes: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update recovery code hashes: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("update recovery code hashes: %w", core.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *repository) SetEmailVerified(ctx context.Context, id string) error {
+	query := `
+		UPDATE users
+		SET email_verified = true, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("set email verified: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("set email verified: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("set email verified: %w", core.ErrNotFound)
+	}
+
+	return nil
+}
+
+// SoftDelete marks a user deleted and sets purge_after gracePeriod out from
+// now, the window during which RestoreSoftDeleted will still undo it and
+// before which ListPendingPurge won't yet surface it to PurgeWorker.
+func (r *repository) SoftDelete(ctx context.Context, id string, gracePeriod time.Duration) error {
+	query := `
+		UPDATE users
+		SET deleted_at = NOW(),
+		    purge_after = NOW() + make_interval(secs => $2),
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, gracePeriod.Seconds())
 	if err != nil {
 		return fmt.Errorf("delete user: %w", err)
 	}
@@ -198,65 +352,574 @@ func (r *repository) SoftDelete(ctx context.Context, id string) error {
 	return nil
 }
 
+// ExtendGracePeriod resets purge_after to gracePeriod out from now, for an
+// already soft-deleted user. Unlike SoftDelete it requires deleted_at to
+// already be set, so it can't be used to soft-delete an active account.
+func (r *repository) ExtendGracePeriod(ctx context.Context, id string, gracePeriod time.Duration) error {
+	query := `
+		UPDATE users
+		SET purge_after = NOW() + make_interval(secs => $2), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, gracePeriod.Seconds())
+	if err != nil {
+		return fmt.Errorf("extend grace period: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("extend grace period: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("extend grace period: %w", core.ErrNotFound)
+	}
+
+	return nil
+}
+
+// RestoreSoftDeleted undoes a soft delete, but only while NOW() < purge_after:
+// once the grace period has lapsed the row may already be mid-purge, so the
+// caller gets ErrNotFound and must recreate the account instead.
+func (r *repository) RestoreSoftDeleted(ctx context.Context, id string) error {
+	query := `
+		UPDATE users
+		SET deleted_at = NULL, purge_after = NULL, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL AND NOW() < purge_after`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("restore soft deleted user: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("restore soft deleted user: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("restore soft deleted user: %w", core.ErrNotFound)
+	}
+
+	return nil
+}
+
+// defaultPendingPurgeBatchSize caps a single ListPendingPurge call when the
+// caller passes a non-positive limit, so a misconfigured
+// SoftDeleteConfig.BatchSize can't turn it into an unbounded scan.
+const defaultPendingPurgeBatchSize = 500
+
+// ListPendingPurge returns up to limit soft-deleted users whose grace
+// period ended before before, oldest purge_after first, for PurgeWorker to
+// hard-delete. A non-positive limit falls back to
+// defaultPendingPurgeBatchSize.
+func (r *repository) ListPendingPurge(ctx context.Context, before time.Time, limit int) ([]User, error) {
+	if limit <= 0 {
+		limit = defaultPendingPurgeBatchSize
+	}
+
+	query := `
+		SELECT id, email, password_hash, name, role, tier, token_version,
+		       totp_secret, totp_enabled, recovery_code_hashes, email_verified,
+		       created_at, updated_at, deleted_at, purge_after
+		FROM users
+		WHERE deleted_at IS NOT NULL AND purge_after IS NOT NULL AND purge_after < $1
+		ORDER BY purge_after
+		LIMIT $2`
+
+	var users []User
+	if err := r.db.SelectContext(ctx, &users, query, before, limit); err != nil {
+		return nil, fmt.Errorf("list pending purge: %w", err)
+	}
+
+	return users, nil
+}
+
+// hardDeleteTables lists every table keyed by user_id that HardDelete must
+// scrub before the users row itself can go. This schema snapshot has no
+// ON DELETE CASCADE to lean on, so the cascade is done explicitly here,
+// inside the same transaction as the final DELETE FROM users.
+var hardDeleteTables = []string{
+	"refresh_tokens",
+	"api_tokens",
+	"passkey_credentials",
+	"identity_links",
+	"device_authorizations",
+	"password_reset_tokens",
+	"email_verification_tokens",
+	"user_permissions",
+}
+
+// HardDelete permanently removes id and every row in hardDeleteTables that
+// references it, all inside one transaction so a failure partway through
+// can't leave orphaned rows behind.
+func (r *repository) HardDelete(ctx context.Context, id string) error {
+	err := core.InTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		for _, table := range hardDeleteTables {
+			query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", table)
+			if _, err := tx.ExecContext(ctx, query, id); err != nil {
+				return fmt.Errorf("delete from %s: %w", table, err)
+			}
+		}
+
+		result, err := tx.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id)
+		if err != nil {
+			return fmt.Errorf("delete user: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("delete user: %w", err)
+		}
+
+		if rows == 0 {
+			return fmt.Errorf("delete user: %w", core.ErrNotFound)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("hard delete user: %w", err)
+	}
+
+	return nil
+}
+
+// defaultRolePermissions is the fallback CheckPermission falls back to when
+// userID has zero permission rows matching resource at all: admins get
+// blanket access, plain users get none until an explicit grant exists.
+var defaultRolePermissions = map[string]bool{
+	RoleAdmin: true,
+	RoleUser:  false,
+}
+
+// GrantPermission upserts a grant (or deny) of permission on resourcePattern
+// for userID, replacing any existing grant for that exact pattern.
+func (r *repository) GrantPermission(
+	ctx context.Context,
+	userID, resourcePattern, permission string,
+	priority int,
+) error {
+	query := `
+		INSERT INTO user_permissions (id, user_id, resource_pattern, permission, priority)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, resource_pattern)
+		DO UPDATE SET permission = EXCLUDED.permission, priority = EXCLUDED.priority`
+
+	_, err := r.db.ExecContext(ctx, query,
+		uuid.New().String(), userID, resourcePattern, permission, priority)
+	if err != nil {
+		return fmt.Errorf("grant permission: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) RevokePermission(
+	ctx context.Context,
+	userID, resourcePattern string,
+) error {
+	query := `DELETE FROM user_permissions WHERE user_id = $1 AND resource_pattern = $2`
+
+	result, err := r.db.ExecContext(ctx, query, userID, resourcePattern)
+	if err != nil {
+		return fmt.Errorf("revoke permission: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("revoke permission: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("revoke permission: %w", core.ErrNotFound)
+	}
+
+	return nil
+}
+
+// ResetPermissions clears every ACL grant for userID, reverting it to
+// defaultRolePermissions. Unlike RevokePermission this isn't an error when
+// there was nothing to delete: "no grants" is ResetPermissions' own
+// steady state, not a missing-row condition.
+func (r *repository) ResetPermissions(ctx context.Context, userID string) error {
+	query := `DELETE FROM user_permissions WHERE user_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("reset permissions: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) ListPermissions(ctx context.Context, userID string) ([]Permission, error) {
+	query := `
+		SELECT id, user_id, resource_pattern, permission, priority, created_at
+		FROM user_permissions
+		WHERE user_id = $1
+		ORDER BY priority DESC, created_at`
+
+	var permissions []Permission
+	if err := r.db.SelectContext(ctx, &permissions, query, userID); err != nil {
+		return nil, fmt.Errorf("list permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// CheckPermission reports whether userID may perform action (PermissionRead
+// or PermissionWrite) against resource.
+//
+// Every grant whose ResourcePattern matches resource is a candidate; an
+// exact match always outranks a "/*" prefix match, and among patterns of
+// the same kind a longer pattern outranks a shorter one, since it names a
+// narrower slice of resources. Among the candidates tied for the highest
+// specificity, a PermissionDeny always wins outright over a read/write
+// grant regardless of Priority — Priority only breaks ties between two
+// candidates that are both Deny or both non-Deny. If nothing matches
+// resource at all, the decision falls back to defaultRolePermissions for
+// userID's role.
+func (r *repository) CheckPermission(
+	ctx context.Context,
+	userID, resource, action string,
+) (bool, error) {
+	var role string
+	if err := r.db.GetContext(ctx, &role,
+		`SELECT role FROM users WHERE id = $1 AND deleted_at IS NULL`, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("check permission: %w", core.ErrNotFound)
+		}
+		return false, fmt.Errorf("check permission: %w", err)
+	}
+
+	permissions, err := r.ListPermissions(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("check permission: %w", err)
+	}
+
+	best := selectBestPermission(permissions, resource)
+	if best == nil {
+		return defaultRolePermissions[role], nil
+	}
+
+	switch best.Permission {
+	case PermissionDeny:
+		return false, nil
+	case PermissionWrite:
+		return true, nil
+	case PermissionRead:
+		return action == PermissionRead, nil
+	default:
+		return false, nil
+	}
+}
+
+// matchSpecificity reports whether pattern matches resource and, if so, how
+// specific that match is: an exact pattern scores len(pattern)*2+1, a "/*"
+// prefix match scores len(prefix)*2, so an exact match always beats a
+// wildcard match of the same or greater length, and a longer wildcard
+// prefix always beats a shorter one.
+func matchSpecificity(pattern, resource string) (int, bool) {
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		if resource == prefix || strings.HasPrefix(resource, prefix+"/") {
+			return len(prefix) * 2, true
+		}
+		return 0, false
+	}
+
+	if pattern == resource {
+		return len(pattern)*2 + 1, true
+	}
+
+	return 0, false
+}
+
+// selectBestPermission picks the permissions candidate CheckPermission
+// should decide by: every grant matching resource competes on specificity
+// first, then, among candidates tied for the highest specificity, a
+// PermissionDeny always wins outright over a read/write grant regardless of
+// Priority — Priority only breaks ties between two candidates that are both
+// Deny or both non-Deny. Returns nil if nothing in permissions matches
+// resource.
+func selectBestPermission(permissions []Permission, resource string) *Permission {
+	var best *Permission
+	var bestSpecificity int
+
+	for i := range permissions {
+		p := &permissions[i]
+
+		specificity, matched := matchSpecificity(p.ResourcePattern, resource)
+		if !matched {
+			continue
+		}
+
+		switch {
+		case best == nil, specificity > bestSpecificity:
+			best, bestSpecificity = p, specificity
+		case specificity == bestSpecificity:
+			switch {
+			case best.Permission == PermissionDeny && p.Permission != PermissionDeny:
+				// best is already a Deny at this specificity; a higher-priority
+				// grant must not override it.
+			case p.Permission == PermissionDeny && best.Permission != PermissionDeny:
+				best = p
+			case p.Priority > best.Priority:
+				best = p
+			}
+		}
+	}
+
+	return best
+}
+
+// largeTableEstimateThreshold is how large pg_class's reltuples estimate for
+// users must be before countUsersTotal trusts it over a real COUNT(*). Below
+// this, an exact count is cheap enough that there's no reason to prefer an
+// estimate that's only refreshed by autovacuum.
+const largeTableEstimateThreshold = 100_000
+
+// streamBatchSize is how many rows Stream fetches per round trip from its
+// server-side cursor.
+const streamBatchSize = 500
+
 func (r *repository) List(
 	ctx context.Context,
 	params ListUsersParams,
-) ([]User, int, error) {
+) (ListResult, error) {
 	params.Normalize()
 
+	conditions, args := buildUserFilterConditions(params)
+	estimateEligible := params.Deleted == "include" &&
+		params.Search == "" && params.Role == "" && params.Tier == ""
+
+	if params.IsKeyset() {
+		return r.listKeyset(ctx, params, conditions, args, estimateEligible)
+	}
+
+	var result ListResult
+
+	if params.IncludeTotal {
+		total, estimated, err := r.countUsersTotal(ctx, joinConditions(conditions), args, estimateEligible)
+		if err != nil {
+			return ListResult{}, err
+		}
+		result.Total = total
+		result.TotalEstimated = estimated
+	}
+
+	whereClause := joinConditions(conditions)
+	argIdx := len(args) + 1
+	query := fmt.Sprintf(`
+		SELECT id, email, name, role, tier, token_version, email_verified,
+		       created_at, updated_at, deleted_at, purge_after
+		FROM users
+		WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d`,
+		whereClause, argIdx, argIdx+1)
+
+	pageArgs := append(args, params.PageSize, params.Offset())
+
+	var users []User
+	if err := r.db.SelectContext(ctx, &users, query, pageArgs...); err != nil {
+		return ListResult{}, fmt.Errorf("list users: %w", err)
+	}
+	result.Users = users
+
+	return result, nil
+}
+
+// buildUserFilterConditions returns the WHERE conditions and positional args
+// for params' Deleted/Search/Role/Tier filters, shared by List's offset and
+// keyset modes and by Stream. Callers append further conditions (a keyset
+// predicate, a LIMIT) using len(args)+1 as the next placeholder index.
+func buildUserFilterConditions(params ListUsersParams) ([]string, []any) {
 	var conditions []string
 	var args []any
-	argIdx := 1
 
-	conditions = append(conditions, "deleted_at IS NULL")
+	switch params.Deleted {
+	case "only":
+		conditions = append(conditions, "deleted_at IS NOT NULL")
+	case "include":
+		// no condition: both deleted and active users match
+	default:
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
 
 	if params.Search != "" {
+		argIdx := len(args) + 1
 		conditions = append(conditions, fmt.Sprintf(
 			"(email ILIKE $%d OR name ILIKE $%d)", argIdx, argIdx))
 		args = append(args, "%"+escapeLike(params.Search)+"%")
-		argIdx++
 	}
 
 	if params.Role != "" {
+		argIdx := len(args) + 1
 		conditions = append(conditions, fmt.Sprintf("role = $%d", argIdx))
 		args = append(args, params.Role)
-		argIdx++
 	}
 
 	if params.Tier != "" {
+		argIdx := len(args) + 1
 		conditions = append(conditions, fmt.Sprintf("tier = $%d", argIdx))
 		args = append(args, params.Tier)
-		argIdx++
 	}
 
-	whereClause := strings.Join(conditions, " AND ")
+	return conditions, args
+}
 
-	countQuery := fmt.Sprintf(
-		"SELECT COUNT(*) FROM users WHERE %s",
-		whereClause,
-	)
-	var total int
-	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
-		return nil, 0, fmt.Errorf("count users: %w", err)
+// joinConditions ANDs conditions together, or returns "TRUE" if there are
+// none (Deleted == "include" with no Search/Role/Tier filter) so the WHERE
+// clause built around it is always valid SQL instead of "WHERE " followed
+// directly by ORDER BY.
+func joinConditions(conditions []string) string {
+	if len(conditions) == 0 {
+		return "TRUE"
 	}
+	return strings.Join(conditions, " AND ")
+}
 
+// countUsersTotal returns the row count matching whereClause/args. When
+// estimateEligible is true (Deleted == "include" and no Search/Role/Tier
+// filter — i.e. whereClause matches the whole table) it first asks pg_class
+// for the table's approximate row count, and returns that estimate once it
+// clears largeTableEstimateThreshold instead of running a synchronous full
+// scan. Any narrower filter can't be estimated this way — pg_class has no
+// idea how many rows are soft-deleted or match "role = 'admin'" — so those
+// always fall through to an exact COUNT(*).
+func (r *repository) countUsersTotal(
+	ctx context.Context,
+	whereClause string,
+	args []any,
+	estimateEligible bool,
+) (total int, estimated bool, err error) {
+	if estimateEligible {
+		var reltuples float64
+		if err := r.db.GetContext(ctx, &reltuples,
+			`SELECT reltuples FROM pg_class WHERE relname = 'users'`,
+		); err == nil && reltuples > largeTableEstimateThreshold {
+			return int(reltuples), true, nil
+		}
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM users WHERE %s", whereClause)
+	if err := r.db.GetContext(ctx, &total, query, args...); err != nil {
+		return 0, false, fmt.Errorf("count users: %w", err)
+	}
+
+	return total, false, nil
+}
+
+// listKeyset is List's keyset-pagination path: conditions/args are the base
+// filters from buildUserFilterConditions, not yet carrying the cursor
+// predicate, so a requested total counts every row matching the filters
+// rather than only those after the cursor.
+func (r *repository) listKeyset(
+	ctx context.Context,
+	params ListUsersParams,
+	conditions []string,
+	args []any,
+	estimateEligible bool,
+) (ListResult, error) {
+	var result ListResult
+
+	if params.IncludeTotal {
+		total, estimated, err := r.countUsersTotal(ctx, joinConditions(conditions), args, estimateEligible)
+		if err != nil {
+			return ListResult{}, err
+		}
+		result.Total = total
+		result.TotalEstimated = estimated
+	}
+
+	if params.AfterCreatedAt != nil {
+		argIdx := len(args) + 1
+		conditions = append(conditions, fmt.Sprintf(
+			"(created_at, id) < ($%d, $%d)", argIdx, argIdx+1))
+		args = append(args, *params.AfterCreatedAt, params.AfterID)
+	}
+
+	whereClause := joinConditions(conditions)
+
+	// Fetch one extra row so we know whether another page follows without a
+	// second round trip.
+	limitIdx := len(args) + 1
 	query := fmt.Sprintf(`
-		SELECT id, email, name, role, tier, token_version,
-		       created_at, updated_at, deleted_at
+		SELECT id, email, name, role, tier, token_version, email_verified,
+		       created_at, updated_at, deleted_at, purge_after
 		FROM users
 		WHERE %s
-		ORDER BY created_at DESC
-		LIMIT $%d OFFSET $%d`,
-		whereClause, argIdx, argIdx+1)
-
-	args = append(args, params.PageSize, params.Offset())
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d`,
+		whereClause, limitIdx)
+	args = append(args, params.PageSize+1)
 
 	var users []User
 	if err := r.db.SelectContext(ctx, &users, query, args...); err != nil {
-		return nil, 0, fmt.Errorf("list users: %w", err)
+		return ListResult{}, fmt.Errorf("list users (keyset): %w", err)
+	}
+
+	if len(users) > params.PageSize {
+		users = users[:params.PageSize]
+		last := users[len(users)-1]
+		result.NextCursor = EncodeUserCursor(last.CreatedAt, last.ID)
 	}
+	result.Users = users
 
-	return users, total, nil
+	return result, nil
+}
+
+// Stream walks every user matching params' filters, in created_at, id
+// order, via a server-side cursor inside one transaction, calling fn once
+// per row in batches of streamBatchSize. Unlike List, it never buffers the
+// whole result set, so an admin export or a bulk email job can walk the
+// entire table without loading it into memory. An error from fn stops the
+// stream and rolls back the transaction.
+func (r *repository) Stream(
+	ctx context.Context,
+	params ListUsersParams,
+	fn func(User) error,
+) error {
+	conditions, args := buildUserFilterConditions(params)
+	whereClause := joinConditions(conditions)
+
+	err := core.InTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		declareQuery := fmt.Sprintf(`
+			DECLARE user_stream NO SCROLL CURSOR FOR
+			SELECT id, email, name, role, tier, token_version, email_verified,
+			       created_at, updated_at, deleted_at, purge_after
+			FROM users
+			WHERE %s
+			ORDER BY created_at, id`,
+			whereClause)
+
+		if _, err := tx.ExecContext(ctx, declareQuery, args...); err != nil {
+			return fmt.Errorf("declare user stream cursor: %w", err)
+		}
+
+		fetchQuery := fmt.Sprintf("FETCH FORWARD %d FROM user_stream", streamBatchSize)
+
+		for {
+			var batch []User
+			if err := tx.SelectContext(ctx, &batch, fetchQuery); err != nil {
+				return fmt.Errorf("fetch user stream batch: %w", err)
+			}
+			if len(batch) == 0 {
+				return nil
+			}
+
+			for _, u := range batch {
+				if err := fn(u); err != nil {
+					return err
+				}
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("stream users: %w", err)
+	}
+
+	return nil
 }
 
 func (r *repository) ExistsByEmail(
@@ -0,0 +1,85 @@
+package user
+
+import "testing"
+
+func TestMatchSpecificity(t *testing.T) {
+	tests := []struct {
+		name              string
+		pattern, resource string
+		wantSpecificity   int
+		wantMatched       bool
+	}{
+		{"exact match", "project:123", "project:123", len("project:123")*2 + 1, true},
+		{"exact mismatch", "project:123", "project:456", 0, false},
+		{"prefix match on the base resource", "project:*", "project", len("project") * 2, true},
+		{"prefix match on a child resource", "topic:foo/*", "topic:foo/bar", len("topic:foo") * 2, true},
+		{"prefix non-match", "topic:foo/*", "topic:food", 0, false},
+		{"longer prefix outranks shorter prefix", "topic:foo/bar/*", "topic:foo/bar/baz", len("topic:foo/bar") * 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specificity, matched := matchSpecificity(tt.pattern, tt.resource)
+			if matched != tt.wantMatched || specificity != tt.wantSpecificity {
+				t.Fatalf("matchSpecificity(%q, %q) = (%d, %v), want (%d, %v)",
+					tt.pattern, tt.resource, specificity, matched, tt.wantSpecificity, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestSelectBestPermission(t *testing.T) {
+	t.Run("no candidates match", func(t *testing.T) {
+		perms := []Permission{{ResourcePattern: "project:other", Permission: PermissionWrite}}
+		if got := selectBestPermission(perms, "project:123"); got != nil {
+			t.Fatalf("expected nil, got %+v", got)
+		}
+	})
+
+	t.Run("exact match outranks a wildcard match regardless of priority", func(t *testing.T) {
+		wildcard := Permission{ResourcePattern: "project:*", Permission: PermissionWrite, Priority: 100}
+		exact := Permission{ResourcePattern: "project:123", Permission: PermissionRead, Priority: 0}
+		perms := []Permission{wildcard, exact}
+
+		got := selectBestPermission(perms, "project:123")
+		if got == nil || got.ResourcePattern != "project:123" {
+			t.Fatalf("expected the exact-match grant to win, got %+v", got)
+		}
+	})
+
+	t.Run("an explicit deny wins at equal specificity regardless of priority", func(t *testing.T) {
+		deny := Permission{ResourcePattern: "project:123", Permission: PermissionDeny, Priority: 0}
+		allow := Permission{ResourcePattern: "project:123", Permission: PermissionWrite, Priority: 100}
+
+		got := selectBestPermission([]Permission{deny, allow}, "project:123")
+		if got == nil || got.Permission != PermissionDeny {
+			t.Fatalf("expected deny to win outright, got %+v", got)
+		}
+
+		// Order must not matter — a higher-priority allow arriving after the
+		// deny still must not unseat it.
+		got = selectBestPermission([]Permission{allow, deny}, "project:123")
+		if got == nil || got.Permission != PermissionDeny {
+			t.Fatalf("expected deny to win outright regardless of slice order, got %+v", got)
+		}
+	})
+
+	t.Run("priority breaks ties between two non-deny grants of equal specificity", func(t *testing.T) {
+		low := Permission{ResourcePattern: "project:123", Permission: PermissionRead, Priority: 1}
+		high := Permission{ResourcePattern: "project:123", Permission: PermissionWrite, Priority: 2}
+
+		got := selectBestPermission([]Permission{low, high}, "project:123")
+		if got == nil || got.Permission != PermissionWrite {
+			t.Fatalf("expected the higher-priority non-deny grant to win, got %+v", got)
+		}
+	})
+
+	t.Run("priority breaks ties between two deny grants of equal specificity", func(t *testing.T) {
+		lowDeny := Permission{ID: "low", ResourcePattern: "project:123", Permission: PermissionDeny, Priority: 1}
+		highDeny := Permission{ID: "high", ResourcePattern: "project:123", Permission: PermissionDeny, Priority: 2}
+
+		got := selectBestPermission([]Permission{lowDeny, highDeny}, "project:123")
+		if got == nil || got.ID != "high" {
+			t.Fatalf("expected the higher-priority deny grant to win, got %+v", got)
+		}
+	})
+}
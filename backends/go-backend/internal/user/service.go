@@ -6,16 +6,209 @@ package user
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/carterperez-dev/templates/go-backend/internal/auth"
+	"github.com/carterperez-dev/templates/go-backend/internal/config"
 	"github.com/carterperez-dev/templates/go-backend/internal/core"
 )
 
+// PasskeyProvider is implemented by auth.PasskeyService. auth.Service
+// already depends on user.Service as its UserProvider, so this dependency
+// must run the other way through an interface to avoid an import cycle; it
+// is wired in with SetPasskeyProvider once both services exist.
+type PasskeyProvider interface {
+	ListPasskeys(ctx context.Context, userID string) ([]auth.PasskeyCredential, error)
+	RevokePasskey(ctx context.Context, userID, credentialID string) error
+}
+
+// IdentityLinksProvider is implemented by auth.Service, for the same
+// import-cycle reason as PasskeyProvider above. It is wired in with
+// SetIdentityLinksProvider so admins can manage social-login links through
+// the user package's admin routes.
+type IdentityLinksProvider interface {
+	ListIdentityLinks(ctx context.Context, userID string) ([]auth.IdentityLink, error)
+	UnlinkIdentity(ctx context.Context, userID, provider string) error
+}
+
+// TierChangeNotifier is implemented by core/quota.Limiter. It's defined
+// here as an interface, the same way PasskeyProvider is above, so this
+// package doesn't need to import core/quota just to publish an
+// invalidation when a tier changes.
+type TierChangeNotifier interface {
+	PublishTierChange(ctx context.Context, userID, tier string) error
+}
+
+// SessionRevoker is implemented by auth.Service. RevokeTokens calls it to
+// invalidate every active refresh-token family and bump TokenVersion in
+// one step — the same mechanism a self-service "log out everywhere"
+// already uses.
+type SessionRevoker interface {
+	LogoutAll(ctx context.Context, userID string) error
+}
+
+// ImpersonationIssuer is implemented by auth.Service. Impersonate calls
+// it to mint a short-lived access token carrying actorID as the "act"
+// claim, rather than duplicating JWT construction in this package.
+type ImpersonationIssuer interface {
+	CreateImpersonationToken(
+		ctx context.Context,
+		actorID string,
+		target *auth.UserInfo,
+	) (string, error)
+}
+
+// AuditLogger is implemented by core/audit.Logger. It's defined here as
+// an interface, the same way TierChangeNotifier is above, so this package
+// doesn't need to import core/audit just to record admin actions.
+type AuditLogger interface {
+	Log(
+		ctx context.Context,
+		actorID, targetID, action string,
+		before, after any,
+	) error
+}
+
 type Service struct {
-	repo Repository
+	repo          Repository
+	passkeys      PasskeyProvider
+	identityLinks IdentityLinksProvider
+	tierNotifier  TierChangeNotifier
+	sessions      SessionRevoker
+	impersonation ImpersonationIssuer
+	audit         AuditLogger
+	softDeleteCfg config.SoftDeleteConfig
+}
+
+// SetIdentityLinksProvider wires social-login link management in after
+// construction, breaking the auth<->user initialization cycle.
+func (s *Service) SetIdentityLinksProvider(provider IdentityLinksProvider) {
+	s.identityLinks = provider
+}
+
+// SetTierChangeNotifier wires quota invalidation in after construction, so
+// UpdateUserTier can tell every process's rate limiter to drop any cached
+// state for the user instead of enforcing their old tier until it expires.
+func (s *Service) SetTierChangeNotifier(notifier TierChangeNotifier) {
+	s.tierNotifier = notifier
+}
+
+// SetSessionRevoker wires session revocation in after construction,
+// breaking the auth<->user initialization cycle the same way
+// SetIdentityLinksProvider does.
+func (s *Service) SetSessionRevoker(revoker SessionRevoker) {
+	s.sessions = revoker
+}
+
+// SetImpersonationIssuer wires impersonation token issuance in after
+// construction, for the same import-cycle reason as SetSessionRevoker.
+func (s *Service) SetImpersonationIssuer(issuer ImpersonationIssuer) {
+	s.impersonation = issuer
+}
+
+// SetAuditLogger wires admin-action audit logging in after construction.
+// A Service without one simply skips recording — useful for tests and
+// for deployments that haven't provisioned the audit_log table.
+func (s *Service) SetAuditLogger(logger AuditLogger) {
+	s.audit = logger
+}
+
+// defaultGracePeriod is used whenever SetSoftDeleteConfig hasn't been
+// called, or the config it was called with leaves GracePeriod unset.
+const defaultGracePeriod = 7 * 24 * time.Hour
+
+// SetSoftDeleteConfig wires the grace period DeleteUser/DeleteMe soft-delete
+// under in after construction. A Service that never calls this still works,
+// falling back to defaultGracePeriod for every tier.
+func (s *Service) SetSoftDeleteConfig(cfg config.SoftDeleteConfig) {
+	s.softDeleteCfg = cfg
+}
+
+// gracePeriodForTier returns the tier-specific override in
+// softDeleteCfg.GracePeriods if one exists, else softDeleteCfg.GracePeriod,
+// falling back to defaultGracePeriod if that's unset too.
+func (s *Service) gracePeriodForTier(tier string) time.Duration {
+	if gp, ok := s.softDeleteCfg.GracePeriods[tier]; ok {
+		return gp
+	}
+	if s.softDeleteCfg.GracePeriod > 0 {
+		return s.softDeleteCfg.GracePeriod
+	}
+	return defaultGracePeriod
+}
+
+// recordAudit is best-effort: a logging failure shouldn't roll back or
+// fail the admin action it's describing, so errors are only logged.
+func (s *Service) recordAudit(
+	ctx context.Context,
+	actorID, targetID, action string,
+	before, after any,
+) {
+	if s.audit == nil {
+		return
+	}
+
+	if err := s.audit.Log(ctx, actorID, targetID, action, before, after); err != nil {
+		slog.Warn("audit log write failed",
+			"error", err,
+			"action", action,
+			"target_id", targetID,
+		)
+	}
+}
+
+func (s *Service) ListIdentityLinksForUser(
+	ctx context.Context,
+	userID string,
+) ([]auth.IdentityLink, error) {
+	if s.identityLinks == nil {
+		return nil, fmt.Errorf("list identity links: %w", core.ErrNotFound)
+	}
+
+	return s.identityLinks.ListIdentityLinks(ctx, userID)
+}
+
+func (s *Service) RevokeIdentityLink(
+	ctx context.Context,
+	userID, provider string,
+) error {
+	if s.identityLinks == nil {
+		return fmt.Errorf("revoke identity link: %w", core.ErrNotFound)
+	}
+
+	return s.identityLinks.UnlinkIdentity(ctx, userID, provider)
+}
+
+// SetPasskeyProvider wires passkey support in after construction, breaking
+// the auth<->user initialization cycle.
+func (s *Service) SetPasskeyProvider(provider PasskeyProvider) {
+	s.passkeys = provider
+}
+
+func (s *Service) ListPasskeys(
+	ctx context.Context,
+	userID string,
+) ([]auth.PasskeyCredential, error) {
+	if s.passkeys == nil {
+		return nil, fmt.Errorf("list passkeys: %w", core.ErrNotFound)
+	}
+
+	return s.passkeys.ListPasskeys(ctx, userID)
+}
+
+func (s *Service) RevokePasskey(
+	ctx context.Context,
+	userID, credentialID string,
+) error {
+	if s.passkeys == nil {
+		return fmt.Errorf("revoke passkey: %w", core.ErrNotFound)
+	}
+
+	return s.passkeys.RevokePasskey(ctx, userID, credentialID)
 }
 
 func NewService(repo Repository) *Service {
@@ -80,13 +273,41 @@ func (s *Service) UpdatePassword(
 	return s.repo.UpdatePassword(ctx, userID, passwordHash)
 }
 
+func (s *Service) SetTOTPSecret(
+	ctx context.Context,
+	userID, secret string,
+	recoveryCodeHashes []string,
+) error {
+	return s.repo.SetTOTPSecret(ctx, userID, secret, recoveryCodeHashes)
+}
+
+func (s *Service) EnableTOTP(ctx context.Context, userID string) error {
+	return s.repo.EnableTOTP(ctx, userID)
+}
+
+func (s *Service) DisableTOTP(ctx context.Context, userID string) error {
+	return s.repo.DisableTOTP(ctx, userID)
+}
+
+func (s *Service) UpdateRecoveryCodeHashes(ctx context.Context, userID string, hashes []string) error {
+	return s.repo.UpdateRecoveryCodeHashes(ctx, userID, hashes)
+}
+
+func (s *Service) SetEmailVerified(ctx context.Context, userID string) error {
+	return s.repo.SetEmailVerified(ctx, userID)
+}
+
 func (s *Service) GetUser(ctx context.Context, id string) (*User, error) {
 	return s.repo.GetByID(ctx, id)
 }
 
+// UpdateUser is shared by the self-service (actorID == id) and admin
+// (actorID is the admin's user ID) update paths, so an admin editing
+// someone else's profile is distinguishable in the audit log from a user
+// editing their own.
 func (s *Service) UpdateUser(
 	ctx context.Context,
-	id string,
+	actorID, id string,
 	req UpdateUserRequest,
 ) (*User, error) {
 	user, err := s.repo.GetByID(ctx, id)
@@ -94,6 +315,8 @@ func (s *Service) UpdateUser(
 		return nil, err
 	}
 
+	before := ToUserResponse(user)
+
 	if req.Name != nil {
 		user.Name = *req.Name
 	}
@@ -102,12 +325,14 @@ func (s *Service) UpdateUser(
 		return nil, err
 	}
 
+	s.recordAudit(ctx, actorID, id, "user.updated", before, ToUserResponse(user))
+
 	return user, nil
 }
 
 func (s *Service) UpdateUserRole(
 	ctx context.Context,
-	id, role string,
+	actorID, id, role string,
 ) (*User, error) {
 	if role != RoleUser && role != RoleAdmin {
 		return nil, fmt.Errorf(
@@ -122,18 +347,21 @@ func (s *Service) UpdateUserRole(
 		return nil, err
 	}
 
+	before := ToUserResponse(user)
 	user.Role = role
 
 	if err := s.repo.Update(ctx, user); err != nil {
 		return nil, err
 	}
 
+	s.recordAudit(ctx, actorID, id, "user.role_updated", before, ToUserResponse(user))
+
 	return user, nil
 }
 
 func (s *Service) UpdateUserTier(
 	ctx context.Context,
-	id, tier string,
+	actorID, id, tier string,
 ) (*User, error) {
 	if tier != TierFree && tier != TierPro && tier != TierEnterprise {
 		return nil, fmt.Errorf(
@@ -148,26 +376,214 @@ func (s *Service) UpdateUserTier(
 		return nil, err
 	}
 
+	before := ToUserResponse(user)
 	user.Tier = tier
 
 	if err := s.repo.Update(ctx, user); err != nil {
 		return nil, err
 	}
 
+	if s.tierNotifier != nil {
+		if notifyErr := s.tierNotifier.PublishTierChange(ctx, id, tier); notifyErr != nil {
+			slog.Warn("failed to publish tier change",
+				"error", notifyErr,
+				"user_id", id,
+			)
+		}
+	}
+
+	s.recordAudit(ctx, actorID, id, "user.tier_updated", before, ToUserResponse(user))
+
 	return user, nil
 }
 
-func (s *Service) DeleteUser(ctx context.Context, id string) error {
-	return s.repo.SoftDelete(ctx, id)
+func (s *Service) DeleteUser(ctx context.Context, actorID, id string) error {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.SoftDelete(ctx, id, s.gracePeriodForTier(user.Tier)); err != nil {
+		return err
+	}
+
+	s.revokeSessionsBestEffort(ctx, id)
+
+	s.recordAudit(ctx, actorID, id, "user.deleted", ToUserResponse(user), nil)
+
+	return nil
+}
+
+// RestoreUser undoes a soft delete while the user is still within its
+// grace period, for an admin reversing an accidental DeleteUser.
+func (s *Service) RestoreUser(ctx context.Context, actorID, id string) error {
+	if err := s.repo.RestoreSoftDeleted(ctx, id); err != nil {
+		return err
+	}
+
+	s.recordAudit(ctx, actorID, id, "user.restored", nil, nil)
+
+	return nil
+}
+
+// revokeSessionsBestEffort logs a soft-deleted user out of every active
+// session so their existing access and refresh tokens stop working
+// immediately, rather than waiting out their natural expiry. It's
+// best-effort, mirroring recordAudit: a revocation failure shouldn't
+// block the delete it's cleaning up after.
+func (s *Service) revokeSessionsBestEffort(ctx context.Context, id string) {
+	if s.sessions == nil {
+		return
+	}
+
+	if err := s.sessions.LogoutAll(ctx, id); err != nil {
+		slog.Warn("session revocation on delete failed",
+			"error", err,
+			"user_id", id,
+		)
+	}
+}
+
+// GrantPermission grants (or, for PermissionDeny, withholds) permission on
+// resourcePattern for id, replacing any existing grant for that exact
+// pattern. See Permission for how priority and the "/*" wildcard affect
+// resolution.
+func (s *Service) GrantPermission(
+	ctx context.Context,
+	actorID, id, resourcePattern, permission string,
+	priority int,
+) error {
+	if permission != PermissionRead && permission != PermissionWrite && permission != PermissionDeny {
+		return fmt.Errorf(
+			"grant permission: invalid permission %q: %w",
+			permission,
+			core.ErrInvalidInput,
+		)
+	}
+
+	// user_permissions has no rows-affected signal the way a plain UPDATE
+	// on users does, so existence is checked explicitly rather than left
+	// to an upsert that would otherwise succeed against a nonexistent id.
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.GrantPermission(ctx, id, resourcePattern, permission, priority); err != nil {
+		return err
+	}
+
+	s.recordAudit(ctx, actorID, id, "user.permission_granted", nil, map[string]any{
+		"resource_pattern": resourcePattern,
+		"permission":       permission,
+		"priority":         priority,
+	})
+
+	return nil
+}
+
+// RevokePermission removes id's grant for resourcePattern, if any.
+func (s *Service) RevokePermission(ctx context.Context, actorID, id, resourcePattern string) error {
+	if err := s.repo.RevokePermission(ctx, id, resourcePattern); err != nil {
+		return err
+	}
+
+	s.recordAudit(ctx, actorID, id, "user.permission_revoked", map[string]any{
+		"resource_pattern": resourcePattern,
+	}, nil)
+
+	return nil
+}
+
+// ResetPermissions clears every ACL grant for id, reverting it to its
+// role's default access.
+func (s *Service) ResetPermissions(ctx context.Context, actorID, id string) error {
+	if err := s.repo.ResetPermissions(ctx, id); err != nil {
+		return err
+	}
+
+	s.recordAudit(ctx, actorID, id, "user.permissions_reset", nil, nil)
+
+	return nil
+}
+
+func (s *Service) ListPermissions(ctx context.Context, id string) ([]Permission, error) {
+	return s.repo.ListPermissions(ctx, id)
+}
+
+// CheckPermission reports whether userID may perform action against
+// resource, per Repository.CheckPermission's resolution rules. It satisfies
+// middleware.PermissionChecker so *Service can be passed straight to
+// middleware.RequirePermission.
+func (s *Service) CheckPermission(
+	ctx context.Context,
+	userID, resource, action string,
+) (bool, error) {
+	return s.repo.CheckPermission(ctx, userID, resource, action)
+}
+
+// RevokeTokens invalidates every outstanding access and refresh token for
+// id: it revokes all refresh-token families and bumps TokenVersion so any
+// access token minted before this call fails Authenticator's version
+// check on its very next request, without waiting for it to expire.
+func (s *Service) RevokeTokens(ctx context.Context, actorID, id string) error {
+	if s.sessions == nil {
+		return fmt.Errorf("revoke tokens: %w", core.ErrNotFound)
+	}
+
+	if err := s.sessions.LogoutAll(ctx, id); err != nil {
+		return fmt.Errorf("revoke tokens: %w", err)
+	}
+
+	s.recordAudit(ctx, actorID, id, "user.tokens_revoked", nil, nil)
+
+	return nil
+}
+
+// Impersonate mints a short-lived access token for id bearing actorID as
+// its "act" claim, so an admin can act as the user for support purposes
+// while every request remains attributable to the admin in audit logs.
+func (s *Service) Impersonate(
+	ctx context.Context,
+	actorID, id string,
+) (string, error) {
+	if s.impersonation == nil {
+		return "", fmt.Errorf("impersonate: %w", core.ErrNotFound)
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := s.impersonation.CreateImpersonationToken(ctx, actorID, toUserInfo(user))
+	if err != nil {
+		return "", fmt.Errorf("impersonate: %w", err)
+	}
+
+	s.recordAudit(ctx, actorID, id, "user.impersonated", nil, nil)
+
+	return token, nil
 }
 
 func (s *Service) ListUsers(
 	ctx context.Context,
 	params ListUsersParams,
-) ([]User, int, error) {
+) (ListResult, error) {
 	return s.repo.List(ctx, params)
 }
 
+// StreamUsers is ListUsers without pagination: it walks every user matching
+// params, calling fn once per row, for callers like admin exports and bulk
+// email jobs that need the whole filtered set without loading it into
+// memory.
+func (s *Service) StreamUsers(
+	ctx context.Context,
+	params ListUsersParams,
+	fn func(User) error,
+) error {
+	return s.repo.Stream(ctx, params, fn)
+}
+
 func (s *Service) GetMe(ctx context.Context, userID string) (*User, error) {
 	if userID == "" {
 		return nil, fmt.Errorf("get me: %w", core.ErrUnauthorized)
@@ -190,7 +606,7 @@ func (s *Service) UpdateMe(
 		return nil, fmt.Errorf("update me: %w", core.ErrUnauthorized)
 	}
 
-	return s.UpdateUser(ctx, userID, req)
+	return s.UpdateUser(ctx, userID, userID, req)
 }
 
 func (s *Service) DeleteMe(ctx context.Context, userID string) error {
@@ -198,7 +614,18 @@ func (s *Service) DeleteMe(ctx context.Context, userID string) error {
 		return fmt.Errorf("delete me: %w", core.ErrUnauthorized)
 	}
 
-	return s.repo.SoftDelete(ctx, userID)
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.SoftDelete(ctx, userID, s.gracePeriodForTier(user.Tier)); err != nil {
+		return err
+	}
+
+	s.revokeSessionsBestEffort(ctx, userID)
+
+	return nil
 }
 
 func (s *Service) EmailExists(
@@ -243,13 +670,17 @@ func (s *Service) CanDeleteUser(
 
 func toUserInfo(u *User) *auth.UserInfo {
 	return &auth.UserInfo{
-		ID:           u.ID,
-		Email:        u.Email,
-		Name:         u.Name,
-		PasswordHash: u.PasswordHash,
-		Role:         u.Role,
-		Tier:         u.Tier,
-		TokenVersion: u.TokenVersion,
+		ID:                 u.ID,
+		Email:              u.Email,
+		Name:               u.Name,
+		PasswordHash:       u.PasswordHash,
+		Role:               u.Role,
+		Tier:               u.Tier,
+		TokenVersion:       u.TokenVersion,
+		TOTPSecret:         u.TOTPSecret,
+		TOTPEnabled:        u.TOTPEnabled,
+		RecoveryCodeHashes: u.RecoveryCodeHashes,
+		EmailVerified:      u.EmailVerified,
 	}
 }
 